@@ -2,26 +2,52 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/99designs/gqlgen/graphql"
-	"github.com/99designs/gqlgen/graphql/handler"
+	firebase "firebase.google.com/go/v4"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
-	"github.com/vektah/gqlparser/v2/gqlerror"
 
+	domainAI "github.com/motoya-k/tsundoc/internal/domain/ai"
+	domainDigest "github.com/motoya-k/tsundoc/internal/domain/digest"
 	"github.com/motoya-k/tsundoc/internal/infra/ai"
 	"github.com/motoya-k/tsundoc/internal/infra/config"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+	digestInfra "github.com/motoya-k/tsundoc/internal/infra/digest"
+	"github.com/motoya-k/tsundoc/internal/infra/markdown"
 	"github.com/motoya-k/tsundoc/internal/infra/repository"
+	"github.com/motoya-k/tsundoc/internal/infra/telemetry"
+	webhookInfra "github.com/motoya-k/tsundoc/internal/infra/webhook"
 	graphqlInterface "github.com/motoya-k/tsundoc/internal/interface/graphql"
 	"github.com/motoya-k/tsundoc/internal/interface/graphql/generated"
+	"github.com/motoya-k/tsundoc/internal/interface/health"
+	"github.com/motoya-k/tsundoc/internal/interface/rest"
+	"github.com/motoya-k/tsundoc/internal/interface/web"
+	authMiddleware "github.com/motoya-k/tsundoc/internal/middleware"
+	accessTokenUseCase "github.com/motoya-k/tsundoc/internal/usecase/accesstoken"
+	annotationUseCase "github.com/motoya-k/tsundoc/internal/usecase/annotation"
 	bookUseCase "github.com/motoya-k/tsundoc/internal/usecase/book"
+	digestUseCase "github.com/motoya-k/tsundoc/internal/usecase/digest"
+	linkUseCase "github.com/motoya-k/tsundoc/internal/usecase/link"
+	readingUseCase "github.com/motoya-k/tsundoc/internal/usecase/reading"
+	searchUseCase "github.com/motoya-k/tsundoc/internal/usecase/search"
+	shareUseCase "github.com/motoya-k/tsundoc/internal/usecase/share"
+	statsUseCase "github.com/motoya-k/tsundoc/internal/usecase/stats"
+	tagUseCase "github.com/motoya-k/tsundoc/internal/usecase/tag"
+	webhookUseCase "github.com/motoya-k/tsundoc/internal/usecase/webhook"
+	workspaceUseCase "github.com/motoya-k/tsundoc/internal/usecase/workspace"
+	"github.com/motoya-k/tsundoc/migrations"
 )
 
 func main() {
@@ -30,10 +56,28 @@ func main() {
 		log.Println("No .env file found")
 	}
 
+	migrateOnStart := flag.Bool("migrate", os.Getenv("MIGRATE_ON_START") == "true", "apply pending database migrations before starting")
+	flag.Parse()
+
 	// Setup logger
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
 
+	serverConfig, err := config.NewServerConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid server configuration")
+	}
+
+	// Setup metrics and tracing
+	metrics := telemetry.NewMetrics()
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), "tsundoc")
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to setup tracing")
+	}
+	if telemetry.TracingEnabled() {
+		logger.Info().Msg("OpenTelemetry tracing enabled")
+	}
+
 	// Setup database connection
 	dbConfig := config.NewDatabaseConfig()
 	db, err := config.NewGORMDB(dbConfig)
@@ -41,76 +85,301 @@ func main() {
 		logger.Fatal().Err(err).Msg("Failed to connect to database")
 	}
 	defer db.Close()
+	if err := db.Use(telemetry.NewGORMPlugin(metrics)); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to instrument database")
+	}
 
 	// Test database connection
 	if err := db.Ping(); err != nil {
 		logger.Fatal().Err(err).Msg("Failed to ping database")
 	}
-	logger.Info().Msg("Database connection established")
+	logger.Info().Str("dialect", string(db.Dialect())).Msg("Database connection established")
+
+	// Database migrations
+	migrationFS, err := migrations.For(string(db.Dialect()))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to load migrations")
+	}
+	migrator, err := database.NewMigrator(db, migrationFS)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to load migrations")
+	}
+
+	// `server migrate up|down|status` manages the schema and exits
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(context.Background(), migrator, flag.Args()[1:], os.Stdout); err != nil {
+			logger.Fatal().Err(err).Msg("Migration failed")
+		}
+		return
+	}
+
+	if *migrateOnStart {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to apply migrations")
+		}
+		logger.Info().Int("applied", len(applied)).Uint("version", migrator.Latest()).Msg("Database migrations applied")
+	}
+
+	// Refuse to serve against an older schema
+	if err := migrator.CheckVersion(context.Background()); err != nil {
+		logger.Fatal().Err(err).Msg("Database schema check failed; run `server migrate up` or start with -migrate")
+	}
 
 	// Setup AI service
-	var aiService ai.Service
+	var aiService domainAI.Service
 	openaiAPIKey := os.Getenv("OPENAI_API_KEY")
 	if openaiAPIKey != "" {
-		aiService = ai.NewOpenAIService(openaiAPIKey)
-		logger.Info().Msg("OpenAI service initialized")
+		aiConfig, err := config.NewAIConfig()
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Invalid AI configuration")
+		}
+		openaiService, err := ai.NewOpenAIService(openaiAPIKey, *aiConfig)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to load AI prompts")
+		}
+		aiService = ai.NewInstrumentedService(openaiService, "openai", metrics)
+		logger.Info().Str("prompts_dir", aiConfig.PromptsDir).Msg("OpenAI service initialized")
 	} else {
 		logger.Warn().Msg("OPENAI_API_KEY not set, AI features will be disabled")
 	}
 
 	// Setup dependencies
 	bookRepo := repository.NewBookRepository(db)
-	bookUC := bookUseCase.NewUseCase(bookRepo, aiService)
+	shareLinkRepo := repository.NewShareLinkRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	accessTokenRepo := repository.NewAccessTokenRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	annotationRepo := repository.NewAnnotationRepository(db)
+	linkRepo := repository.NewLinkRepository(db)
+	readingRepo := repository.NewReadingRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	digestRepo := repository.NewDigestRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	savedSearchRepo := repository.NewSavedSearchRepository(db)
+
+	// Webhook deliveries are sent from background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	webhookDispatcher := webhookInfra.NewDispatcher(webhookRepo)
+	webhookDispatcher.Start(workerCtx, 4)
+
+	renderer, err := markdown.NewCachedRenderer(markdown.NewRenderer(), markdown.DefaultCacheSize)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to set up markdown rendering")
+	}
+
+	bookUC := bookUseCase.NewUseCase(bookRepo, annotationRepo, db, aiService, webhookDispatcher, renderer, tagRepo)
+	shareUC := shareUseCase.NewUseCase(shareLinkRepo, bookRepo)
+	workspaceUC := workspaceUseCase.NewUseCase(workspaceRepo, db)
+	accessTokenUC := accessTokenUseCase.NewUseCase(accessTokenRepo)
+	webhookUC := webhookUseCase.NewUseCase(webhookRepo, webhookDispatcher)
+	annotationUC := annotationUseCase.NewUseCase(annotationRepo, bookRepo)
+	linkUC := linkUseCase.NewUseCase(linkRepo, bookRepo)
+	readingUC := readingUseCase.NewUseCase(readingRepo, bookRepo)
+	statsUC := statsUseCase.NewUseCase(statsRepo)
+	tagUC := tagUseCase.NewUseCase(tagRepo)
+	searchUC := searchUseCase.NewUseCase(savedSearchRepo, bookRepo, tagRepo)
+
+	// Digests can always be previewed; they are only sent when SMTP is set up
+	digestConfig, err := config.NewDigestConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid digest configuration")
+	}
+	digestRenderer, err := digestInfra.NewRenderer(digestConfig.AppURL)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to set up digest rendering")
+	}
+	var digestSender domainDigest.Sender
+	if digestConfig.Enabled() {
+		digestSender, err = digestInfra.NewSMTPSender(digestConfig.SMTPHost, digestConfig.SMTPPort, digestConfig.SMTPUsername, digestConfig.SMTPPassword, digestConfig.From)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Invalid SMTP configuration")
+		}
+	} else {
+		logger.Warn().Msg("SMTP_HOST not set, email digests will not be sent")
+	}
+	digestUC := digestUseCase.NewUseCase(digestRepo, bookRepo, readingRepo, savedSearchRepo, tagRepo, digestRenderer, digestSender)
+
+	var digestScheduler *digestInfra.Scheduler
+	if digestSender != nil {
+		digestScheduler = digestInfra.NewScheduler(digestUC, digestConfig.CheckInterval)
+		digestScheduler.Start(workerCtx)
+		logger.Info().Str("smtp_host", digestConfig.SMTPHost).Dur("interval", digestConfig.CheckInterval).Msg("Email digests enabled")
+	}
 
 	// Setup GraphQL resolver
 	resolver := &graphqlInterface.Resolver{
-		BookUseCase: bookUC,
+		BookUseCase:        bookUC,
+		ShareUseCase:       shareUC,
+		WorkspaceUseCase:   workspaceUC,
+		AccessTokenUseCase: accessTokenUC,
+		WebhookUseCase:     webhookUC,
+		AnnotationUseCase:  annotationUC,
+		LinkUseCase:        linkUC,
+		ReadingUseCase:     readingUC,
+		StatsUseCase:       statsUC,
+		DigestUseCase:      digestUC,
+		TagUseCase:         tagUC,
+		SearchUseCase:      searchUC,
 	}
 
 	// Setup router
 	r := chi.NewRouter()
 	
 	// Middleware
+	r.Use(telemetry.Middleware(metrics))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000", "https://tsundoc.app"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	// Firebase Auth middleware (commented out for now)
-	// r.Use(authMiddleware.FirebaseAuth)
+	// Setup Firebase (optional; ID tokens are only verified when credentials are configured)
+	var firebaseApp *firebase.App
+	if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" {
+		firebaseApp, err = firebase.NewApp(context.Background(), nil)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to initialize Firebase")
+		}
+	} else {
+		logger.Warn().Msg("GOOGLE_APPLICATION_CREDENTIALS not set, Firebase ID tokens will not be verified")
+	}
+
+	// Auth middleware: Firebase ID tokens and personal access tokens
+	auth, err := authMiddleware.NewAuthMiddleware(firebaseApp, accessTokenUC)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to setup auth middleware")
+	}
+	r.Use(auth.Middleware)
+
+	// Liveness and readiness probes
+	probes := health.NewHandler(serverConfig.ReadinessTimeout)
+	probes.AddCheck("database", db.PingContext)
+	if checker, ok := aiService.(domainAI.HealthChecker); ok && serverConfig.ReadinessCheckAI {
+		probes.AddCheck("ai", checker.Ping)
+	}
+	probes.Routes(r)
+
+	// Public share links (no authentication)
+	web.NewShareHandler(shareUC).Routes(r)
 
-	// Health check endpoint
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// REST API
+	r.Mount(rest.Prefix, rest.NewRouter(rest.NewBookHandler(bookUC, annotationUC)))
 
 	// GraphQL endpoint
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
-	
-	// Add error presenter to show actual errors during development
-	srv.SetErrorPresenter(func(ctx context.Context, e error) *gqlerror.Error {
-		logger.Error().Err(e).Msg("GraphQL error")
-		return graphql.DefaultErrorPresenter(ctx, e)
-	})
+	graphqlConfig, err := config.NewGraphQLConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid GraphQL configuration")
+	}
+	serverOptions := graphqlInterface.ServerOptions{
+		MaxDepth:      graphqlConfig.MaxDepth,
+		MaxComplexity: graphqlConfig.MaxComplexity,
+		APQCacheSize:  graphqlConfig.APQCacheSize,
+		Introspection: graphqlConfig.Development,
+	}
+	if graphqlConfig.AllowlistFile != "" {
+		serverOptions.Allowlist, err = graphqlInterface.LoadAllowlist(graphqlConfig.AllowlistFile)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to load GraphQL allowlist")
+		}
+		logger.Info().Int("documents", serverOptions.Allowlist.Len()).Msg("GraphQL allowlist mode enabled")
+	}
+	srv := graphqlInterface.NewServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}), serverOptions)
+	srv.AroundOperations(graphqlInterface.RequireTokenScopes)
+	srv.AroundOperations(resolver.WithLoaders)
+	srv.Use(graphqlInterface.NewTelemetry(metrics))
+
+	// Map domain errors to extensions.code; internal details are only shown in development
+	srv.SetErrorPresenter(graphqlInterface.NewErrorPresenter(graphqlConfig.Development))
 	
 	r.Handle("/graphql", srv)
-	r.Handle("/", playground.Handler("GraphQL playground", "/graphql"))
+	if graphqlConfig.Development {
+		r.Handle("/", playground.Handler("GraphQL playground", "/graphql"))
+	}
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	server := &http.Server{
+		Addr:              ":" + serverConfig.Port,
+		Handler:           r,
+		ReadHeaderTimeout: serverConfig.ReadHeaderTimeout,
+		ReadTimeout:       serverConfig.ReadTimeout,
+		WriteTimeout:      serverConfig.WriteTimeout,
+		IdleTimeout:       serverConfig.IdleTimeout,
 	}
 
-	logger.Info().Str("port", port).Msg("Starting server")
-	if err := http.ListenAndServe(":"+port, r); err != nil {
+	// Prometheus metrics are served on a separate address that is not exposed publicly
+	metricsRouter := chi.NewRouter()
+	metricsRouter.Handle("/metrics", metrics.Handler())
+	metricsServer := &http.Server{
+		Addr:              serverConfig.MetricsAddr,
+		Handler:           metricsRouter,
+		ReadHeaderTimeout: serverConfig.ReadHeaderTimeout,
+	}
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serverErr := make(chan error, 2)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	go func() {
+		serverErr <- metricsServer.ListenAndServe()
+	}()
+	logger.Info().
+		Str("port", serverConfig.Port).
+		Dur("read_timeout", serverConfig.ReadTimeout).
+		Dur("write_timeout", serverConfig.WriteTimeout).
+		Dur("idle_timeout", serverConfig.IdleTimeout).
+		Str("metrics_addr", serverConfig.MetricsAddr).
+		Msg("Server started")
+
+	select {
+	case err := <-serverErr:
 		logger.Fatal().Err(err).Msg("Server failed to start")
+	case <-signalCtx.Done():
+	}
+	stopSignals()
+
+	logger.Info().Dur("timeout", serverConfig.ShutdownTimeout).Msg("Shutdown signal received, draining")
+	started := time.Now()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+	defer cancel()
+
+	// Fail readiness first so that load balancers stop sending new requests
+	probes.Shutdown()
+
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error().Err(err).Msg("HTTP server did not drain in time")
+	} else {
+		logger.Info().Msg("HTTP server stopped")
 	}
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("Metrics server did not stop in time")
+	}
+
+	// Requests have finished, so no new events can be published
+	if err := webhookDispatcher.Shutdown(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("Webhook deliveries did not finish in time")
+	} else {
+		logger.Info().Msg("Webhook dispatcher drained")
+	}
+	if digestScheduler != nil {
+		if err := digestScheduler.Shutdown(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("Digests did not finish sending in time")
+		}
+	}
+	stopWorkers()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("Failed to flush traces")
+	}
+
+	logger.Info().Dur("took", time.Since(started)).Msg("Shutdown complete")
 }
\ No newline at end of file
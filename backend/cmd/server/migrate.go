@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+const migrateUsage = `Usage: server migrate <command>
+
+Commands:
+  up        Apply all pending migrations
+  down [N]  Revert the last N migrations (default 1)
+  status    Show the current schema version and pending migrations`
+
+// runMigrate implements the `server migrate` subcommand
+func runMigrate(ctx context.Context, migrator *database.Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n\n%s", migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(out, "Applied %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "No pending migrations")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: %q", args[1])
+			}
+			steps = n
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Fprintf(out, "Reverted %06d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		dirty := ""
+		if status.Dirty {
+			dirty = " (dirty)"
+		}
+		fmt.Fprintf(out, "Current version: %d%s\n", status.Version, dirty)
+		fmt.Fprintf(out, "Latest version:  %d\n", status.Latest)
+		for _, m := range status.Pending {
+			fmt.Fprintf(out, "Pending: %06d_%s\n", m.Version, m.Name)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n\n%s", args[0], migrateUsage)
+	}
+}
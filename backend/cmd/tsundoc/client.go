@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiPrefix matches rest.Prefix on the server
+const apiPrefix = "/api/v1"
+
+// Book mirrors the REST API book representation
+type Book struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Tags        []string `json:"tags"`
+	Content     string   `json:"content"`
+	URL         string   `json:"url,omitempty"`
+	WorkspaceID string   `json:"workspaceId,omitempty"`
+	Version     int      `json:"version"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+	// Annotations are only returned when requested
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation mirrors the REST API annotation representation
+type Annotation struct {
+	ID        string `json:"id"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	Quote     string `json:"quote"`
+	Color     string `json:"color"`
+	Note      string `json:"note,omitempty"`
+	Detached  bool   `json:"detached,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type createBookRequest struct {
+	Content     string   `json:"content"`
+	Title       string   `json:"title,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	WorkspaceID string   `json:"workspaceId,omitempty"`
+}
+
+type updateBookRequest struct {
+	Title           *string  `json:"title,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Content         *string  `json:"content,omitempty"`
+	ExpectedVersion *int     `json:"expectedVersion,omitempty"`
+}
+
+// APIError is an error response from the server
+type APIError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// Client talks to the tsundoc REST API
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(cfg *Config) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(cfg.Server, "/") + apiPrefix,
+		token:   cfg.Token,
+		http:    &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+func (c *Client) CreateBook(ctx context.Context, req createBookRequest) (*Book, error) {
+	var b Book
+	if err := c.do(ctx, http.MethodPost, "/books", req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListBooks lists books, with the user's annotations on each when
+// withAnnotations is set
+func (c *Client) ListBooks(ctx context.Context, keyword, workspaceID string, withAnnotations bool) ([]Book, error) {
+	query := url.Values{}
+	if keyword != "" {
+		query.Set("keyword", keyword)
+	}
+	if workspaceID != "" {
+		query.Set("workspaceId", workspaceID)
+	}
+	if withAnnotations {
+		query.Set("include", "annotations")
+	}
+
+	path := "/books"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var resp struct {
+		Books []Book `json:"books"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Books, nil
+}
+
+func (c *Client) GetBook(ctx context.Context, id string) (*Book, error) {
+	var b Book
+	if err := c.do(ctx, http.MethodGet, "/books/"+url.PathEscape(id), nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) UpdateBook(ctx context.Context, id string, req updateBookRequest) (*Book, error) {
+	var b Book
+	if err := c.do(ctx, http.MethodPatch, "/books/"+url.PathEscape(id), req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) MergeBooks(ctx context.Context, ids []string) (*Book, error) {
+	var b Book
+	req := struct {
+		BookIDs []string `json:"bookIds"`
+	}{BookIDs: ids}
+	if err := c.do(ctx, http.MethodPost, "/books/merge", req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var errResp struct {
+			Error APIError `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error.Message == "" {
+			return &APIError{Status: resp.StatusCode, Code: http.StatusText(resp.StatusCode), Message: "request failed"}
+		}
+		errResp.Error.Status = resp.StatusCode
+		return &errResp.Error
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxContentBytes matches the request body limit of the server
+const maxContentBytes = 10 << 20
+
+func runLogin(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	server := fs.String("server", "", "server URL (default: current server or "+defaultServer+")")
+	token := fs.String("token", "", "personal access token; read from stdin when omitted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+
+	cfg.Token = *token
+	if cfg.Token == "" {
+		fmt.Fprint(e.stderr, "Personal access token: ")
+		line, err := bufio.NewReader(e.stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		cfg.Token = strings.TrimSpace(line)
+	}
+	if cfg.Token == "" {
+		return fmt.Errorf("a token is required; create one with the createPersonalAccessToken mutation")
+	}
+
+	// Make sure the token works before storing it
+	if _, err := NewClient(cfg).ListBooks(ctx, "", "", false); err != nil {
+		return fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	path, err := saveConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "Logged in to %s (config saved to %s)\n", cfg.Server, path)
+	return nil
+}
+
+func runSave(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var tags stringList
+	title := fs.String("title", "", "book title (generated by the server when omitted)")
+	workspaceID := fs.String("workspace", "", "save into a workspace")
+	fs.Var(&tags, "tag", "tag to add (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	source := fs.Arg(0)
+	content, sourceURL, err := readSource(ctx, e.stdin, source)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("nothing to save: content is empty")
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	b, err := client.CreateBook(ctx, createBookRequest{
+		Content:     content,
+		Title:       *title,
+		Tags:        tags,
+		URL:         sourceURL,
+		WorkspaceID: *workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.printSaved(b)
+}
+
+// readSource reads content from stdin ("" or "-"), an http(s) URL or a file.
+// For URLs the URL is returned as well so it is stored with the book.
+func readSource(ctx context.Context, stdin io.Reader, source string) (string, string, error) {
+	switch {
+	case source == "" || source == "-":
+		data, err := io.ReadAll(io.LimitReader(stdin, maxContentBytes))
+		return string(data), "", err
+
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return "", "", err
+		}
+		resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to fetch %s: %w", source, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return "", "", fmt.Errorf("failed to fetch %s: %s", source, resp.Status)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxContentBytes))
+		return string(data), source, err
+
+	default:
+		data, err := os.ReadFile(source)
+		return string(data), "", err
+	}
+}
+
+func runList(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var tags stringList
+	keyword := fs.String("keyword", "", "only books matching the keyword")
+	workspaceID := fs.String("workspace", "", "list the books of a workspace")
+	fs.Var(&tags, "tag", "only books with this tag (repeatable; all must match)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+
+	books, err := client.ListBooks(ctx, *keyword, *workspaceID, false)
+	if err != nil {
+		return err
+	}
+
+	return p.books(filterByTags(books, tags))
+}
+
+// filterByTags keeps the books that have every tag, ignoring case
+func filterByTags(books []Book, tags []string) []Book {
+	if len(tags) == 0 {
+		return books
+	}
+
+	var result []Book
+	for _, b := range books {
+		has := make(map[string]bool, len(b.Tags))
+		for _, t := range b.Tags {
+			has[strings.ToLower(t)] = true
+		}
+
+		matches := true
+		for _, t := range tags {
+			if !has[strings.ToLower(t)] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+func runShow(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	contentOnly := fs.Bool("content", false, "print only the content, for piping")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+
+	b, err := client.GetBook(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *contentOnly {
+		_, err := io.WriteString(e.stdout, b.Content)
+		return err
+	}
+	return p.book(b)
+}
+
+func runEdit(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	b, err := client.GetBook(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	edited, err := e.editInEditor(ctx, b.Content)
+	if err != nil {
+		return err
+	}
+	if edited == b.Content {
+		fmt.Fprintln(e.stderr, "No changes")
+		return nil
+	}
+
+	// Refuse to overwrite changes saved elsewhere while the editor was open
+	updated, err := client.UpdateBook(ctx, b.ID, updateBookRequest{Content: &edited, ExpectedVersion: &b.Version})
+	if err != nil {
+		return err
+	}
+
+	return e.printSaved(updated)
+}
+
+// editInEditor opens content in $VISUAL or $EDITOR and returns the saved result
+func (e *env) editInEditor(ctx context.Context, content string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "tsundoc-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// EDITOR may include arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.CommandContext(ctx, parts[0], append(parts[1:], f.Name())...)
+	cmd.Stdin = e.stdin
+	cmd.Stdout = e.stdout
+	cmd.Stderr = e.stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func runMerge(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	b, err := client.MergeBooks(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	return e.printSaved(b)
+}
+
+func runExport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	format := fs.String("format", "json", "json (to stdout) or markdown (one file per book)")
+	dir := fs.String("dir", ".", "output directory for markdown files")
+	workspaceID := fs.String("workspace", "", "export the books of a workspace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	books, err := client.ListBooks(ctx, "", *workspaceID, true)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		p, err := newPrinter(e.stdout, outputJSON)
+		if err != nil {
+			return err
+		}
+		return p.books(books)
+
+	case "markdown":
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return err
+		}
+		for _, b := range books {
+			path := filepath.Join(*dir, exportFileName(b))
+			if err := os.WriteFile(path, renderMarkdown(b), 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+		}
+		fmt.Fprintf(e.stderr, "Exported %d books to %s\n", len(books), *dir)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown export format %q (want json or markdown)", *format)
+	}
+}
+
+var nonSlugChars = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// exportFileName builds a readable, unique file name from the title and ID
+func exportFileName(b Book) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(b.Title), "-"), "-")
+	slug = truncate(slug, 60)
+	id := b.ID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	if slug == "" {
+		return id + ".md"
+	}
+	return slug + "-" + id + ".md"
+}
+
+// renderMarkdown writes a book as Markdown with YAML front matter, followed
+// by its annotations as quotes with their notes
+func renderMarkdown(b Book) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	fmt.Fprintf(&buf, "id: %q\n", b.ID)
+	fmt.Fprintf(&buf, "title: %q\n", b.Title)
+	if len(b.Tags) > 0 {
+		buf.WriteString("tags:\n")
+		for _, t := range b.Tags {
+			fmt.Fprintf(&buf, "  - %q\n", t)
+		}
+	}
+	if b.URL != "" {
+		fmt.Fprintf(&buf, "url: %q\n", b.URL)
+	}
+	fmt.Fprintf(&buf, "created: %q\n", b.CreatedAt)
+	fmt.Fprintf(&buf, "updated: %q\n", b.UpdatedAt)
+	buf.WriteString("---\n\n")
+	buf.WriteString(strings.TrimRight(b.Content, "\n"))
+	buf.WriteString("\n")
+
+	if len(b.Annotations) > 0 {
+		buf.WriteString("\n## Annotations\n")
+		for _, a := range b.Annotations {
+			buf.WriteString("\n> ")
+			buf.WriteString(strings.ReplaceAll(strings.TrimRight(a.Quote, "\n"), "\n", "\n> "))
+			buf.WriteString("\n")
+			if a.Note != "" {
+				fmt.Fprintf(&buf, "\n%s\n", strings.TrimRight(a.Note, "\n"))
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// printSaved reports a created or updated book
+func (e *env) printSaved(b *Book) error {
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+	if p.mode == outputJSON {
+		return p.json(b)
+	}
+
+	_, err = fmt.Fprintf(e.stdout, "%s\t%s\n", b.ID, b.Title)
+	return err
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// Config is persisted by `tsundoc login`
+type Config struct {
+	Server string `json:"server"`
+	Token  string `json:"token"`
+}
+
+// configPath returns the location of the config file, honouring TSUNDOC_CONFIG
+func configPath() (string, error) {
+	if path := os.Getenv("TSUNDOC_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "tsundoc", "config.json"), nil
+}
+
+// loadConfig reads the config file. TSUNDOC_SERVER and TSUNDOC_TOKEN override
+// the stored values, which makes the CLI usable in CI without logging in.
+func loadConfig() (*Config, error) {
+	cfg := &Config{}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	if server := os.Getenv("TSUNDOC_SERVER"); server != "" {
+		cfg.Server = server
+	}
+	if token := os.Getenv("TSUNDOC_TOKEN"); token != "" {
+		cfg.Token = token
+	}
+	if cfg.Server == "" {
+		cfg.Server = defaultServer
+	}
+
+	return cfg, nil
+}
+
+// saveConfig writes the config file readable by the current user only, as it holds a token
+func saveConfig(cfg *Config) (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return path, nil
+}
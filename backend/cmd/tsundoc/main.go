@@ -0,0 +1,128 @@
+// Command tsundoc is a command-line client for the tsundoc server.
+//
+// Authenticate once with a personal access token:
+//
+//	tsundoc login --server https://api.tsundoc.app --token tsd_...
+//
+// then save, list, edit, merge and export books from the terminal.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	// run defines its flags on fs, parses args with it and executes the command
+	run func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error
+}
+
+// env carries what every command needs
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	output string
+}
+
+func (e *env) client() (*Client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(cfg), nil
+}
+
+func (e *env) printer() (*printer, error) {
+	return newPrinter(e.stdout, e.output)
+}
+
+var commands = []*command{
+	{name: "login", usage: "login [--server URL] [--token TOKEN]", summary: "Store the server URL and a personal access token", run: runLogin},
+	{name: "save", usage: "save [--title T] [--tag T]... [--workspace ID] [FILE|URL|-]", summary: "Save content from stdin, a file or a URL", run: runSave},
+	{name: "ls", usage: "ls [--keyword K] [--tag T]... [--workspace ID]", summary: "List books", run: runList},
+	{name: "show", usage: "show [--content] ID", summary: "Show a book", run: runShow},
+	{name: "edit", usage: "edit ID", summary: "Edit a book's content in $EDITOR", run: runEdit},
+	{name: "merge", usage: "merge ID ID...", summary: "Merge books into a new book", run: runMerge},
+	{name: "export", usage: "export [--format json|markdown] [--dir DIR] [--workspace ID]", summary: "Export all books", run: runExport},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	if err := run(ctx, e, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "tsundoc: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, e *env, args []string) error {
+	global := flag.NewFlagSet("tsundoc", flag.ContinueOnError)
+	global.SetOutput(e.stderr)
+	global.StringVar(&e.output, "o", outputTable, "output format: table or json")
+	global.Usage = func() { printUsage(e.stderr) }
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+
+	if global.NArg() == 0 {
+		printUsage(e.stderr)
+		return flag.ErrHelp
+	}
+
+	name := global.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(ctx, e, newFlagSet(e, cmd), global.Args()[1:])
+		}
+	}
+
+	printUsage(e.stderr)
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: tsundoc [-o table|json] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'tsundoc <command> -h' for command flags.")
+}
+
+// newFlagSet creates the flag set for a command
+func newFlagSet(e *env, cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: tsundoc %s\n\n%s\n", cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (s *stringList) String() string {
+	return fmt.Sprint(*s)
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer serves a minimal version of the REST API backed by a map
+func fakeServer(t *testing.T, books map[string]*Book) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/books", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer tsd_test", r.Header.Get("Authorization"))
+		resp := struct {
+			Books []*Book `json:"books"`
+		}{}
+		for _, b := range books {
+			if kw := r.URL.Query().Get("keyword"); kw == "" || strings.Contains(b.Content, kw) {
+				listed := *b
+				if r.URL.Query().Get("include") != "annotations" {
+					listed.Annotations = nil
+				}
+				resp.Books = append(resp.Books, &listed)
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("POST /api/v1/books", func(w http.ResponseWriter, r *http.Request) {
+		var req createBookRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		b := &Book{ID: "book-new", Title: req.Title, Tags: req.Tags, Content: req.Content}
+		books[b.ID] = b
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(b)
+	})
+	mux.HandleFunc("GET /api/v1/books/{id}", func(w http.ResponseWriter, r *http.Request) {
+		b, ok := books[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"book not found"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(b)
+	})
+	mux.HandleFunc("PATCH /api/v1/books/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req updateBookRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		b := books[r.PathValue("id")]
+		if req.ExpectedVersion != nil && *req.ExpectedVersion != b.Version {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":"CONFLICT","message":"book was modified by someone else"}}`))
+			return
+		}
+		b.Content = *req.Content
+		b.Version++
+		json.NewEncoder(w).Encode(b)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	t.Setenv("TSUNDOC_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("TSUNDOC_SERVER", server.URL)
+	t.Setenv("TSUNDOC_TOKEN", "tsd_test")
+	return server
+}
+
+func runCLI(t *testing.T, stdin string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	e := &env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}
+	err := run(context.Background(), e, args)
+	return stdout.String(), err
+}
+
+func TestSave_FromStdin(t *testing.T) {
+	books := map[string]*Book{}
+	fakeServer(t, books)
+
+	out, err := runCLI(t, "Notes from the terminal", "save", "--title", "Terminal", "--tag", "cli", "--tag", "go")
+
+	require.NoError(t, err)
+	assert.Equal(t, "book-new\tTerminal\n", out)
+	assert.Equal(t, "Notes from the terminal", books["book-new"].Content)
+	assert.Equal(t, []string{"cli", "go"}, books["book-new"].Tags)
+}
+
+func TestList_FiltersByTagAndPrintsJSON(t *testing.T) {
+	fakeServer(t, map[string]*Book{
+		"book-1": {ID: "book-1", Title: "Go", Tags: []string{"Go", "cli"}},
+		"book-2": {ID: "book-2", Title: "Rust", Tags: []string{"rust"}},
+	})
+
+	out, err := runCLI(t, "", "-o", "json", "ls", "--tag", "go")
+	require.NoError(t, err)
+
+	var books []Book
+	require.NoError(t, json.Unmarshal([]byte(out), &books))
+	require.Len(t, books, 1)
+	assert.Equal(t, "book-1", books[0].ID)
+}
+
+func TestShow_NotFound(t *testing.T) {
+	fakeServer(t, map[string]*Book{})
+
+	_, err := runCLI(t, "", "show", "missing")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	assert.Equal(t, "NOT_FOUND", apiErr.Code)
+}
+
+func TestEdit_UpdatesContent(t *testing.T) {
+	books := map[string]*Book{"book-1": {ID: "book-1", Title: "Draft", Content: "old text", Version: 1}}
+	fakeServer(t, books)
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "sed -i s/old/new/")
+
+	_, err := runCLI(t, "", "edit", "book-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "new text", books["book-1"].Content)
+	assert.Equal(t, 2, books["book-1"].Version)
+}
+
+func TestUpdateBook_ConflictingChange(t *testing.T) {
+	books := map[string]*Book{"book-1": {ID: "book-1", Title: "Draft", Content: "old text", Version: 1}}
+	fakeServer(t, books)
+
+	// Another client saves between reading and updating the book
+	e := &env{stdin: strings.NewReader(""), stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+	client, err := e.client()
+	require.NoError(t, err)
+	b, err := client.GetBook(context.Background(), "book-1")
+	require.NoError(t, err)
+	books["book-1"] = &Book{ID: "book-1", Title: "Draft", Content: "their text", Version: 2}
+
+	edited := "new text"
+	_, err = client.UpdateBook(context.Background(), b.ID, updateBookRequest{Content: &edited, ExpectedVersion: &b.Version})
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Status)
+	assert.Equal(t, "their text", books["book-1"].Content)
+}
+
+func TestExport_Markdown(t *testing.T) {
+	fakeServer(t, map[string]*Book{
+		"0123456789": {ID: "0123456789", Title: "Hello, World!", Tags: []string{"greeting"}, Content: "Hi"},
+	})
+	dir := t.TempDir()
+
+	_, err := runCLI(t, "", "export", "--format", "markdown", "--dir", dir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "hello-world-01234567.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "title: \"Hello, World!\"\n")
+	assert.Contains(t, string(data), "  - \"greeting\"\n")
+	assert.True(t, strings.HasSuffix(string(data), "---\n\nHi\n"))
+}
+
+func TestExport_MarkdownWithAnnotations(t *testing.T) {
+	fakeServer(t, map[string]*Book{
+		"0123456789": {ID: "0123456789", Title: "Notes", Content: "Hello, world", Annotations: []Annotation{
+			{ID: "ann-1", Start: 0, End: 5, Quote: "Hello", Color: "yellow"},
+			{ID: "ann-2", Start: 7, End: 12, Quote: "world", Color: "pink", Note: "planet"},
+		}},
+	})
+	dir := t.TempDir()
+
+	_, err := runCLI(t, "", "export", "--format", "markdown", "--dir", dir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "notes-01234567.md"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(data), "---\n\nHello, world\n\n## Annotations\n\n> Hello\n\n> world\n\nplanet\n"))
+}
+
+func TestLogin_SavesConfig(t *testing.T) {
+	server := fakeServer(t, map[string]*Book{})
+	t.Setenv("TSUNDOC_TOKEN", "")
+	t.Setenv("TSUNDOC_SERVER", "")
+
+	_, err := runCLI(t, "tsd_test\n", "login", "--server", server.URL)
+	require.NoError(t, err)
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, server.URL, cfg.Server)
+	assert.Equal(t, "tsd_test", cfg.Token)
+
+	path, err := configPath()
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer renders results in the output mode selected with -o
+type printer struct {
+	w    io.Writer
+	mode string
+}
+
+func newPrinter(w io.Writer, mode string) (*printer, error) {
+	if mode != outputTable && mode != outputJSON {
+		return nil, fmt.Errorf("unknown output mode %q (want %s or %s)", mode, outputTable, outputJSON)
+	}
+	return &printer{w: w, mode: mode}, nil
+}
+
+func (p *printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// books prints a list of books, one row per book in table mode
+func (p *printer) books(books []Book) error {
+	if p.mode == outputJSON {
+		if books == nil {
+			books = []Book{}
+		}
+		return p.json(books)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tTAGS\tUPDATED")
+	for _, b := range books {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", b.ID, truncate(b.Title, 60), strings.Join(b.Tags, ","), b.UpdatedAt)
+	}
+	return tw.Flush()
+}
+
+// book prints a single book with its content
+func (p *printer) book(b *Book) error {
+	if p.mode == outputJSON {
+		return p.json(b)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%s\n", b.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", b.Title)
+	fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(b.Tags, ", "))
+	if b.URL != "" {
+		fmt.Fprintf(tw, "URL:\t%s\n", b.URL)
+	}
+	if b.WorkspaceID != "" {
+		fmt.Fprintf(tw, "Workspace:\t%s\n", b.WorkspaceID)
+	}
+	fmt.Fprintf(tw, "Created:\t%s\n", b.CreatedAt)
+	fmt.Fprintf(tw, "Updated:\t%s\n", b.UpdatedAt)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(p.w, "\n%s\n", strings.TrimRight(b.Content, "\n"))
+	return err
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
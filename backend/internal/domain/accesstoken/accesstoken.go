@@ -0,0 +1,148 @@
+package accesstoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// ErrNotFound is returned when a token does not exist or belongs to another user
+var ErrNotFound = errs.NotFound("access token not found")
+
+// Prefix marks a bearer token as a personal access token
+const Prefix = "tsd_"
+
+// displayPrefixLen is how many characters of a token are kept for display
+const displayPrefixLen = len(Prefix) + 6
+
+// Scope limits what a personal access token can do
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	ScopeAI    Scope = "ai"
+)
+
+// IsValid reports whether the scope is one of the known scopes
+func (s Scope) IsValid() bool {
+	switch s {
+	case ScopeRead, ScopeWrite, ScopeAI:
+		return true
+	}
+	return false
+}
+
+// Token represents a personal access token. Only the hash of the secret is stored.
+type Token struct {
+	ID          string
+	UserID      string
+	Name        string
+	TokenPrefix string
+	Hash        string
+	Scopes      []Scope
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// NewToken creates a new token and returns it together with its plaintext secret,
+// which is shown to the user once and never stored
+func NewToken(userID, name string, scopes []Scope, expiresAt *time.Time) (*Token, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+	secret := Prefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return &Token{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Name:        name,
+		TokenPrefix: secret[:displayPrefixLen],
+		Hash:        Hash(secret),
+		Scopes:      scopes,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   time.Now(),
+	}, secret, nil
+}
+
+// Hash returns the stored representation of a token secret
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPersonalAccessToken reports whether a bearer token looks like a personal access token
+func IsPersonalAccessToken(bearer string) bool {
+	return strings.HasPrefix(bearer, Prefix)
+}
+
+// IsActive reports whether the token can be used at the given time
+func (t *Token) IsActive(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	if t.ExpiresAt != nil && !now.Before(*t.ExpiresAt) {
+		return false
+	}
+	return true
+}
+
+// HasScope reports whether the token was granted the scope
+func (t *Token) HasScope(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Repository defines the interface for access token persistence
+type Repository interface {
+	Save(ctx context.Context, token *Token) error
+	FindByHash(ctx context.Context, hash string) (*Token, error)
+	FindByUserID(ctx context.Context, userID string) ([]*Token, error)
+	Revoke(ctx context.Context, id, userID string) error
+	TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+type scopesKey struct{}
+
+// WithScopes marks the request as authenticated by a token with the given scopes
+func WithScopes(ctx context.Context, scopes []Scope) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// ScopesFromContext returns the scopes of the token that authenticated the request.
+// It reports false when the request was not authenticated by a personal access token.
+func ScopesFromContext(ctx context.Context) ([]Scope, bool) {
+	scopes, ok := ctx.Value(scopesKey{}).([]Scope)
+	return scopes, ok
+}
+
+// Allows reports whether the request may perform operations requiring the scope.
+// Requests not authenticated by a personal access token are not restricted.
+func Allows(ctx context.Context, scope Scope) bool {
+	scopes, ok := ScopesFromContext(ctx)
+	if !ok {
+		return true
+	}
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package accesstoken
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToken(t *testing.T) {
+	token, secret, err := NewToken("user-123", "cron", []Scope{ScopeRead}, nil)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(secret, Prefix))
+	assert.True(t, IsPersonalAccessToken(secret))
+	assert.True(t, strings.HasPrefix(secret, token.TokenPrefix))
+	assert.Equal(t, Hash(secret), token.Hash)
+	assert.NotContains(t, token.Hash, secret)
+	assert.Equal(t, "user-123", token.UserID)
+	assert.True(t, token.HasScope(ScopeRead))
+	assert.False(t, token.HasScope(ScopeWrite))
+}
+
+func TestToken_IsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.True(t, (&Token{}).IsActive(now))
+	assert.True(t, (&Token{ExpiresAt: &future}).IsActive(now))
+	assert.False(t, (&Token{ExpiresAt: &past}).IsActive(now))
+	assert.False(t, (&Token{RevokedAt: &past}).IsActive(now))
+}
+
+func TestAllows(t *testing.T) {
+	ctx := context.Background()
+
+	// Requests without a personal access token are not restricted
+	assert.True(t, Allows(ctx, ScopeWrite))
+
+	ctx = WithScopes(ctx, []Scope{ScopeRead, ScopeAI})
+	assert.True(t, Allows(ctx, ScopeRead))
+	assert.True(t, Allows(ctx, ScopeAI))
+	assert.False(t, Allows(ctx, ScopeWrite))
+}
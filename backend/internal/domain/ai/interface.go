@@ -2,17 +2,55 @@ package ai
 
 import "context"
 
+// Operation names a kind of AI call. Prompts and model settings are
+// configured per operation.
+type Operation string
+
+const (
+	OperationGenerateTitle    Operation = "generate_title"
+	OperationGenerateTags     Operation = "generate_tags"
+	OperationSummarizeContent Operation = "summarize_content"
+	OperationMergeContents    Operation = "merge_contents"
+)
+
+// Operations lists every operation
+var Operations = []Operation{
+	OperationGenerateTitle,
+	OperationGenerateTags,
+	OperationSummarizeContent,
+	OperationMergeContents,
+}
+
+// MaxExistingTags bounds the library tags passed to GenerateTags
+const MaxExistingTags = 100
+
 // Service defines the interface for AI-related operations
 type Service interface {
 	// GenerateTitle generates a title from the given content
 	GenerateTitle(ctx context.Context, content string) (string, error)
 	
-	// GenerateTags generates relevant tags from the given content
-	GenerateTags(ctx context.Context, content string) ([]string, error)
+	// GenerateTags suggests tags for the given content with their confidence,
+	// preferring the tags already used in the library. The tags are not
+	// normalized; see NormalizeTags.
+	GenerateTags(ctx context.Context, content string, existingTags []string) ([]Tag, error)
 	
 	// SummarizeContent creates a summary of the given content
 	SummarizeContent(ctx context.Context, content string) (string, error)
 	
 	// MergeContents intelligently merges multiple content pieces
 	MergeContents(ctx context.Context, contents []string) (string, error)
-}
\ No newline at end of file
+}
+
+// HealthChecker is implemented by services that can cheaply check whether the
+// provider is reachable
+type HealthChecker interface {
+	// Ping returns an error when the provider cannot serve requests
+	Ping(ctx context.Context) error
+}
+
+// PromptVersioner is implemented by services whose prompts are versioned, so
+// that generated output can be traced back to the prompt that produced it
+type PromptVersioner interface {
+	// PromptVersion returns the version of the prompt used for operation
+	PromptVersion(operation Operation) string
+}
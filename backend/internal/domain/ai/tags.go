@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxGeneratedTags bounds the tags kept from a GenerateTags call
+	MaxGeneratedTags = 5
+	// MinTagConfidence is the confidence below which generated tags are
+	// dropped
+	MinTagConfidence = 0.5
+)
+
+// Tag is a tag suggested for content
+type Tag struct {
+	Name string
+	// Confidence is how well the tag fits the content, from 0 to 1
+	Confidence float64
+}
+
+// TagNames returns the names of tags
+func TagNames(tags []Tag) []string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// NormalizeTags cleans up generated tags before they are saved. Whitespace is
+// collapsed and names are lowercased, except that a tag matching one of
+// existingTags, ignoring case, spaces, hyphens and underscores, takes the
+// existing spelling. Duplicates keep their highest confidence. Tags below
+// MinTagConfidence are dropped, and at most MaxGeneratedTags are returned,
+// most confident first.
+func NormalizeTags(tags []Tag, existingTags []string) []Tag {
+	vocabulary := make(map[string]string, len(existingTags))
+	for _, existing := range existingTags {
+		key := tagKey(existing)
+		if _, ok := vocabulary[key]; !ok && key != "" {
+			vocabulary[key] = existing
+		}
+	}
+
+	var result []Tag
+	index := map[string]int{}
+	for _, t := range tags {
+		name := strings.Join(strings.Fields(strings.TrimLeft(t.Name, "#")), " ")
+		key := tagKey(name)
+		if key == "" || t.Confidence < MinTagConfidence {
+			continue
+		}
+		if existing, ok := vocabulary[key]; ok {
+			name = existing
+		} else {
+			name = strings.ToLower(name)
+		}
+
+		if i, ok := index[key]; ok {
+			if t.Confidence > result[i].Confidence {
+				result[i].Confidence = t.Confidence
+			}
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, Tag{Name: name, Confidence: t.Confidence})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Confidence > result[j].Confidence
+	})
+	if len(result) > MaxGeneratedTags {
+		result = result[:MaxGeneratedTags]
+	}
+	return result
+}
+
+// tagKey identifies tags that differ only in case or separators, such as
+// "Machine Learning" and "machine-learning"
+func tagKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
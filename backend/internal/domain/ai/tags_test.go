@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []Tag
+		existing []string
+		want     []Tag
+	}{
+		{
+			name: "case and whitespace",
+			tags: []Tag{{Name: "  Machine   Learning ", Confidence: 0.9}, {Name: "#GraphQL", Confidence: 0.8}},
+			want: []Tag{{Name: "machine learning", Confidence: 0.9}, {Name: "graphql", Confidence: 0.8}},
+		},
+		{
+			name:     "existing spelling wins",
+			tags:     []Tag{{Name: "machine-learning", Confidence: 0.7}, {Name: "graphql", Confidence: 0.9}},
+			existing: []string{"GraphQL", "Machine Learning"},
+			want:     []Tag{{Name: "GraphQL", Confidence: 0.9}, {Name: "Machine Learning", Confidence: 0.7}},
+		},
+		{
+			name: "duplicates keep the highest confidence",
+			tags: []Tag{{Name: "Go", Confidence: 0.6}, {Name: "api", Confidence: 0.8}, {Name: "go", Confidence: 0.95}},
+			want: []Tag{{Name: "go", Confidence: 0.95}, {Name: "api", Confidence: 0.8}},
+		},
+		{
+			name: "low confidence and empty names are dropped",
+			tags: []Tag{{Name: "rust", Confidence: 0.2}, {Name: " ", Confidence: 0.9}, {Name: "go", Confidence: 0.5}},
+			want: []Tag{{Name: "go", Confidence: 0.5}},
+		},
+		{
+			name: "at most five",
+			tags: []Tag{
+				{Name: "a", Confidence: 0.6}, {Name: "b", Confidence: 0.7}, {Name: "c", Confidence: 0.8},
+				{Name: "d", Confidence: 0.9}, {Name: "e", Confidence: 0.9}, {Name: "f", Confidence: 1},
+			},
+			want: []Tag{
+				{Name: "f", Confidence: 1}, {Name: "d", Confidence: 0.9}, {Name: "e", Confidence: 0.9},
+				{Name: "c", Confidence: 0.8}, {Name: "b", Confidence: 0.7},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeTags(tt.tags, tt.existing))
+		})
+	}
+}
+
+func TestTagNames(t *testing.T) {
+	assert.Equal(t, []string{"go", "api"}, TagNames([]Tag{{Name: "go"}, {Name: "api"}}))
+	assert.Empty(t, TagNames(nil))
+}
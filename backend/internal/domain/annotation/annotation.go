@@ -0,0 +1,161 @@
+package annotation
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// ErrNotFound is returned when an annotation does not exist or belongs to another user
+var ErrNotFound = errs.NotFound("annotation not found")
+
+// Color is the highlight color of an annotation
+type Color string
+
+const (
+	ColorYellow Color = "yellow"
+	ColorGreen  Color = "green"
+	ColorBlue   Color = "blue"
+	ColorPink   Color = "pink"
+	ColorPurple Color = "purple"
+)
+
+// IsValid reports whether the color is one of the known colors
+func (c Color) IsValid() bool {
+	switch c {
+	case ColorYellow, ColorGreen, ColorBlue, ColorPink, ColorPurple:
+		return true
+	}
+	return false
+}
+
+// Annotation highlights a passage of a book's content, optionally with a
+// note. Annotations are private to the user who made them.
+type Annotation struct {
+	ID     string
+	BookID string
+	UserID string
+	// Start and End delimit the passage in characters (Unicode code points)
+	// of the content, End exclusive
+	Start int
+	End   int
+	// Quote is the highlighted text. It finds the passage again when the
+	// content changes.
+	Quote string
+	Color Color
+	Note  string
+	// Detached is set when the quote no longer appears in the content
+	Detached  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewAnnotation highlights content[start:end] of a book. An empty color
+// defaults to yellow.
+func NewAnnotation(userID, bookID, content string, start, end int, color Color, note string) (*Annotation, error) {
+	if color == "" {
+		color = ColorYellow
+	}
+	if !color.IsValid() {
+		return nil, errs.Validation("color", "unknown annotation color")
+	}
+
+	runes := []rune(content)
+	if start < 0 || start >= len(runes) {
+		return nil, errs.Validation("start", "start is outside the content")
+	}
+	if end <= start || end > len(runes) {
+		return nil, errs.Validation("end", "end must be after start and within the content")
+	}
+
+	now := time.Now()
+	return &Annotation{
+		ID:        uuid.New().String(),
+		BookID:    bookID,
+		UserID:    userID,
+		Start:     start,
+		End:       end,
+		Quote:     string(runes[start:end]),
+		Color:     color,
+		Note:      note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Reanchor moves the annotation to its quote in changed content. When the
+// quote occurs more than once, the occurrence closest to the old position
+// wins. When it no longer occurs the annotation is detached and keeps its
+// old offsets. Reanchor reports whether the annotation changed.
+func (a *Annotation) Reanchor(content string) bool {
+	runes := []rune(content)
+	if a.End <= len(runes) && string(runes[a.Start:a.End]) == a.Quote {
+		changed := a.Detached
+		a.Detached = false
+		return changed
+	}
+
+	best := -1
+	for _, start := range occurrences(content, a.Quote) {
+		if best < 0 || distance(start, a.Start) < distance(best, a.Start) {
+			best = start
+		}
+	}
+	if best < 0 {
+		changed := !a.Detached
+		a.Detached = true
+		return changed
+	}
+
+	a.Start = best
+	a.End = best + utf8.RuneCountInString(a.Quote)
+	a.Detached = false
+	return true
+}
+
+// occurrences returns the character offsets of every occurrence of quote in
+// content, including overlapping ones
+func occurrences(content, quote string) []int {
+	var offsets []int
+	pos, runePos := 0, 0
+	for {
+		i := strings.Index(content[pos:], quote)
+		if i < 0 {
+			return offsets
+		}
+		runePos += utf8.RuneCountInString(content[pos : pos+i])
+		offsets = append(offsets, runePos)
+
+		// Continue one character later
+		_, size := utf8.DecodeRuneInString(content[pos+i:])
+		pos += i + size
+		runePos++
+	}
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// Repository defines the interface for annotation persistence
+type Repository interface {
+	Save(ctx context.Context, a *Annotation) error
+	FindByID(ctx context.Context, id, userID string) (*Annotation, error)
+	// FindByBookIDs returns the user's annotations on each of the books,
+	// keyed by book ID and ordered by position
+	FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string][]*Annotation, error)
+	// FindAllByBookID returns the annotations of every user on a book
+	FindAllByBookID(ctx context.Context, bookID string) ([]*Annotation, error)
+	Update(ctx context.Context, a *Annotation) error
+	// UpdateAnchors saves the position of annotations after re-anchoring
+	UpdateAnchors(ctx context.Context, annotations []*Annotation) error
+	Delete(ctx context.Context, id, userID string) error
+}
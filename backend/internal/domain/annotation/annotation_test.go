@@ -0,0 +1,98 @@
+package annotation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+func TestNewAnnotation(t *testing.T) {
+	a, err := NewAnnotation("user-123", "book-123", "積読は楽しい。Reading is fun.", 0, 2, "", "a note")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, a.ID)
+	assert.Equal(t, "積読", a.Quote)
+	assert.Equal(t, ColorYellow, a.Color)
+	assert.Equal(t, "a note", a.Note)
+}
+
+func TestNewAnnotation_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		start int
+		end   int
+		color Color
+		field string
+	}{
+		{name: "negative start", start: -1, end: 2, field: "start"},
+		{name: "start past the content", start: 5, end: 6, field: "start"},
+		{name: "empty range", start: 2, end: 2, field: "end"},
+		{name: "end past the content", start: 0, end: 6, field: "end"},
+		{name: "unknown color", start: 0, end: 2, color: "orange", field: "color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAnnotation("user-123", "book-123", "Hello", tt.start, tt.end, tt.color, "")
+
+			require.True(t, errors.Is(err, errs.ErrValidation))
+			e, _ := errs.As(err)
+			assert.Equal(t, tt.field, e.Field)
+		})
+	}
+}
+
+func TestAnnotation_Reanchor(t *testing.T) {
+	newAnnotation := func(content string, start, end int) *Annotation {
+		a, err := NewAnnotation("user-123", "book-123", content, start, end, ColorGreen, "")
+		require.NoError(t, err)
+		return a
+	}
+
+	t.Run("unchanged passage", func(t *testing.T) {
+		a := newAnnotation("one two three", 4, 7)
+
+		assert.False(t, a.Reanchor("one two three four"))
+		assert.Equal(t, 4, a.Start)
+	})
+
+	t.Run("passage moved", func(t *testing.T) {
+		a := newAnnotation("one two three", 4, 7)
+
+		assert.True(t, a.Reanchor("zero one two three"))
+		assert.Equal(t, 9, a.Start)
+		assert.Equal(t, 12, a.End)
+	})
+
+	t.Run("closest occurrence wins", func(t *testing.T) {
+		a := newAnnotation("two, then two again", 10, 13)
+
+		assert.True(t, a.Reanchor("x two, then two again"))
+		assert.Equal(t, 12, a.Start)
+	})
+
+	t.Run("offsets count characters", func(t *testing.T) {
+		a := newAnnotation("今日は晴れ", 3, 5)
+
+		assert.True(t, a.Reanchor("昨日も今日も晴れ"))
+		assert.Equal(t, 6, a.Start)
+		assert.Equal(t, 8, a.End)
+	})
+
+	t.Run("passage removed", func(t *testing.T) {
+		a := newAnnotation("one two three", 4, 7)
+
+		assert.True(t, a.Reanchor("one three"))
+		assert.True(t, a.Detached)
+		assert.Equal(t, 4, a.Start)
+
+		// Detaching again is not a change, restoring the passage is
+		assert.False(t, a.Reanchor("one"))
+		assert.True(t, a.Reanchor("one two three"))
+		assert.False(t, a.Detached)
+	})
+}
@@ -2,9 +2,46 @@ package book
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+)
+
+// ErrNotFound is returned when a book does not exist or the user cannot read it
+var ErrNotFound = errs.NotFound("book not found")
+
+// errModified classifies ConflictError
+var errModified = errs.Conflict("book was modified by someone else")
+
+// ConflictError is returned when a book changed after the client read it.
+// Current is the stored book, so that the client can reconcile and retry.
+type ConflictError struct {
+	Current *Book
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("book %s was modified by someone else: now at version %d", e.Current.ID, e.Current.Version)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return errModified
+}
+
+// Enrichment is the outcome of asking AI to fill in a new book
+type Enrichment string
+
+const (
+	// EnrichmentSkipped means AI was not asked: it was disabled or nothing
+	// was left out
+	EnrichmentSkipped   Enrichment = ""
+	EnrichmentSucceeded Enrichment = "succeeded"
+	// EnrichmentFailed means at least one AI call failed and a fallback was
+	// used
+	EnrichmentFailed Enrichment = "failed"
 )
 
 // Book represents a book entity in the domain
@@ -17,28 +54,57 @@ type Book struct {
 	Content     string
 	URL         string
 	UserID      string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	WorkspaceID string
+	// Version starts at 1 and increases with every update
+	Version int
+	// Enrichment records whether AI filled in the title or tags that were
+	// left out when the book was created
+	Enrichment Enrichment
+	// MergedFrom is the number of books merged into this one, 0 when the book
+	// was not made by merging
+	MergedFrom int
+	// PromptVersions holds the version of the AI prompt that generated the
+	// title, tags or content, keyed by AI operation such as generate_title
+	PromptVersions map[string]string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // NewBook creates a new book instance
 func NewBook(userID, content string) *Book {
 	now := time.Now()
 	return &Book{
-		ID:        uuid.New().String(),
-		UserID:    userID,
-		Content:   content,
-		Tags:      []string{},
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		Content:        content,
+		Tags:           []string{},
+		Version:        1,
+		PromptVersions: map[string]string{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 }
 
-// Repository defines the interface for book persistence
+// Repository defines the interface for book persistence.
+// Books in a workspace are visible to all of its members, and can be
+// changed by members with write access; userID is the acting user.
 type Repository interface {
 	Save(ctx context.Context, book *Book) error
 	FindByID(ctx context.Context, id, userID string) (*Book, error)
+	// FindByIDs returns one entry per id, in the same order. The entry is nil
+	// when the book does not exist or the user cannot read it.
+	FindByIDs(ctx context.Context, ids []string, userID string) ([]*Book, error)
 	FindByUserID(ctx context.Context, userID string, keyword string) ([]*Book, error)
-	Update(ctx context.Context, book *Book) error
+	FindByWorkspaceID(ctx context.Context, workspaceID, userID string, keyword string) ([]*Book, error)
+	// FindTags returns up to limit tags used in the user's personal books, or
+	// in a workspace when workspaceID is set, most used first
+	FindTags(ctx context.Context, userID, workspaceID string, limit int) ([]string, error)
+	// Search returns the user's personal books, or a workspace's books when
+	// workspaceID is set, that match q, newest first. A limit of 0 returns
+	// every match.
+	Search(ctx context.Context, userID, workspaceID string, q *search.Query, limit int) ([]*Book, error)
+	// Update saves book if the stored version still equals book.Version and
+	// then increments book.Version. It returns a *ConflictError otherwise.
+	Update(ctx context.Context, book *Book, userID string) error
 	Delete(ctx context.Context, id, userID string) error
-}
\ No newline at end of file
+}
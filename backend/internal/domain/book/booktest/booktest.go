@@ -0,0 +1,81 @@
+// Package booktest provides a mock book.Repository for tests in other packages.
+package booktest
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+)
+
+// MockRepository implements book.Repository for testing
+type MockRepository struct {
+	mock.Mock
+}
+
+var _ book.Repository = (*MockRepository)(nil)
+
+func (m *MockRepository) Save(ctx context.Context, b *book.Book) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id, userID string) (*book.Book, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*book.Book), args.Error(1)
+}
+
+func (m *MockRepository) FindByIDs(ctx context.Context, ids []string, userID string) ([]*book.Book, error) {
+	args := m.Called(ctx, ids, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*book.Book), args.Error(1)
+}
+
+func (m *MockRepository) FindByUserID(ctx context.Context, userID, keyword string) ([]*book.Book, error) {
+	args := m.Called(ctx, userID, keyword)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*book.Book), args.Error(1)
+}
+
+func (m *MockRepository) FindByWorkspaceID(ctx context.Context, workspaceID, userID, keyword string) ([]*book.Book, error) {
+	args := m.Called(ctx, workspaceID, userID, keyword)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*book.Book), args.Error(1)
+}
+
+func (m *MockRepository) FindTags(ctx context.Context, userID, workspaceID string, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, workspaceID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepository) Search(ctx context.Context, userID, workspaceID string, q *search.Query, limit int) ([]*book.Book, error) {
+	args := m.Called(ctx, userID, workspaceID, q, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*book.Book), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, b *book.Book, userID string) error {
+	args := m.Called(ctx, b, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
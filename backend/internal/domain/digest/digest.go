@@ -0,0 +1,202 @@
+package digest
+
+import (
+	"context"
+	"net/mail"
+	"time"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+)
+
+// ErrNotFound is returned when a user has no digest settings
+var ErrNotFound = errs.NotFound("digest settings not found")
+
+const (
+	// SendHour is the local hour from which a due digest is sent. Weekly
+	// digests go out on Mondays.
+	SendHour = 8
+	// MaxNewBooks bounds the new books listed in a digest
+	MaxNewBooks = 20
+	// StalledAfter is how long a book can be left while reading before the
+	// digest reminds the user of it
+	StalledAfter = 7 * 24 * time.Hour
+	// ResurfaceAfter is how long a book must have been unread before the
+	// digest brings it up again
+	ResurfaceAfter = 30 * 24 * time.Hour
+	// MaxResurfaced bounds the old unread books brought up in a digest
+	MaxResurfaced = 3
+	// MaxShelfBooks bounds the books listed for each subscribed saved search
+	MaxShelfBooks = 10
+)
+
+// Frequency is how often a user receives the digest
+type Frequency string
+
+const (
+	FrequencyDaily  Frequency = "daily"
+	FrequencyWeekly Frequency = "weekly"
+)
+
+// IsValid reports whether the frequency is one of the known frequencies
+func (f Frequency) IsValid() bool {
+	return f == FrequencyDaily || f == FrequencyWeekly
+}
+
+// Settings are a user's digest preferences. Digests are opt-in.
+type Settings struct {
+	UserID    string
+	Enabled   bool
+	Email     string
+	Frequency Frequency
+	// Timezone is an IANA name such as Asia/Tokyo. It decides when the
+	// digest is sent.
+	Timezone   string
+	LastSentAt *time.Time
+	UpdatedAt  time.Time
+}
+
+// NewSettings returns the settings of a user who never opted in: disabled,
+// weekly, in UTC
+func NewSettings(userID string) *Settings {
+	return &Settings{
+		UserID:    userID,
+		Frequency: FrequencyWeekly,
+		Timezone:  "UTC",
+	}
+}
+
+// Validate checks the settings before they are saved
+func (s *Settings) Validate() error {
+	if !s.Frequency.IsValid() {
+		return errs.Validation("frequency", "unknown digest frequency")
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return errs.Validation("timezone", "unknown time zone")
+	}
+	if s.Email != "" {
+		if addr, err := mail.ParseAddress(s.Email); err != nil || addr.Address != s.Email {
+			return errs.Validation("email", "invalid email address")
+		}
+	}
+	if s.Enabled && s.Email == "" {
+		return errs.Validation("email", "an email address is required to receive the digest")
+	}
+	return nil
+}
+
+// Location returns the user's time zone, or UTC when it cannot be loaded
+func (s *Settings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// PeriodStart returns the latest time up to now at which a digest was
+// scheduled: SendHour today or the day before for a daily digest, SendHour
+// on the latest Monday for a weekly one, in the user's time zone
+func (s *Settings) PeriodStart(now time.Time) time.Time {
+	local := now.In(s.Location())
+	start := time.Date(local.Year(), local.Month(), local.Day(), SendHour, 0, 0, 0, local.Location())
+	if s.Frequency == FrequencyWeekly {
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+	if start.After(local) {
+		if s.Frequency == FrequencyWeekly {
+			start = start.AddDate(0, 0, -7)
+		} else {
+			start = start.AddDate(0, 0, -1)
+		}
+	}
+	return start
+}
+
+// Due reports whether a digest should be sent at now: the user opted in and
+// has not received one since the period started
+func (s *Settings) Due(now time.Time) bool {
+	if !s.Enabled || s.Email == "" {
+		return false
+	}
+	return s.LastSentAt == nil || s.LastSentAt.Before(s.PeriodStart(now))
+}
+
+// Since returns the start of what a digest sent at now covers: the last
+// digest, or one period back when none was sent
+func (s *Settings) Since(now time.Time) time.Time {
+	if s.LastSentAt != nil {
+		return *s.LastSentAt
+	}
+	if s.Frequency == FrequencyDaily {
+		return now.AddDate(0, 0, -1)
+	}
+	return now.AddDate(0, 0, -7)
+}
+
+// Digest lists what happened in a user's library between Since and Until,
+// which are in the user's time zone
+type Digest struct {
+	UserID string
+	Since  time.Time
+	Until  time.Time
+	// NewBooks were saved in the period, newest first
+	NewBooks []*book.Book
+	// Reminders are books the user started but left for StalledAfter
+	Reminders []*book.Book
+	// Resurfaced are books unread for ResurfaceAfter, to give them another
+	// chance
+	Resurfaced []*book.Book
+	// Shelves are the user's subscribed saved searches that books saved in
+	// the period match
+	Shelves []*Shelf
+}
+
+// Shelf lists the books saved in a digest period that match a saved search,
+// newest first
+type Shelf struct {
+	Search *search.SavedSearch
+	Books  []*book.Book
+}
+
+// IsEmpty reports whether the digest has nothing to tell
+func (d *Digest) IsEmpty() bool {
+	return len(d.NewBooks) == 0 && len(d.Reminders) == 0 && len(d.Resurfaced) == 0 && len(d.Shelves) == 0
+}
+
+// Email is a rendered digest
+type Email struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Preview is a digest and the email it renders to
+type Preview struct {
+	Digest *Digest
+	Email  *Email
+}
+
+// Renderer turns a digest into an email
+type Renderer interface {
+	Render(d *Digest, to string) (*Email, error)
+}
+
+// Sender delivers emails
+type Sender interface {
+	Send(ctx context.Context, email *Email) error
+}
+
+// Repository defines the interface for digest settings persistence
+type Repository interface {
+	// FindByUserID returns ErrNotFound when the user has no settings
+	FindByUserID(ctx context.Context, userID string) (*Settings, error)
+	// FindEnabled returns the settings of every user who opted in
+	FindEnabled(ctx context.Context) ([]*Settings, error)
+	// Save inserts the settings or replaces the user's settings
+	Save(ctx context.Context, s *Settings) error
+	// MarkSent records that a digest was sent to the user at sentAt
+	MarkSent(ctx context.Context, userID string, sentAt time.Time) error
+}
@@ -0,0 +1,104 @@
+package digest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+func TestSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(s *Settings)
+		field  string
+	}{
+		{name: "unknown frequency", change: func(s *Settings) { s.Frequency = "hourly" }, field: "frequency"},
+		{name: "unknown time zone", change: func(s *Settings) { s.Timezone = "Mars/Olympus" }, field: "timezone"},
+		{name: "empty time zone", change: func(s *Settings) { s.Timezone = "" }, field: "timezone"},
+		{name: "invalid email", change: func(s *Settings) { s.Email = "Reader <reader@example.com>" }, field: "email"},
+		{name: "enabled without email", change: func(s *Settings) { s.Enabled = true }, field: "email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSettings("user-123")
+			tt.change(s)
+
+			err := s.Validate()
+
+			require.True(t, errors.Is(err, errs.ErrValidation))
+			e, _ := errs.As(err)
+			assert.Equal(t, tt.field, e.Field)
+		})
+	}
+
+	s := NewSettings("user-123")
+	s.Enabled = true
+	s.Email = "reader@example.com"
+	s.Timezone = "Asia/Tokyo"
+	assert.NoError(t, s.Validate())
+}
+
+func TestSettings_Due(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	newSettings := func(frequency Frequency, lastSentAt *time.Time) *Settings {
+		return &Settings{
+			UserID:     "user-123",
+			Enabled:    true,
+			Email:      "reader@example.com",
+			Frequency:  frequency,
+			Timezone:   "Asia/Tokyo",
+			LastSentAt: lastSentAt,
+		}
+	}
+	at := func(day, hour int) time.Time {
+		// May 2024 starts on a Wednesday; the 13th is a Monday
+		return time.Date(2024, 5, day, hour, 0, 0, 0, tokyo)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	t.Run("weekly on Monday morning", func(t *testing.T) {
+		s := newSettings(FrequencyWeekly, ptr(at(6, 8)))
+
+		assert.False(t, s.Due(at(12, 23)))
+		assert.False(t, s.Due(at(13, 7)))
+		assert.True(t, s.Due(at(13, 8)))
+		assert.True(t, s.Due(at(15, 12)))
+		assert.Equal(t, at(13, 8), s.PeriodStart(at(15, 12)))
+	})
+
+	t.Run("daily", func(t *testing.T) {
+		s := newSettings(FrequencyDaily, ptr(at(14, 8)))
+
+		assert.False(t, s.Due(at(15, 7)))
+		assert.True(t, s.Due(at(15, 9)))
+	})
+
+	t.Run("the user's time zone decides", func(t *testing.T) {
+		s := newSettings(FrequencyDaily, ptr(at(14, 8)))
+
+		// 08:30 in Tokyo is 23:30 UTC the day before
+		assert.True(t, s.Due(at(15, 8).Add(30*time.Minute).UTC()))
+	})
+
+	t.Run("first digest", func(t *testing.T) {
+		s := newSettings(FrequencyWeekly, nil)
+
+		assert.True(t, s.Due(at(15, 12)))
+		assert.Equal(t, at(8, 12), s.Since(at(15, 12)))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newSettings(FrequencyDaily, nil)
+		s.Enabled = false
+
+		assert.False(t, s.Due(at(15, 12)))
+	})
+}
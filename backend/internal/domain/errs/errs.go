@@ -0,0 +1,80 @@
+// Package errs classifies domain errors so that the interface layer can map
+// them to GraphQL error codes and HTTP statuses without matching on messages.
+package errs
+
+import "errors"
+
+// Kinds of error. Check them with errors.Is; every *Error matches its kind
+// through any amount of fmt.Errorf("...: %w") wrapping.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrValidation    = errors.New("validation failed")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrConflict      = errors.New("conflict")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrAIUnavailable = errors.New("AI service unavailable")
+)
+
+// Error is a classified error. Message is safe to show to clients; Error()
+// also includes the cause for logs.
+type Error struct {
+	Kind    error
+	Message string
+	// Field names the invalid input of a validation error, as spelled in the API
+	Field string
+	// Err is the underlying cause, if any. It is not shown to clients.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// NotFound reports a missing resource, or one the user may not see
+func NotFound(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+// Validation reports invalid input in field
+func Validation(field, message string) error {
+	return &Error{Kind: ErrValidation, Message: message, Field: field}
+}
+
+// Unauthorized reports a missing user or a lack of permission
+func Unauthorized(message string) error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+// Conflict reports a request that clashes with the current state
+func Conflict(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+// QuotaExceeded reports that a usage limit was hit; err is the cause
+func QuotaExceeded(message string, err error) error {
+	return &Error{Kind: ErrQuotaExceeded, Message: message, Err: err}
+}
+
+// AIUnavailable reports that the AI provider failed; err is the cause
+func AIUnavailable(message string, err error) error {
+	return &Error{Kind: ErrAIUnavailable, Message: message, Err: err}
+}
+
+// As returns the classified error in err's chain, if any
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
@@ -0,0 +1,49 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_MatchesKindThroughWrapping(t *testing.T) {
+	err := fmt.Errorf("failed to get book: %w", NotFound("book not found"))
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrValidation)
+	assert.Equal(t, "failed to get book: book not found", err.Error())
+
+	e, ok := As(err)
+	require.True(t, ok)
+	assert.Equal(t, "book not found", e.Message)
+}
+
+func TestValidation_KeepsField(t *testing.T) {
+	err := fmt.Errorf("failed to save: %w", Validation("content", "content is required"))
+
+	e, ok := As(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, e.Kind)
+	assert.Equal(t, "content", e.Field)
+}
+
+func TestAIUnavailable_WrapsCause(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := AIUnavailable("failed to reach the AI provider", cause)
+
+	assert.ErrorIs(t, err, ErrAIUnavailable)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "failed to reach the AI provider: connection reset", err.Error())
+
+	e, ok := As(err)
+	require.True(t, ok)
+	assert.Equal(t, "failed to reach the AI provider", e.Message)
+}
+
+func TestAs_UnclassifiedError(t *testing.T) {
+	_, ok := As(errors.New("connection refused"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package link
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+)
+
+// maxTargetLength matches the longest title a book can have
+const maxTargetLength = 255
+
+var (
+	// wikiLink matches [[target]] and [[target|label]]
+	wikiLink = regexp.MustCompile(`\[\[([^\[\]|\n]+)(?:\|[^\[\]\n]*)?\]\]`)
+	// codeBlock and codeSpan match Markdown code, where brackets are not links
+	codeBlock = regexp.MustCompile("(?ms)^[ \t]*(```|~~~).*?^[ \t]*(```|~~~)[ \t]*$")
+	codeSpan  = regexp.MustCompile("`[^`\n]+`")
+)
+
+// Link is a wiki-style link from one book to another, written in the source
+// book's content as [[Title]] or [[id]]
+type Link struct {
+	SourceID string
+	// Target is the text between the brackets
+	Target string
+	// TargetID is the linked book, or empty when no book in the source's
+	// library matches the target
+	TargetID string
+	// Position orders the links of a book by first appearance
+	Position int
+}
+
+// Resolved reports whether the link points at a book
+func (l *Link) Resolved() bool {
+	return l.TargetID != ""
+}
+
+// Parse returns the targets of the links in content in order of first
+// appearance. Targets differing only in case count once, and links inside
+// code are ignored.
+func Parse(content string) []string {
+	content = codeBlock.ReplaceAllString(content, "")
+	content = codeSpan.ReplaceAllString(content, "")
+
+	var targets []string
+	seen := map[string]bool{}
+	for _, m := range wikiLink.FindAllStringSubmatch(content, -1) {
+		target := strings.Join(strings.Fields(m[1]), " ")
+		key := strings.ToLower(target)
+		if target == "" || utf8.RuneCountInString(target) > maxTargetLength || seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// Graph is a set of books and the links between them
+type Graph struct {
+	Nodes []*book.Book
+	Edges []*Edge
+}
+
+// Edge is a link between two books of a graph
+type Edge struct {
+	SourceID string
+	TargetID string
+}
+
+// Repository defines the interface for reading links. Links are written
+// together with the content of their source book.
+type Repository interface {
+	// FindBySourceIDs returns the links written in each book, keyed by book ID
+	// and ordered by position
+	FindBySourceIDs(ctx context.Context, sourceIDs []string) (map[string][]*Link, error)
+	// FindByTargetIDs returns the links pointing at each book, keyed by book ID
+	FindByTargetIDs(ctx context.Context, targetIDs []string) (map[string][]*Link, error)
+	// FindUnresolved returns the links matching no book in the user's personal
+	// library, or in a workspace's library when workspaceID is set
+	FindUnresolved(ctx context.Context, userID, workspaceID string) ([]*Link, error)
+}
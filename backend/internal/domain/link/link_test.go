@@ -0,0 +1,30 @@
+package link
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "no links", content: "Just text [with] brackets", want: nil},
+		{name: "title and id", content: "See [[Go Basics]] and [[6f1c0e2a-1111-4222-8333-944455556666]].", want: []string{"Go Basics", "6f1c0e2a-1111-4222-8333-944455556666"}},
+		{name: "label is not the target", content: "Read [[Go Basics|the basics]] first", want: []string{"Go Basics"}},
+		{name: "duplicates count once", content: "[[Go]] then [[go]] then [[Go]]", want: []string{"Go"}},
+		{name: "whitespace is normalized", content: "[[  Go   Basics ]]", want: []string{"Go Basics"}},
+		{name: "empty and unclosed links", content: "[[ ]] and [[open", want: nil},
+		{name: "links in code are ignored", content: "`[[inline]]`\n\n```sh\nif [[ -f x ]]; then\n```\n\n[[Real]]", want: []string{"Real"}},
+		{name: "non-ASCII titles", content: "[[積読のすすめ]]", want: []string{"積読のすすめ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.content))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package markdown
+
+import "context"
+
+// Heading is an entry of a document outline
+type Heading struct {
+	// Level is 1 for a top-level heading up to 6
+	Level int
+	Text  string
+	// Anchor is the id of the heading in the rendered HTML
+	Anchor string
+}
+
+// Document is Markdown content parsed into the forms clients display
+type Document struct {
+	// Outline lists the headings in document order
+	Outline []Heading
+	// HTML is sanitized and safe to insert into a page
+	HTML string
+	// PlainText is the text content without any markup
+	PlainText string
+}
+
+// Renderer parses Markdown content. Documents may be shared between callers
+// and must not be modified.
+type Renderer interface {
+	Render(ctx context.Context, source string) (*Document, error)
+}
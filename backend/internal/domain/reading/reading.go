@@ -0,0 +1,157 @@
+package reading
+
+import (
+	"context"
+	"math"
+	"time"
+	"unicode"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// Reading speeds used to estimate reading time. Text written without spaces
+// between words, such as Japanese, is counted in characters.
+const (
+	WordsPerMinute      = 200
+	CharactersPerMinute = 500
+)
+
+// Status is where a user stands with reading a book
+type Status string
+
+const (
+	StatusUnread    Status = "unread"
+	StatusReading   Status = "reading"
+	StatusRead      Status = "read"
+	StatusAbandoned Status = "abandoned"
+)
+
+// IsValid reports whether the status is one of the known statuses
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusUnread, StatusReading, StatusRead, StatusAbandoned:
+		return true
+	}
+	return false
+}
+
+// BacklogSort orders the backlog of unread books
+type BacklogSort string
+
+const (
+	// BacklogOldest puts the books that have waited longest first, and the
+	// quickest reads first among books added on the same day
+	BacklogOldest BacklogSort = "oldest"
+	// BacklogShortest puts the quickest reads first, and the oldest first
+	// among books of the same length
+	BacklogShortest BacklogSort = "shortest"
+)
+
+// State is the reading state of a book for one user. Every user of a shared
+// book has their own state.
+type State struct {
+	BookID string
+	UserID string
+	Status Status
+	// Progress is the percentage of the book read, from 0 to 100
+	Progress int
+	// StartedAt is when the user first started reading the book
+	StartedAt *time.Time
+	// FinishedAt is when the user read or abandoned the book
+	FinishedAt *time.Time
+	UpdatedAt  time.Time
+}
+
+// NewState returns the state of a book the user has not started
+func NewState(userID, bookID string) *State {
+	return &State{
+		BookID: bookID,
+		UserID: userID,
+		Status: StatusUnread,
+	}
+}
+
+// SetStatus moves the state to status at now. Progress is only accepted
+// while reading or abandoned and is kept when nil; a read book is at 100 and
+// an unread one at 0. Going back to unread clears the timestamps.
+func (s *State) SetStatus(status Status, progress *int, now time.Time) error {
+	if !status.IsValid() {
+		return errs.Validation("status", "unknown reading status")
+	}
+	if progress != nil {
+		if *progress < 0 || *progress > 100 {
+			return errs.Validation("progress", "progress must be between 0 and 100")
+		}
+		if status == StatusUnread || status == StatusRead {
+			return errs.Validation("progress", "progress can only be set while reading or abandoned")
+		}
+	}
+
+	switch status {
+	case StatusUnread:
+		s.Progress = 0
+		s.StartedAt = nil
+		s.FinishedAt = nil
+	case StatusReading:
+		if s.StartedAt == nil {
+			s.StartedAt = &now
+		}
+		s.FinishedAt = nil
+		if s.Status == StatusRead {
+			s.Progress = 0
+		}
+	case StatusRead:
+		s.Progress = 100
+		if s.StartedAt == nil {
+			s.StartedAt = &now
+		}
+		if s.Status != StatusRead {
+			s.FinishedAt = &now
+		}
+	case StatusAbandoned:
+		if s.StartedAt == nil {
+			s.StartedAt = &now
+		}
+		if s.Status != StatusAbandoned {
+			s.FinishedAt = &now
+		}
+	}
+	if progress != nil {
+		s.Progress = *progress
+	}
+
+	s.Status = status
+	s.UpdatedAt = now
+	return nil
+}
+
+// EstimateMinutes estimates how long reading content takes, rounded up to
+// whole minutes. Empty content takes no time.
+func EstimateMinutes(content string) int {
+	words, chars := 0, 0
+	inWord := false
+	for _, r := range content {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			chars++
+			inWord = false
+		case unicode.IsSpace(r) || unicode.IsPunct(r) && r != '\'' && r != '-':
+			inWord = false
+		case !inWord:
+			words++
+			inWord = true
+		}
+	}
+
+	minutes := float64(words)/WordsPerMinute + float64(chars)/CharactersPerMinute
+	return int(math.Ceil(minutes))
+}
+
+// Repository defines the interface for reading state persistence
+type Repository interface {
+	// Save inserts the state or replaces the user's state of the book
+	Save(ctx context.Context, state *State) error
+	// FindByBookIDs returns the user's states keyed by book ID. Books the user
+	// has no state for are missing from the map.
+	FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string]*State, error)
+}
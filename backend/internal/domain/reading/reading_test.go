@@ -0,0 +1,133 @@
+package reading
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestState_SetStatus(t *testing.T) {
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	t.Run("reading then read", func(t *testing.T) {
+		s := NewState("user-123", "book-123")
+
+		require.NoError(t, s.SetStatus(StatusReading, intPtr(30), day1))
+		assert.Equal(t, StatusReading, s.Status)
+		assert.Equal(t, 30, s.Progress)
+		assert.Equal(t, day1, *s.StartedAt)
+		assert.Nil(t, s.FinishedAt)
+
+		// Progress is kept when not given
+		require.NoError(t, s.SetStatus(StatusReading, nil, day2))
+		assert.Equal(t, 30, s.Progress)
+		assert.Equal(t, day1, *s.StartedAt)
+
+		require.NoError(t, s.SetStatus(StatusRead, nil, day3))
+		assert.Equal(t, 100, s.Progress)
+		assert.Equal(t, day1, *s.StartedAt)
+		assert.Equal(t, day3, *s.FinishedAt)
+		assert.Equal(t, day3, s.UpdatedAt)
+	})
+
+	t.Run("read straight away", func(t *testing.T) {
+		s := NewState("user-123", "book-123")
+
+		require.NoError(t, s.SetStatus(StatusRead, nil, day1))
+		assert.Equal(t, day1, *s.StartedAt)
+		assert.Equal(t, day1, *s.FinishedAt)
+
+		// Marking it read again keeps the finish date
+		require.NoError(t, s.SetStatus(StatusRead, nil, day2))
+		assert.Equal(t, day1, *s.FinishedAt)
+	})
+
+	t.Run("rereading", func(t *testing.T) {
+		s := NewState("user-123", "book-123")
+		require.NoError(t, s.SetStatus(StatusRead, nil, day1))
+
+		require.NoError(t, s.SetStatus(StatusReading, nil, day2))
+		assert.Equal(t, 0, s.Progress)
+		assert.Equal(t, day1, *s.StartedAt)
+		assert.Nil(t, s.FinishedAt)
+	})
+
+	t.Run("abandoned", func(t *testing.T) {
+		s := NewState("user-123", "book-123")
+		require.NoError(t, s.SetStatus(StatusReading, intPtr(40), day1))
+
+		require.NoError(t, s.SetStatus(StatusAbandoned, nil, day2))
+		assert.Equal(t, 40, s.Progress)
+		assert.Equal(t, day2, *s.FinishedAt)
+	})
+
+	t.Run("back to unread", func(t *testing.T) {
+		s := NewState("user-123", "book-123")
+		require.NoError(t, s.SetStatus(StatusReading, intPtr(40), day1))
+
+		require.NoError(t, s.SetStatus(StatusUnread, nil, day2))
+		assert.Equal(t, 0, s.Progress)
+		assert.Nil(t, s.StartedAt)
+		assert.Nil(t, s.FinishedAt)
+	})
+}
+
+func TestState_SetStatus_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   Status
+		progress *int
+		field    string
+	}{
+		{name: "unknown status", status: "skimmed", field: "status"},
+		{name: "negative progress", status: StatusReading, progress: intPtr(-1), field: "progress"},
+		{name: "progress over 100", status: StatusReading, progress: intPtr(101), field: "progress"},
+		{name: "progress on an unread book", status: StatusUnread, progress: intPtr(10), field: "progress"},
+		{name: "progress on a read book", status: StatusRead, progress: intPtr(100), field: "progress"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState("user-123", "book-123")
+			err := s.SetStatus(tt.status, tt.progress, time.Now())
+
+			require.True(t, errors.Is(err, errs.ErrValidation))
+			e, _ := errs.As(err)
+			assert.Equal(t, tt.field, e.Field)
+			assert.Equal(t, StatusUnread, s.Status)
+		})
+	}
+}
+
+func TestEstimateMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "empty", content: "", want: 0},
+		{name: "a few words", content: "The quick brown fox", want: 1},
+		{name: "words", content: strings.Repeat("word, ", 401), want: 3},
+		{name: "contractions are one word", content: strings.Repeat("don't ", 200), want: 1},
+		{name: "Japanese counts characters", content: strings.Repeat("積", 1000), want: 2},
+		{name: "mixed", content: strings.Repeat("積", 500) + strings.Repeat(" word", 200), want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EstimateMinutes(tt.content))
+		})
+	}
+}
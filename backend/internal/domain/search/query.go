@@ -0,0 +1,311 @@
+// Package search parses the book search query language, such as
+//
+//	tag:go -tag:draft created:>2025-01-01 status:unread "exact phrase"
+//
+// into a Query. A book matches a query when it matches every term. Terms are
+// separated by spaces and negated with a leading minus:
+//
+//   - word or "quoted phrase": the title, content or tags contain the text,
+//     ignoring case, or the user's annotation notes on the book do
+//   - tag:name: the book carries the tag or one of its descendants
+//   - status:unread|reading|read|abandoned: the user's reading status
+//   - created:DATE and updated:DATE, where DATE is YYYY-MM-DD in UTC and may
+//     be preceded by <, <=, > or >=
+//
+// Values containing spaces are quoted, as in tag:"machine learning".
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+)
+
+const (
+	// MaxQueryLength bounds the length of a query, in characters
+	MaxQueryLength = 500
+	// MaxTerms bounds the number of terms in a query
+	MaxTerms = 20
+)
+
+// dateLayout is how dates are written in queries
+const dateLayout = "2006-01-02"
+
+// Term is a condition on books: one of *Text, *Tag, *Status, *Date or *Not
+type Term interface {
+	term()
+}
+
+// Text matches books whose title, content or tags contain Text, ignoring
+// case, or that the user annotated with a note containing it
+type Text struct {
+	Text string
+}
+
+// Tag matches books carrying Tag or one of its descendants, ignoring case
+type Tag struct {
+	Tag string
+}
+
+// Status matches books by the searching user's reading status. Books the
+// user never set a status on are unread.
+type Status struct {
+	Status reading.Status
+}
+
+// DateField is the timestamp of a book compared by a Date term
+type DateField string
+
+const (
+	FieldCreated DateField = "created"
+	FieldUpdated DateField = "updated"
+)
+
+// Comparison is how a Date term compares a timestamp with its day
+type Comparison string
+
+const (
+	On         Comparison = ""
+	Before     Comparison = "<"
+	OnOrBefore Comparison = "<="
+	After      Comparison = ">"
+	OnOrAfter  Comparison = ">="
+)
+
+// Date matches books whose Field falls on, before or after the UTC day Day
+type Date struct {
+	Field      DateField
+	Comparison Comparison
+	Day        time.Time
+}
+
+// Range returns the times matching the term as the half-open interval
+// [from, to). A zero from or to leaves that side open.
+func (d *Date) Range() (from, to time.Time) {
+	next := d.Day.AddDate(0, 0, 1)
+	switch d.Comparison {
+	case Before:
+		return time.Time{}, d.Day
+	case OnOrBefore:
+		return time.Time{}, next
+	case After:
+		return next, time.Time{}
+	case OnOrAfter:
+		return d.Day, time.Time{}
+	default:
+		return d.Day, next
+	}
+}
+
+// Not matches the books that Term does not match
+type Not struct {
+	Term Term
+}
+
+func (*Text) term()   {}
+func (*Tag) term()    {}
+func (*Status) term() {}
+func (*Date) term()   {}
+func (*Not) term()    {}
+
+// Query is a parsed search. An empty query matches every book.
+type Query struct {
+	Terms []Term
+}
+
+// ResolveTags returns a copy of the query with every tag replaced by
+// resolve(tag), such as the tag a synonym stands for
+func (q *Query) ResolveTags(resolve func(string) string) *Query {
+	var mapTerm func(Term) Term
+	mapTerm = func(t Term) Term {
+		switch t := t.(type) {
+		case *Tag:
+			return &Tag{Tag: resolve(t.Tag)}
+		case *Not:
+			return &Not{Term: mapTerm(t.Term)}
+		default:
+			return t
+		}
+	}
+
+	resolved := &Query{Terms: make([]Term, len(q.Terms))}
+	for i, t := range q.Terms {
+		resolved.Terms[i] = mapTerm(t)
+	}
+	return resolved
+}
+
+// Parse parses a query. Errors are validation errors on the query field that
+// point at the offending term.
+func Parse(input string) (*Query, error) {
+	if !utf8.ValidString(input) {
+		return nil, invalid("query is not valid UTF-8")
+	}
+	if utf8.RuneCountInString(input) > MaxQueryLength {
+		return nil, invalid("query is longer than %d characters", MaxQueryLength)
+	}
+
+	p := &parser{input: input}
+	q := &Query{Terms: []Term{}}
+	for {
+		p.skipSpaces()
+		if p.done() {
+			return q, nil
+		}
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if len(q.Terms) == MaxTerms {
+			return nil, invalid("query has more than %d terms", MaxTerms)
+		}
+		q.Terms = append(q.Terms, t)
+	}
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+	return r
+}
+
+// advance moves past the next rune
+func (p *parser) advance() {
+	_, size := utf8.DecodeRuneInString(p.input[p.pos:])
+	p.pos += size
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		p.advance()
+	}
+}
+
+// word reads up to the next space
+func (p *parser) word() string {
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.peek()) {
+		p.advance()
+	}
+	return p.input[start:p.pos]
+}
+
+// quoted reads a "quoted" value, the opening quote being next
+func (p *parser) quoted() (string, error) {
+	end := strings.IndexByte(p.input[p.pos+1:], '"')
+	if end < 0 {
+		return "", invalid("missing closing quote in %s", p.input[p.pos:])
+	}
+	value := p.input[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+// value reads the value of a field, quoted or up to the next space
+func (p *parser) value() (string, error) {
+	if !p.done() && p.peek() == '"' {
+		return p.quoted()
+	}
+	return p.word(), nil
+}
+
+func (p *parser) term() (Term, error) {
+	// A minus negates the term it is attached to
+	if p.peek() == '-' {
+		if next, _ := utf8.DecodeRuneInString(p.input[p.pos+1:]); next != utf8.RuneError && !unicode.IsSpace(next) {
+			p.pos++
+			t, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			return &Not{Term: t}, nil
+		}
+	}
+
+	if p.peek() == '"' {
+		text, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" {
+			return nil, invalid(`empty phrase ""`)
+		}
+		return &Text{Text: text}, nil
+	}
+
+	start := p.pos
+	word := p.word()
+	field, _, ok := strings.Cut(word, ":")
+	if !ok || !isField(field) {
+		return &Text{Text: word}, nil
+	}
+
+	// Read the value again, as it may be quoted
+	p.pos = start + len(field) + 1
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	return fieldTerm(strings.ToLower(field), strings.TrimSpace(value))
+}
+
+func isField(field string) bool {
+	switch strings.ToLower(field) {
+	case "tag", "status", string(FieldCreated), string(FieldUpdated):
+		return true
+	}
+	return false
+}
+
+func fieldTerm(field, value string) (Term, error) {
+	if value == "" {
+		return nil, invalid("%s: needs a value", field)
+	}
+
+	switch field {
+	case "tag":
+		return &Tag{Tag: value}, nil
+	case "status":
+		status := reading.Status(strings.ToLower(value))
+		if !status.IsValid() {
+			return nil, invalid("unknown status in status:%s, use unread, reading, read or abandoned", value)
+		}
+		return &Status{Status: status}, nil
+	default:
+		return parseDate(DateField(field), value)
+	}
+}
+
+func parseDate(field DateField, value string) (*Date, error) {
+	comparison := On
+	for _, c := range []Comparison{OnOrBefore, OnOrAfter, Before, After} {
+		if strings.HasPrefix(value, string(c)) {
+			comparison = c
+			break
+		}
+	}
+
+	day, err := time.Parse(dateLayout, strings.TrimPrefix(value, string(comparison)))
+	if err != nil {
+		return nil, invalid("invalid date in %s:%s, use YYYY-MM-DD", field, value)
+	}
+	return &Date{Field: field, Comparison: comparison, Day: day}, nil
+}
+
+func invalid(format string, args ...interface{}) error {
+	return errs.Validation("query", fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+)
+
+func TestParse(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input string
+		want  []Term
+	}{
+		{name: "empty", input: "  ", want: []Term{}},
+		{
+			name:  "every kind of term",
+			input: `tag:go -tag:draft created:>2025-01-01 status:unread "exact  phrase"`,
+			want: []Term{
+				&Tag{Tag: "go"},
+				&Not{Term: &Tag{Tag: "draft"}},
+				&Date{Field: FieldCreated, Comparison: After, Day: day},
+				&Status{Status: reading.StatusUnread},
+				&Text{Text: "exact phrase"},
+			},
+		},
+		{name: "words", input: "graphql  schema", want: []Term{&Text{Text: "graphql"}, &Text{Text: "schema"}}},
+		{name: "quoted value", input: `tag:"machine learning"`, want: []Term{&Tag{Tag: "machine learning"}}},
+		{name: "fields ignore case", input: "TAG:Go Status:READ", want: []Term{&Tag{Tag: "Go"}, &Status{Status: reading.StatusRead}}},
+		{name: "unknown fields are text", input: "https://go.dev c++:", want: []Term{&Text{Text: "https://go.dev"}, &Text{Text: "c++:"}}},
+		{name: "negated phrase", input: `-"draft notes"`, want: []Term{&Not{Term: &Text{Text: "draft notes"}}}},
+		{name: "lone minus is text", input: "a - b", want: []Term{&Text{Text: "a"}, &Text{Text: "-"}, &Text{Text: "b"}}},
+		{
+			name:  "date comparisons",
+			input: "updated:<=2025-01-01 created:2025-01-01",
+			want: []Term{
+				&Date{Field: FieldUpdated, Comparison: OnOrBefore, Day: day},
+				&Date{Field: FieldCreated, Comparison: On, Day: day},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, q.Terms)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "unclosed quote", input: `tag:go "exact phrase`, want: "missing closing quote"},
+		{name: "empty phrase", input: `""`, want: "empty phrase"},
+		{name: "missing value", input: "tag: go", want: "tag: needs a value"},
+		{name: "unknown status", input: "status:done", want: "unknown status in status:done"},
+		{name: "invalid date", input: "created:>2025-13-01", want: "invalid date in created:>2025-13-01"},
+		{name: "too many terms", input: strings.Repeat("go ", MaxTerms+1), want: "more than 20 terms"},
+		{name: "too long", input: strings.Repeat("a", MaxQueryLength+1), want: "longer than 500 characters"},
+		{name: "invalid UTF-8", input: "\xff", want: "not valid UTF-8"},
+		{name: "invalid UTF-8 in a word", input: "a\xffb", want: "not valid UTF-8"},
+		{name: "invalid UTF-8 in a value", input: "tag:\xff", want: "not valid UTF-8"},
+		{name: "invalid UTF-8 after a minus", input: "-\xff", want: "not valid UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			require.ErrorIs(t, err, errs.ErrValidation)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestDate_Range(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	next := day.AddDate(0, 0, 1)
+
+	tests := []struct {
+		comparison Comparison
+		from, to   time.Time
+	}{
+		{comparison: On, from: day, to: next},
+		{comparison: Before, to: day},
+		{comparison: OnOrBefore, to: next},
+		{comparison: After, from: next},
+		{comparison: OnOrAfter, from: day},
+	}
+
+	for _, tt := range tests {
+		from, to := (&Date{Field: FieldCreated, Comparison: tt.comparison, Day: day}).Range()
+		assert.Equal(t, tt.from, from, "%q", tt.comparison)
+		assert.Equal(t, tt.to, to, "%q", tt.comparison)
+	}
+}
+
+func TestQuery_ResolveTags(t *testing.T) {
+	q, err := Parse("golang -tag:golang tag:rust")
+	require.NoError(t, err)
+
+	resolved := q.ResolveTags(func(tag string) string {
+		if tag == "golang" {
+			return "lang/go"
+		}
+		return tag
+	})
+
+	assert.Equal(t, []Term{
+		&Text{Text: "golang"},
+		&Not{Term: &Tag{Tag: "lang/go"}},
+		&Tag{Tag: "rust"},
+	}, resolved.Terms)
+	// The query itself is left alone
+	assert.Equal(t, &Not{Term: &Tag{Tag: "golang"}}, q.Terms[1])
+}
+
+func TestNewSavedSearch(t *testing.T) {
+	s, err := NewSavedSearch("user-123", "", "  Go backlog ", " tag:go status:unread ")
+	require.NoError(t, err)
+	assert.Equal(t, "Go backlog", s.Name)
+	assert.Equal(t, "tag:go status:unread", s.Query)
+	assert.NotEmpty(t, s.ID)
+
+	_, err = NewSavedSearch("user-123", "", " ", "tag:go")
+	assert.ErrorIs(t, err, errs.ErrValidation)
+	_, err = NewSavedSearch("user-123", "", strings.Repeat("a", MaxNameLength+1), "tag:go")
+	assert.ErrorIs(t, err, errs.ErrValidation)
+	_, err = NewSavedSearch("user-123", "", "Broken", "status:done")
+	assert.ErrorIs(t, err, errs.ErrValidation)
+}
@@ -0,0 +1,80 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// MaxNameLength bounds the name of a saved search, in characters
+const MaxNameLength = 100
+
+// ErrNotFound is returned when a saved search does not exist or belongs to
+// another user
+var ErrNotFound = errs.NotFound("saved search not found")
+
+// SavedSearch is a named query, shown as a smart shelf of the books matching
+// it. Saved searches are private to the user who made them.
+type SavedSearch struct {
+	ID     string
+	UserID string
+	// WorkspaceID is the workspace searched, or empty for the user's
+	// personal books
+	WorkspaceID string
+	Name        string
+	Query       string
+	// Pinned searches are listed first
+	Pinned bool
+	// Subscribed searches list the matching books saved since the last
+	// digest in the user's email digest
+	Subscribed bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewSavedSearch creates a saved search of the user's personal books, or of
+// a workspace when workspaceID is set
+func NewSavedSearch(userID, workspaceID, name, query string) (*SavedSearch, error) {
+	now := time.Now()
+	s := &SavedSearch{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Name:        strings.TrimSpace(name),
+		Query:       strings.TrimSpace(query),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks the saved search before it is saved
+func (s *SavedSearch) Validate() error {
+	if s.Name == "" {
+		return errs.Validation("name", "name is required")
+	}
+	if utf8.RuneCountInString(s.Name) > MaxNameLength {
+		return errs.Validation("name", "name is too long")
+	}
+	_, err := Parse(s.Query)
+	return err
+}
+
+// Repository defines the interface for saved search persistence
+type Repository interface {
+	Save(ctx context.Context, s *SavedSearch) error
+	FindByID(ctx context.Context, id, userID string) (*SavedSearch, error)
+	// FindByUserID returns the user's saved searches, pinned ones first, then
+	// by name
+	FindByUserID(ctx context.Context, userID string) ([]*SavedSearch, error)
+	Update(ctx context.Context, s *SavedSearch) error
+	Delete(ctx context.Context, id, userID string) error
+}
@@ -0,0 +1,113 @@
+package share
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+var (
+	// ErrNotFound is returned for unknown share tokens
+	ErrNotFound = errs.NotFound("share link not found")
+	// ErrLinkInactive is returned when a link has expired or was revoked
+	ErrLinkInactive = errs.NotFound("share link is no longer active")
+	// ErrPasswordRequired is returned when a protected link is opened without the right password
+	ErrPasswordRequired = errs.Unauthorized("share link password required")
+)
+
+// tokenBytes is the amount of randomness in a share token (256 bits)
+const tokenBytes = 32
+
+// Link represents a public, read-only link to a book
+type Link struct {
+	ID           string
+	Token        string
+	BookID       string
+	UserID       string
+	PasswordHash string
+	ExpiresAt    *time.Time
+	RevokedAt    *time.Time
+	ViewCount    int
+	LastViewedAt *time.Time
+	CreatedAt    time.Time
+}
+
+// NewLink creates a new share link with an unguessable token
+func NewLink(userID, bookID string, expiresAt *time.Time) (*Link, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Link{
+		ID:        uuid.New().String(),
+		Token:     token,
+		BookID:    bookID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// SetPassword protects the link with the given password
+func (l *Link) SetPassword(password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	l.PasswordHash = string(hash)
+	return nil
+}
+
+// HasPassword reports whether the link is password protected
+func (l *Link) HasPassword() bool {
+	return l.PasswordHash != ""
+}
+
+// CheckPassword reports whether the given password unlocks the link
+func (l *Link) CheckPassword(password string) bool {
+	if !l.HasPassword() {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(l.PasswordHash), []byte(password)) == nil
+}
+
+// IsActive reports whether the link can still be viewed at the given time
+func (l *Link) IsActive(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	if l.ExpiresAt != nil && !now.Before(*l.ExpiresAt) {
+		return false
+	}
+	return true
+}
+
+// Path returns the public path of the shared book
+func (l *Link) Path() string {
+	return "/s/" + l.Token
+}
+
+// Repository defines the interface for share link persistence
+type Repository interface {
+	Save(ctx context.Context, link *Link) error
+	FindByToken(ctx context.Context, token string) (*Link, error)
+	FindByBookID(ctx context.Context, bookID, userID string) ([]*Link, error)
+	Revoke(ctx context.Context, id, userID string) error
+	IncrementViewCount(ctx context.Context, id string) error
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
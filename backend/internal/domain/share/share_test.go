@@ -0,0 +1,65 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLink(t *testing.T) {
+	link, err := NewLink("user-123", "book-123", nil)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, link.ID)
+	assert.Len(t, link.Token, 43)
+	assert.Equal(t, "user-123", link.UserID)
+	assert.Equal(t, "book-123", link.BookID)
+	assert.Equal(t, "/s/"+link.Token, link.Path())
+	assert.False(t, link.HasPassword())
+	assert.Zero(t, link.ViewCount)
+
+	other, err := NewLink("user-123", "book-123", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, link.Token, other.Token)
+}
+
+func TestLink_IsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		revokedAt *time.Time
+		expected  bool
+	}{
+		{name: "no expiry", expected: true},
+		{name: "expires in the future", expiresAt: &future, expected: true},
+		{name: "expired", expiresAt: &past, expected: false},
+		{name: "revoked", revokedAt: &past, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := &Link{ExpiresAt: tt.expiresAt, RevokedAt: tt.revokedAt}
+			assert.Equal(t, tt.expected, link.IsActive(now))
+		})
+	}
+}
+
+func TestLink_Password(t *testing.T) {
+	link, err := NewLink("user-123", "book-123", nil)
+	require.NoError(t, err)
+
+	assert.True(t, link.CheckPassword(""))
+
+	require.NoError(t, link.SetPassword("secret"))
+	assert.True(t, link.HasPassword())
+	assert.NotEqual(t, "secret", link.PasswordHash)
+	assert.True(t, link.CheckPassword("secret"))
+	assert.False(t, link.CheckPassword("wrong"))
+	assert.False(t, link.CheckPassword(""))
+}
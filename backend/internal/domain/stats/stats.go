@@ -0,0 +1,132 @@
+package stats
+
+import (
+	"context"
+	"time"
+)
+
+// MaxTags bounds the tag lists in library statistics
+const MaxTags = 10
+
+// Range is the period library statistics cover, ending today
+type Range string
+
+const (
+	RangeWeek    Range = "week"
+	RangeMonth   Range = "month"
+	RangeQuarter Range = "quarter"
+	RangeYear    Range = "year"
+)
+
+// IsValid reports whether the range is one of the known ranges
+func (r Range) IsValid() bool {
+	switch r {
+	case RangeWeek, RangeMonth, RangeQuarter, RangeYear:
+		return true
+	}
+	return false
+}
+
+// Interval is the width of the buckets of a time series
+type Interval string
+
+const (
+	IntervalDay  Interval = "day"
+	IntervalWeek Interval = "week"
+)
+
+// Window returns the first bucket of the range ending at now and the width of
+// its buckets: days for a week or a month, weeks starting on Monday for a
+// quarter or a year. Days are UTC.
+func (r Range) Window(now time.Time) (time.Time, Interval) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	switch r {
+	case RangeMonth:
+		return today.AddDate(0, 0, -29), IntervalDay
+	case RangeQuarter:
+		return monday.AddDate(0, 0, -7*12), IntervalWeek
+	case RangeYear:
+		return monday.AddDate(0, 0, -7*51), IntervalWeek
+	default:
+		return today.AddDate(0, 0, -6), IntervalDay
+	}
+}
+
+// BucketStarts returns the start of every bucket from since up to until
+func BucketStarts(since, until time.Time, interval Interval) []time.Time {
+	step := 1
+	if interval == IntervalWeek {
+		step = 7
+	}
+	var starts []time.Time
+	for t := since; !t.After(until); t = t.AddDate(0, 0, step) {
+		starts = append(starts, t)
+	}
+	return starts
+}
+
+// Bucket is one point of a time series
+type Bucket struct {
+	Start time.Time
+	Count int
+}
+
+// TagCount is how many books carry a tag
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// TagTrend is how many books saved in each bucket carry a tag
+type TagTrend struct {
+	Tag     string
+	Count   int
+	Buckets []*Bucket
+}
+
+// LibraryStats describes the books of a library, the user's personal books or
+// a workspace, as seen by one user. Time series and the counts of saved,
+// merged and enriched books cover the books saved since Since; the other
+// figures cover the whole library.
+type LibraryStats struct {
+	Range    Range
+	Interval Interval
+	Since    time.Time
+	// TotalBooks counts every book in the library
+	TotalBooks int
+	// SavedBooks counts the books saved in each bucket
+	SavedBooks []*Bucket
+	// TagTrends follows the MaxTags tags most often saved in the range
+	TagTrends []*TagTrend
+	// BacklogSize counts the books the user has not started reading
+	BacklogSize int
+	// BacklogTrend is the backlog size at the end of each bucket
+	BacklogTrend []*Bucket
+	// AverageContentLength is in characters
+	AverageContentLength int
+	// MergedTopics are the MaxTags tags most often on books made by merging
+	MergedTopics []*TagCount
+	// EnrichmentAttempts counts the books for which AI was asked to fill in a
+	// title or tags, and EnrichmentSuccesses those where every call succeeded
+	EnrichmentAttempts  int
+	EnrichmentSuccesses int
+}
+
+// EnrichmentSuccessRate is the share of enrichment attempts that succeeded,
+// from 0 to 1, or nil when there were none
+func (s *LibraryStats) EnrichmentSuccessRate() *float64 {
+	if s.EnrichmentAttempts == 0 {
+		return nil
+	}
+	rate := float64(s.EnrichmentSuccesses) / float64(s.EnrichmentAttempts)
+	return &rate
+}
+
+// Repository computes library statistics
+type Repository interface {
+	// GetLibraryStats computes the statistics of the user's personal books,
+	// or of a workspace when workspaceID is set, with time series in buckets
+	// of interval from since up to now
+	GetLibraryStats(ctx context.Context, userID, workspaceID string, since time.Time, interval Interval) (*LibraryStats, error)
+}
@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange_Window(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2024, 5, 15, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		rng      Range
+		since    time.Time
+		interval Interval
+	}{
+		{rng: RangeWeek, since: time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC), interval: IntervalDay},
+		{rng: RangeMonth, since: time.Date(2024, 4, 16, 0, 0, 0, 0, time.UTC), interval: IntervalDay},
+		{rng: RangeQuarter, since: time.Date(2024, 2, 19, 0, 0, 0, 0, time.UTC), interval: IntervalWeek},
+		{rng: RangeYear, since: time.Date(2023, 5, 22, 0, 0, 0, 0, time.UTC), interval: IntervalWeek},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rng), func(t *testing.T) {
+			since, interval := tt.rng.Window(now)
+
+			assert.Equal(t, tt.since, since)
+			assert.Equal(t, tt.interval, interval)
+			if interval == IntervalWeek {
+				assert.Equal(t, time.Monday, since.Weekday())
+			}
+		})
+	}
+}
+
+func TestBucketStarts(t *testing.T) {
+	since := time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 5, 15, 18, 30, 0, 0, time.UTC)
+
+	days := BucketStarts(since, now, IntervalDay)
+	assert.Len(t, days, 3)
+	assert.Equal(t, time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), days[2])
+
+	weeks := BucketStarts(since.AddDate(0, 0, -14), now, IntervalWeek)
+	assert.Equal(t, []time.Time{since.AddDate(0, 0, -14), since.AddDate(0, 0, -7), since}, weeks)
+}
+
+func TestLibraryStats_EnrichmentSuccessRate(t *testing.T) {
+	assert.Nil(t, (&LibraryStats{}).EnrichmentSuccessRate())
+
+	rate := (&LibraryStats{EnrichmentAttempts: 4, EnrichmentSuccesses: 3}).EnrichmentSuccessRate()
+	assert.Equal(t, 0.75, *rate)
+}
@@ -0,0 +1,197 @@
+package tag
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// Separator splits a hierarchical tag such as lang/go into its segments
+const Separator = "/"
+
+// ErrSynonymNotFound is returned when a user has no synonym for an alias
+var ErrSynonymNotFound = errs.NotFound("tag synonym not found")
+
+// Normalize trims the segments of a tag, collapsing the whitespace in them
+// and dropping empty ones, so that " lang / go/ " becomes lang/go
+func Normalize(name string) string {
+	var segments []string
+	for _, segment := range strings.Split(name, Separator) {
+		segment = strings.Join(strings.Fields(segment), " ")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, Separator)
+}
+
+// Matches reports whether name is filter or one of its descendants, ignoring
+// case: lang/go and lang/go/generics match lang/go but golang does not
+func Matches(name, filter string) bool {
+	name, filter = strings.ToLower(Normalize(name)), strings.ToLower(Normalize(filter))
+	if filter == "" {
+		return false
+	}
+	return name == filter || strings.HasPrefix(name, filter+Separator)
+}
+
+// Tagged reports whether b carries filter or one of its descendants
+func Tagged(b *book.Book, filter string) bool {
+	for _, t := range b.Tags {
+		if Matches(t, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the books carrying filter or one of its descendants
+func Filter(books []*book.Book, filter string) []*book.Book {
+	result := make([]*book.Book, 0, len(books))
+	for _, b := range books {
+		if Tagged(b, filter) {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// Synonym makes a user's tag Alias stand for Tag, such as golang for lang/go
+type Synonym struct {
+	UserID string
+	// Alias is stored lowercased; it matches tags ignoring case
+	Alias     string
+	Tag       string
+	CreatedAt time.Time
+}
+
+// NewSynonym validates and normalizes a synonym of the user's
+func NewSynonym(userID, alias, tag string) (*Synonym, error) {
+	alias, tag = strings.ToLower(Normalize(alias)), Normalize(tag)
+	if alias == "" {
+		return nil, errs.Validation("alias", "alias is required")
+	}
+	if tag == "" {
+		return nil, errs.Validation("tag", "tag is required")
+	}
+	if alias == strings.ToLower(tag) {
+		return nil, errs.Validation("alias", "alias must differ from the tag")
+	}
+	return &Synonym{UserID: userID, Alias: alias, Tag: tag}, nil
+}
+
+// Synonyms replaces aliases with the tags they stand for, keyed by alias
+type Synonyms map[string]string
+
+// NewSynonyms indexes a user's synonyms
+func NewSynonyms(synonyms []*Synonym) Synonyms {
+	index := make(Synonyms, len(synonyms))
+	for _, s := range synonyms {
+		index[strings.ToLower(s.Alias)] = s.Tag
+	}
+	return index
+}
+
+// Canonical normalizes name and replaces it when it is an alias
+func (s Synonyms) Canonical(name string) string {
+	name = Normalize(name)
+	if canonical, ok := s[strings.ToLower(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
+// Canonicalize returns the canonical form of tags in order, without empty
+// tags or tags repeated ignoring case
+func (s Synonyms) Canonicalize(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = s.Canonical(t)
+		key := strings.ToLower(t)
+		if t == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, t)
+	}
+	return result
+}
+
+// Node is a tag in the tag hierarchy of a library
+type Node struct {
+	// Name is the last segment of the tag, such as go in lang/go
+	Name string
+	// Path is the whole tag
+	Path string
+	// Count is the number of books carrying the tag or one of its descendants
+	Count    int
+	Children []*Node
+}
+
+// BuildTree arranges the tags of books, one entry per book, into a hierarchy.
+// A tag like lang/go also counts for lang, once per book. Tags differing
+// only in case are merged under their first spelling. Siblings are ordered
+// by count, most used first, then by name.
+func BuildTree(bookTags [][]string) []*Node {
+	root := &Node{}
+	nodes := map[string]*Node{}
+	for _, tags := range bookTags {
+		counted := map[*Node]bool{}
+		for _, t := range tags {
+			parent := root
+			segments := strings.Split(Normalize(t), Separator)
+			for i, segment := range segments {
+				if segment == "" {
+					break
+				}
+				path := strings.Join(segments[:i+1], Separator)
+				key := strings.ToLower(path)
+				node, ok := nodes[key]
+				if !ok {
+					node = &Node{Name: segment, Path: path}
+					nodes[key] = node
+					parent.Children = append(parent.Children, node)
+				}
+				if !counted[node] {
+					counted[node] = true
+					node.Count++
+				}
+				parent = node
+			}
+		}
+	}
+	sortNodes(root.Children)
+	if root.Children == nil {
+		return []*Node{}
+	}
+	return root.Children
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Count != nodes[j].Count {
+			return nodes[i].Count > nodes[j].Count
+		}
+		return nodes[i].Path < nodes[j].Path
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
+// Repository defines the interface for tag synonyms and for reading the tags
+// of a library. Tags themselves are stored on books.
+type Repository interface {
+	FindSynonyms(ctx context.Context, userID string) ([]*Synonym, error)
+	// SaveSynonym creates the synonym or points its alias at a new tag
+	SaveSynonym(ctx context.Context, synonym *Synonym) error
+	DeleteSynonym(ctx context.Context, userID, alias string) error
+	// FindBookTags returns the tags of each of the user's personal books, or
+	// of a workspace's books when workspaceID is set, oldest book first
+	FindBookTags(ctx context.Context, userID, workspaceID string) ([][]string, error)
+}
@@ -0,0 +1,102 @@
+package tag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "lang/go", Normalize(" lang / go/ "))
+	assert.Equal(t, "machine learning/llm", Normalize("machine   learning//llm"))
+	assert.Equal(t, "", Normalize(" / "))
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    string
+		filter string
+		want   bool
+	}{
+		{name: "same tag", tag: "lang/go", filter: "lang/go", want: true},
+		{name: "child", tag: "lang/go", filter: "lang", want: true},
+		{name: "grandchild", tag: "lang/go/generics", filter: "lang", want: true},
+		{name: "case is ignored", tag: "Lang/Go", filter: "lang/go", want: true},
+		{name: "parent does not match child filter", tag: "lang", filter: "lang/go", want: false},
+		{name: "prefix of a segment", tag: "language", filter: "lang", want: false},
+		{name: "empty filter", tag: "go", filter: " ", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Matches(tt.tag, tt.filter))
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	books := []*book.Book{
+		{ID: "go", Tags: []string{"lang/go"}},
+		{ID: "rust", Tags: []string{"cooking", "lang/rust"}},
+		{ID: "other", Tags: []string{"language"}},
+	}
+
+	assert.Equal(t, []*book.Book{books[0], books[1]}, Filter(books, "lang"))
+	assert.Equal(t, []*book.Book{books[0]}, Filter(books, "lang/go"))
+	assert.Empty(t, Filter(books, "lang/python"))
+}
+
+func TestNewSynonym(t *testing.T) {
+	s, err := NewSynonym("user-1", " GoLang ", "lang / go")
+	require.NoError(t, err)
+	assert.Equal(t, &Synonym{UserID: "user-1", Alias: "golang", Tag: "lang/go"}, s)
+
+	for _, tt := range []struct{ alias, tag string }{{"", "go"}, {"golang", "/"}, {"Go", "go"}} {
+		_, err := NewSynonym("user-1", tt.alias, tt.tag)
+		assert.ErrorIs(t, err, errs.ErrValidation, "%q -> %q", tt.alias, tt.tag)
+	}
+}
+
+func TestSynonyms_Canonicalize(t *testing.T) {
+	synonyms := NewSynonyms([]*Synonym{
+		{Alias: "golang", Tag: "lang/go"},
+		{Alias: "js", Tag: "lang/javascript"},
+	})
+
+	assert.Equal(t, "lang/go", synonyms.Canonical(" GoLang "))
+	assert.Equal(t, "lang/rust", synonyms.Canonical("lang / rust"))
+	assert.Equal(t,
+		[]string{"lang/go", "web", "lang/javascript"},
+		synonyms.Canonicalize([]string{"golang", "web", "", "Lang/Go", "JS", "Web"}),
+	)
+	assert.Equal(t, []string{"go"}, Synonyms(nil).Canonicalize([]string{"go", "go"}))
+}
+
+func TestBuildTree(t *testing.T) {
+	tree := BuildTree([][]string{
+		{"lang/go", "lang/go/generics", "web"},
+		{"lang/rust"},
+		{"Lang/Go"},
+		{"web"},
+		{"lang"},
+	})
+
+	require.Len(t, tree, 2)
+	lang := tree[0]
+	assert.Equal(t, "lang", lang.Name)
+	// Every book with a lang tag counts once, whatever its depth
+	assert.Equal(t, 4, lang.Count)
+	require.Len(t, lang.Children, 2)
+	assert.Equal(t, &Node{Name: "go", Path: "lang/go", Count: 2, Children: []*Node{
+		{Name: "generics", Path: "lang/go/generics", Count: 1},
+	}}, lang.Children[0])
+	assert.Equal(t, &Node{Name: "rust", Path: "lang/rust", Count: 1}, lang.Children[1])
+	assert.Equal(t, &Node{Name: "web", Path: "web", Count: 2}, tree[1])
+
+	assert.Equal(t, []*Node{}, BuildTree(nil))
+}
@@ -0,0 +1,22 @@
+package transaction
+
+import "context"
+
+// UnitOfWork runs several repository calls as one atomic change
+type UnitOfWork interface {
+	// RunInTransaction calls fn inside a transaction. Repository calls made
+	// with the context passed to fn take part in it. The transaction commits
+	// when fn returns nil and rolls back otherwise. A nested call joins the
+	// transaction that is already open.
+	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// None runs fn directly, without a transaction. It suits use cases backed by
+// stores that have no transactions, such as the mocks in tests.
+var None UnitOfWork = none{}
+
+type none struct{}
+
+func (none) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// ErrNotFound is returned when a webhook does not exist or belongs to another user
+var ErrNotFound = errs.NotFound("webhook not found")
+
+// EventType identifies what happened to trigger a delivery
+type EventType string
+
+const (
+	EventBookCreated EventType = "book.created"
+	EventBookUpdated EventType = "book.updated"
+	EventBookDeleted EventType = "book.deleted"
+	EventBookMerged  EventType = "book.merged"
+
+	// EventTest is sent by testWebhook and cannot be subscribed to
+	EventTest EventType = "webhook.test"
+)
+
+// IsValid reports whether webhooks can subscribe to the event type
+func (e EventType) IsValid() bool {
+	switch e {
+	case EventBookCreated, EventBookUpdated, EventBookDeleted, EventBookMerged:
+		return true
+	}
+	return false
+}
+
+// SignatureHeader carries the HMAC-SHA256 signature of the request body
+const SignatureHeader = "X-Tsundoc-Signature"
+
+// PublicAddress reports whether webhooks may be delivered to addr. Loopback,
+// private, link-local, multicast and unspecified addresses are refused so that
+// webhooks cannot reach the server's own network.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// PublicHost reports whether host, the host name of a webhook URL, may be
+// public. Names are resolved when delivering, so only localhost and IP
+// addresses are decided here.
+func PublicHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(addr)
+	}
+	return true
+}
+
+// Webhook is a user's subscription to book events
+type Webhook struct {
+	ID        string
+	UserID    string
+	URL       string
+	Secret    string
+	Events    []EventType
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWebhook creates a webhook. A random secret is generated when secret is empty.
+func NewWebhook(userID, url, secret string, events []EventType) (*Webhook, error) {
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	now := time.Now()
+	return &Webhook{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		URL:       url,
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Subscribes reports whether the webhook should receive the event type
+func (w *Webhook) Subscribes(eventType EventType) bool {
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature header value for a request body
+func (w *Webhook) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Event is the JSON document delivered to webhooks
+type Event struct {
+	ID         string      `json:"id"`
+	Type       EventType   `json:"type"`
+	UserID     string      `json:"-"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Data       interface{} `json:"data"`
+}
+
+func NewEvent(eventType EventType, userID string, data interface{}) Event {
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: time.Now(),
+		Data:       data,
+	}
+}
+
+// BookPayload is the representation of a book in event data
+type BookPayload struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+	URL           string     `json:"url,omitempty"`
+	WorkspaceID   string     `json:"workspaceId,omitempty"`
+	SourceBookIDs []string   `json:"sourceBookIds,omitempty"`
+	CreatedAt     *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
+
+// NewBookPayload describes a book in event data. Content is left out to keep
+// deliveries small; receivers can fetch it through the API.
+func NewBookPayload(b *book.Book) BookPayload {
+	return BookPayload{
+		ID:          b.ID,
+		Title:       b.Title,
+		Tags:        b.Tags,
+		URL:         b.URL,
+		WorkspaceID: b.WorkspaceID,
+		CreatedAt:   &b.CreatedAt,
+		UpdatedAt:   &b.UpdatedAt,
+	}
+}
+
+// NewBookEvent creates an event carrying a book
+func NewBookEvent(eventType EventType, userID string, b *book.Book) Event {
+	return NewEvent(eventType, userID, NewBookPayload(b))
+}
+
+// Delivery records one attempt to deliver an event to a webhook
+type Delivery struct {
+	ID         string
+	WebhookID  string
+	EventID    string
+	EventType  EventType
+	Payload    string
+	Attempt    int
+	StatusCode int
+	Error      string
+	Succeeded  bool
+	CreatedAt  time.Time
+}
+
+// Publisher queues events for delivery to subscribed webhooks
+type Publisher interface {
+	// Publish must not block the caller on network I/O
+	Publish(ctx context.Context, event Event)
+}
+
+// Dispatcher delivers events to webhooks
+type Dispatcher interface {
+	Publisher
+
+	// Deliver sends an event to a single webhook once and records the attempt
+	Deliver(ctx context.Context, w *Webhook, event Event) (*Delivery, error)
+}
+
+type Repository interface {
+	Save(ctx context.Context, w *Webhook) error
+	FindByID(ctx context.Context, id, userID string) (*Webhook, error)
+	FindByUserID(ctx context.Context, userID string) ([]*Webhook, error)
+	Delete(ctx context.Context, id, userID string) error
+	SaveDelivery(ctx context.Context, d *Delivery) error
+	// FindDeliveries returns the most recent delivery attempts, newest first
+	FindDeliveries(ctx context.Context, webhookID, userID string, limit int) ([]*Delivery, error)
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+)
+
+func TestNewWebhook(t *testing.T) {
+	t.Run("generates a secret when none is given", func(t *testing.T) {
+		w, err := NewWebhook("user-123", "https://example.com/hook", "", []EventType{EventBookCreated})
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, w.ID)
+		assert.Len(t, w.Secret, 64)
+		assert.True(t, w.Subscribes(EventBookCreated))
+		assert.False(t, w.Subscribes(EventBookDeleted))
+	})
+
+	t.Run("keeps a provided secret", func(t *testing.T) {
+		w, err := NewWebhook("user-123", "https://example.com/hook", "s3cret", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "s3cret", w.Secret)
+	})
+}
+
+func TestPublicHost(t *testing.T) {
+	for _, host := range []string{"example.com", "93.184.216.34", "2606:2800:220:1::"} {
+		assert.True(t, PublicHost(host), host)
+	}
+	for _, host := range []string{"localhost", "api.localhost", "LOCALHOST.", "127.0.0.1", "10.0.0.8", "192.168.1.1", "172.16.0.1", "169.254.169.254", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, PublicHost(host), host)
+	}
+	assert.False(t, PublicAddress(netip.Addr{}))
+}
+
+func TestEventType_IsValid(t *testing.T) {
+	assert.True(t, EventBookMerged.IsValid())
+	assert.False(t, EventTest.IsValid())
+	assert.False(t, EventType("book.read").IsValid())
+}
+
+func TestWebhook_Sign(t *testing.T) {
+	w := &Webhook{Secret: "s3cret"}
+	body := []byte(`{"type":"book.created"}`)
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), w.Sign(body))
+}
+
+func TestNewBookEvent(t *testing.T) {
+	b := book.NewBook("user-123", "Some content")
+	b.Title = "Title"
+
+	event := NewBookEvent(EventBookCreated, "user-123", b)
+
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, "book.created", decoded["type"])
+	assert.NotContains(t, decoded, "UserID")
+
+	data := decoded["data"].(map[string]interface{})
+	assert.Equal(t, b.ID, data["id"])
+	assert.Equal(t, "Title", data["title"])
+	assert.NotContains(t, data, "content")
+}
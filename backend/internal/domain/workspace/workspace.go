@@ -0,0 +1,139 @@
+package workspace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// Role is a member's permission level within a workspace
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+var (
+	// ErrNotFound is returned when a workspace does not exist or the user is not a member
+	ErrNotFound = errs.NotFound("workspace not found")
+	// ErrMemberNotFound is returned when a user is not a member of the workspace
+	ErrMemberNotFound = errs.NotFound("workspace member not found")
+	// ErrInvitationNotFound is returned for unknown invitation tokens
+	ErrInvitationNotFound = errs.NotFound("workspace invitation not found")
+)
+
+// invitationTTL is how long an invitation can be accepted
+const invitationTTL = 7 * 24 * time.Hour
+
+// IsValid reports whether the role is one of the known roles
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleOwner, RoleEditor, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// CanWrite reports whether the role allows creating and editing books
+func (r Role) CanWrite() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// CanManage reports whether the role allows managing members and invitations
+func (r Role) CanManage() bool {
+	return r == RoleOwner
+}
+
+// Workspace represents a shared library owned by a team
+type Workspace struct {
+	ID        string
+	Name      string
+	OwnerID   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWorkspace creates a new workspace instance
+func NewWorkspace(ownerID, name string) *Workspace {
+	now := time.Now()
+	return &Workspace{
+		ID:        uuid.New().String(),
+		Name:      name,
+		OwnerID:   ownerID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Member represents a user's membership in a workspace
+type Member struct {
+	WorkspaceID string
+	UserID      string
+	Role        Role
+	JoinedAt    time.Time
+}
+
+// Invitation lets the holder of its token join a workspace with the given role
+type Invitation struct {
+	ID          string
+	WorkspaceID string
+	Token       string
+	Role        Role
+	InvitedBy   string
+	ExpiresAt   time.Time
+	AcceptedBy  string
+	AcceptedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+// NewInvitation creates a new invitation with an unguessable token
+func NewInvitation(workspaceID, invitedBy string, role Role) (*Invitation, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	now := time.Now()
+	return &Invitation{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		Token:       base64.RawURLEncoding.EncodeToString(b),
+		Role:        role,
+		InvitedBy:   invitedBy,
+		ExpiresAt:   now.Add(invitationTTL),
+		CreatedAt:   now,
+	}, nil
+}
+
+// IsPending reports whether the invitation can still be accepted at the given time
+func (i *Invitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// Repository defines the interface for workspace persistence
+type Repository interface {
+	Save(ctx context.Context, workspace *Workspace) error
+	FindByID(ctx context.Context, id, userID string) (*Workspace, error)
+	FindByUserID(ctx context.Context, userID string) ([]*Workspace, error)
+
+	AddMember(ctx context.Context, member *Member) error
+	FindMember(ctx context.Context, workspaceID, userID string) (*Member, error)
+	FindMembers(ctx context.Context, workspaceID string) ([]*Member, error)
+	// FindMembersByWorkspaceIDs returns the members of several workspaces in
+	// one query, keyed by workspace ID
+	FindMembersByWorkspaceIDs(ctx context.Context, workspaceIDs []string) (map[string][]*Member, error)
+	UpdateMemberRole(ctx context.Context, workspaceID, userID string, role Role) error
+	RemoveMember(ctx context.Context, workspaceID, userID string) error
+
+	SaveInvitation(ctx context.Context, invitation *Invitation) error
+	FindInvitationByToken(ctx context.Context, token string) (*Invitation, error)
+	MarkInvitationAccepted(ctx context.Context, id, userID string) error
+}
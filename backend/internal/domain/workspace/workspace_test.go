@@ -0,0 +1,53 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWorkspace(t *testing.T) {
+	ws := NewWorkspace("user-123", "Research")
+
+	assert.NotEmpty(t, ws.ID)
+	assert.Equal(t, "user-123", ws.OwnerID)
+	assert.Equal(t, "Research", ws.Name)
+	assert.WithinDuration(t, time.Now(), ws.CreatedAt, time.Second)
+}
+
+func TestRole_Permissions(t *testing.T) {
+	tests := []struct {
+		role      Role
+		valid     bool
+		canWrite  bool
+		canManage bool
+	}{
+		{role: RoleOwner, valid: true, canWrite: true, canManage: true},
+		{role: RoleEditor, valid: true, canWrite: true, canManage: false},
+		{role: RoleViewer, valid: true, canWrite: false, canManage: false},
+		{role: Role("admin"), valid: false, canWrite: false, canManage: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.role.IsValid())
+			assert.Equal(t, tt.canWrite, tt.role.CanWrite())
+			assert.Equal(t, tt.canManage, tt.role.CanManage())
+		})
+	}
+}
+
+func TestInvitation_IsPending(t *testing.T) {
+	inv, err := NewInvitation("ws-123", "user-123", RoleEditor)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, inv.Token)
+	assert.True(t, inv.IsPending(time.Now()))
+	assert.False(t, inv.IsPending(inv.ExpiresAt.Add(time.Second)))
+
+	acceptedAt := time.Now()
+	inv.AcceptedAt = &acceptedAt
+	assert.False(t, inv.IsPending(time.Now()))
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/motoya-k/tsundoc/internal/domain/ai"
+)
+
+// OperationConfig holds the model settings of an operation
+type OperationConfig struct {
+	Model       string
+	Temperature float32
+	MaxTokens   int
+	// Timeout bounds each call; 0 leaves it to the caller's context
+	Timeout time.Duration
+	// MaxContentChars is how much of the content goes into the prompt
+	MaxContentChars int
+}
+
+// Config configures the OpenAI service
+type Config struct {
+	// PromptsDir holds templates that replace the embedded defaults, one
+	// <operation>.tmpl file per operation. Operations without a file keep
+	// the default.
+	PromptsDir string
+	Operations map[ai.Operation]OperationConfig
+}
+
+// DefaultConfig returns the settings used unless they are overridden
+func DefaultConfig() Config {
+	return Config{
+		Operations: map[ai.Operation]OperationConfig{
+			ai.OperationGenerateTitle: {
+				Model: openai.GPT3Dot5Turbo, Temperature: 0.7, MaxTokens: 50,
+				Timeout: 30 * time.Second, MaxContentChars: 3000,
+			},
+			// Structured outputs need a model that supports JSON schemas
+			ai.OperationGenerateTags: {
+				Model: openai.GPT4oMini, Temperature: 0.5, MaxTokens: 100,
+				Timeout: 30 * time.Second, MaxContentChars: 3000,
+			},
+			ai.OperationSummarizeContent: {
+				Model: openai.GPT3Dot5Turbo, Temperature: 0.5, MaxTokens: 400,
+				Timeout: 30 * time.Second, MaxContentChars: 4000,
+			},
+			ai.OperationMergeContents: {
+				Model: openai.GPT3Dot5Turbo, Temperature: 0.3, MaxTokens: 2000,
+				Timeout: 60 * time.Second, MaxContentChars: 6000,
+			},
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/motoya-k/tsundoc/internal/domain/ai"
+	"github.com/motoya-k/tsundoc/internal/infra/telemetry"
+)
+
+// InstrumentedService wraps an AI service with a span and metrics per call
+type InstrumentedService struct {
+	next     ai.Service
+	provider string
+	metrics  *telemetry.Metrics
+}
+
+// NewInstrumentedService wraps next; provider labels the metrics, e.g. "openai"
+func NewInstrumentedService(next ai.Service, provider string, metrics *telemetry.Metrics) *InstrumentedService {
+	return &InstrumentedService{
+		next:     next,
+		provider: provider,
+		metrics:  metrics,
+	}
+}
+
+// Ping forwards to the wrapped service when it supports health checks. It is
+// not recorded as an AI request.
+func (s *InstrumentedService) Ping(ctx context.Context) error {
+	if checker, ok := s.next.(ai.HealthChecker); ok {
+		return checker.Ping(ctx)
+	}
+	return nil
+}
+
+// PromptVersion forwards to the wrapped service when its prompts are
+// versioned
+func (s *InstrumentedService) PromptVersion(operation ai.Operation) string {
+	if versioner, ok := s.next.(ai.PromptVersioner); ok {
+		return versioner.PromptVersion(operation)
+	}
+	return ""
+}
+
+// GenerateTitle generates a title from the given content
+func (s *InstrumentedService) GenerateTitle(ctx context.Context, content string) (string, error) {
+	return instrument(ctx, s, ai.OperationGenerateTitle, func(ctx context.Context) (string, error) {
+		return s.next.GenerateTitle(ctx, content)
+	})
+}
+
+// GenerateTags generates relevant tags from the given content
+func (s *InstrumentedService) GenerateTags(ctx context.Context, content string, existingTags []string) ([]ai.Tag, error) {
+	return instrument(ctx, s, ai.OperationGenerateTags, func(ctx context.Context) ([]ai.Tag, error) {
+		return s.next.GenerateTags(ctx, content, existingTags)
+	})
+}
+
+// SummarizeContent creates a summary of the given content
+func (s *InstrumentedService) SummarizeContent(ctx context.Context, content string) (string, error) {
+	return instrument(ctx, s, ai.OperationSummarizeContent, func(ctx context.Context) (string, error) {
+		return s.next.SummarizeContent(ctx, content)
+	})
+}
+
+// MergeContents intelligently merges multiple content pieces
+func (s *InstrumentedService) MergeContents(ctx context.Context, contents []string) (string, error) {
+	return instrument(ctx, s, ai.OperationMergeContents, func(ctx context.Context) (string, error) {
+		return s.next.MergeContents(ctx, contents)
+	})
+}
+
+func instrument[T any](ctx context.Context, s *InstrumentedService, operation ai.Operation, call func(context.Context) (T, error)) (T, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "ai."+string(operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("ai.provider", s.provider)),
+	)
+	defer span.End()
+
+	start := time.Now()
+	result, err := call(ctx)
+	s.metrics.ObserveAIRequest(string(operation), s.provider, err, time.Since(start))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
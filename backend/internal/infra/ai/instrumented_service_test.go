@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/motoya-k/tsundoc/internal/domain/ai"
+	"github.com/motoya-k/tsundoc/internal/infra/telemetry"
+)
+
+func TestInstrumentedService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	metrics := telemetry.NewMetrics()
+	svc := NewInstrumentedService(&MockOpenAIService{
+		GenerateTagsFunc: func(ctx context.Context, content string, existingTags []string) ([]ai.Tag, error) {
+			return nil, errors.New("rate limited")
+		},
+	}, "openai", metrics)
+
+	title, err := svc.GenerateTitle(context.Background(), "content")
+	require.NoError(t, err)
+	assert.Equal(t, "Test Title", title)
+
+	_, err = svc.GenerateTags(context.Background(), "content", nil)
+	assert.EqualError(t, err, "rate limited")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "ai.generate_title", spans[0].Name())
+	assert.Equal(t, "ai.generate_tags", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+	families, err := metrics.Registry().Gather()
+	require.NoError(t, err)
+	counts := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "tsundoc_ai_requests_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			key := ""
+			for _, label := range m.GetLabel() {
+				key += label.GetName() + "=" + label.GetValue() + ","
+			}
+			counts[key] = m.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"operation=generate_tags,outcome=error,provider=openai,":    1,
+		"operation=generate_title,outcome=success,provider=openai,": 1,
+	}, counts)
+}
@@ -3,70 +3,74 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"strings"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
 	"github.com/motoya-k/tsundoc/internal/domain/ai"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
 )
 
-// OpenAIService implements the AI service using OpenAI API
+// OpenAIService implements the AI service using OpenAI API. Prompts are
+// rendered from templates and each operation has its own model settings.
 type OpenAIService struct {
-	client *openai.Client
+	client     *openai.Client
+	prompts    map[ai.Operation]*Prompt
+	operations map[ai.Operation]OperationConfig
 }
 
-// NewOpenAIService creates a new OpenAI service instance
-func NewOpenAIService(apiKey string) *OpenAIService {
+// NewOpenAIService creates a new OpenAI service instance. It fails when a
+// prompt template in cfg.PromptsDir cannot be parsed.
+func NewOpenAIService(apiKey string, cfg Config) (*OpenAIService, error) {
+	prompts, err := LoadPrompts(cfg.PromptsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := DefaultConfig().Operations
+	for op, opCfg := range cfg.Operations {
+		operations[op] = opCfg
+	}
+
 	return &OpenAIService{
-		client: openai.NewClient(apiKey),
+		client:     openai.NewClient(apiKey),
+		prompts:    prompts,
+		operations: operations,
+	}, nil
+}
+
+// Ping lists the available models, which checks the API key and
+// connectivity without spending tokens
+func (s *OpenAIService) Ping(ctx context.Context) error {
+	if _, err := s.client.ListModels(ctx); err != nil {
+		return providerError("failed to reach OpenAI", err)
 	}
+	return nil
+}
+
+// PromptVersion returns the version of the template used for operation
+func (s *OpenAIService) PromptVersion(operation ai.Operation) string {
+	if p, ok := s.prompts[operation]; ok {
+		return p.Version
+	}
+	return ""
 }
 
 // GenerateTitle generates a title from the given content
 func (s *OpenAIService) GenerateTitle(ctx context.Context, content string) (string, error) {
 	if content == "" {
-		return "", fmt.Errorf("content cannot be empty")
+		return "", errs.Validation("content", "content cannot be empty")
 	}
 
-	// Truncate content if too long
-	truncatedContent := truncateContent(content, 3000)
-
-	prompt := fmt.Sprintf(`Given the following content, generate a concise and descriptive title (maximum 100 characters).
-The title should capture the main topic or key insight from the content.
-Output only the title, nothing else.
-
-Content:
-%s`, truncatedContent)
-
-	resp, err := s.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "You are a helpful assistant that generates concise titles for content.",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			Temperature: 0.7,
-			MaxTokens:   50,
-		},
-	)
-
+	title, err := s.complete(ctx, ai.OperationGenerateTitle, content, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate title: %w", err)
-	}
-
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+		return "", err
 	}
 
-	title := strings.TrimSpace(resp.Choices[0].Message.Content)
-	
 	// Ensure title is not too long
 	if len(title) > 100 {
 		title = title[:97] + "..."
@@ -75,186 +79,186 @@ Content:
 	return title, nil
 }
 
-// GenerateTags generates relevant tags from the given content
-func (s *OpenAIService) GenerateTags(ctx context.Context, content string) ([]string, error) {
+// GenerateTags generates relevant tags from the given content. The reply is
+// constrained to tagsSchema with structured outputs.
+func (s *OpenAIService) GenerateTags(ctx context.Context, content string, existingTags []string) ([]ai.Tag, error) {
 	if content == "" {
-		return []string{}, fmt.Errorf("content cannot be empty")
-	}
-
-	// Truncate content if too long
-	truncatedContent := truncateContent(content, 3000)
-
-	prompt := fmt.Sprintf(`Analyze the following content and generate 3-5 relevant tags.
-Tags should be single words or short phrases that capture key topics, technologies, or concepts.
-Output the tags as a JSON array of strings.
-
-Content:
-%s`, truncatedContent)
-
-	resp, err := s.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "You are a helpful assistant that generates relevant tags for content. Always output valid JSON.",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			Temperature: 0.5,
-			MaxTokens:   100,
-		},
-	)
-
-	if err != nil {
-		return []string{}, fmt.Errorf("failed to generate tags: %w", err)
+		return []ai.Tag{}, errs.Validation("content", "content cannot be empty")
 	}
 
-	if len(resp.Choices) == 0 {
-		return []string{}, fmt.Errorf("no response from OpenAI")
+	if len(existingTags) > ai.MaxExistingTags {
+		existingTags = existingTags[:ai.MaxExistingTags]
 	}
-
-	// Parse JSON response
-	var tags []string
-	responseContent := strings.TrimSpace(resp.Choices[0].Message.Content)
-	if err := json.Unmarshal([]byte(responseContent), &tags); err != nil {
-		// Fallback: try to extract tags from non-JSON response
-		tags = extractTagsFromText(responseContent)
-	}
-
-	// Limit to 5 tags
-	if len(tags) > 5 {
-		tags = tags[:5]
+	responseContent, err := s.complete(ctx, ai.OperationGenerateTags, content, existingTags)
+	if err != nil {
+		return []ai.Tag{}, err
 	}
 
-	return tags, nil
+	return parseTags(responseContent)
 }
 
 // SummarizeContent creates a summary of the given content
 func (s *OpenAIService) SummarizeContent(ctx context.Context, content string) (string, error) {
 	if content == "" {
-		return "", fmt.Errorf("content cannot be empty")
-	}
-
-	// Truncate content if too long
-	truncatedContent := truncateContent(content, 4000)
-
-	prompt := fmt.Sprintf(`Create a concise summary of the following content.
-The summary should capture the main points and key insights.
-Keep it under 300 words.
-
-Content:
-%s`, truncatedContent)
-
-	resp, err := s.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "You are a helpful assistant that creates concise summaries.",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			Temperature: 0.5,
-			MaxTokens:   400,
-		},
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("failed to summarize content: %w", err)
+		return "", errs.Validation("content", "content cannot be empty")
 	}
 
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
-	}
-
-	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+	return s.complete(ctx, ai.OperationSummarizeContent, content, nil)
 }
 
 // MergeContents intelligently merges multiple content pieces
 func (s *OpenAIService) MergeContents(ctx context.Context, contents []string) (string, error) {
 	if len(contents) == 0 {
-		return "", fmt.Errorf("no content to merge")
+		return "", errs.Validation("contents", "no content to merge")
 	}
 
 	// Combine all contents with separators
-	combinedContent := strings.Join(contents, "\n\n---\n\n")
-	truncatedContent := truncateContent(combinedContent, 6000)
+	return s.complete(ctx, ai.OperationMergeContents, strings.Join(contents, "\n\n---\n\n"), nil)
+}
 
-	prompt := fmt.Sprintf(`You have multiple pieces of related content below.
-Please merge them intelligently by:
-1. Removing duplicate information
-2. Organizing content logically
-3. Preserving all unique insights and information
-4. Creating a coherent flow
+// complete renders the prompt of operation and returns the trimmed reply
+func (s *OpenAIService) complete(ctx context.Context, operation ai.Operation, content string, existingTags []string) (string, error) {
+	cfg := s.operations[operation]
+	prompt, ok := s.prompts[operation]
+	if !ok {
+		return "", fmt.Errorf("no prompt for %s", operation)
+	}
 
-Contents to merge:
-%s`, truncatedContent)
+	system, user, err := prompt.Render(PromptData{
+		Content:      truncateContent(content, cfg.MaxContentChars),
+		Language:     detectLanguage(content),
+		ExistingTags: existingTags,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
 
 	resp, err := s.client.CreateChatCompletion(
 		ctx,
 		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
+			Model: cfg.Model,
 			Messages: []openai.ChatCompletionMessage{
 				{
 					Role:    openai.ChatMessageRoleSystem,
-					Content: "You are a helpful assistant that merges related content intelligently while preserving all important information.",
+					Content: system,
 				},
 				{
 					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
+					Content: user,
 				},
 			},
-			Temperature: 0.3,
-			MaxTokens:   2000,
+			Temperature:    cfg.Temperature,
+			MaxTokens:      cfg.MaxTokens,
+			ResponseFormat: responseFormats[operation],
 		},
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to merge contents: %w", err)
+		return "", providerError("failed to "+strings.ReplaceAll(string(operation), "_", " "), err)
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+		return "", errs.AIUnavailable("no response from OpenAI", nil)
 	}
 
 	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
 }
 
 // Ensure interface compliance
-var _ ai.Service = (*OpenAIService)(nil)
+var (
+	_ ai.Service         = (*OpenAIService)(nil)
+	_ ai.HealthChecker   = (*OpenAIService)(nil)
+	_ ai.PromptVersioner = (*OpenAIService)(nil)
+)
 
 // Helper functions
 
+// providerError classifies a failed API call. Rate and quota limits are
+// reported as exceeded quota so that clients can tell them apart from outages.
+func providerError(message string, err error) error {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusTooManyRequests {
+		return errs.QuotaExceeded(message, err)
+	}
+	return errs.AIUnavailable(message, err)
+}
+
+// truncateContent shortens content to at most maxChars runes, ending it with
+// an ellipsis when there is room for one.
 func truncateContent(content string, maxChars int) string {
-	if len(content) <= maxChars {
+	runes := []rune(content)
+	if len(runes) <= maxChars {
 		return content
 	}
-	return content[:maxChars-3] + "..."
+	if maxChars < 3 {
+		return string(runes[:max(maxChars, 0)])
+	}
+	return string(runes[:maxChars-3]) + "..."
 }
 
-func extractTagsFromText(text string) []string {
-	// Simple extraction logic for fallback
-	var tags []string
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.Contains(line, " ") || len(line) < 30 {
-			// Remove common punctuation
-			tag := strings.Trim(line, ".,;:-\"'[](){}")
-			if tag != "" {
-				tags = append(tags, tag)
-			}
-		}
+// tagsSchema is the shape of the reply to GenerateTags. Strict structured
+// outputs need an object at the top level and every property required.
+var tagsSchema = &jsonschema.Definition{
+	Type: jsonschema.Object,
+	Properties: map[string]jsonschema.Definition{
+		"tags": {
+			Type: jsonschema.Array,
+			Items: &jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"name": {
+						Type:        jsonschema.String,
+						Description: "The tag, a single word or short phrase",
+					},
+					"confidence": {
+						Type:        jsonschema.Number,
+						Description: "How well the tag fits the content, from 0 to 1",
+					},
+				},
+				Required:             []string{"name", "confidence"},
+				AdditionalProperties: false,
+			},
+		},
+	},
+	Required:             []string{"tags"},
+	AdditionalProperties: false,
+}
+
+// responseFormats holds the structured output format of the operations that
+// reply with JSON
+var responseFormats = map[ai.Operation]*openai.ChatCompletionResponseFormat{
+	ai.OperationGenerateTags: {
+		Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+			Name:   "tags",
+			Schema: tagsSchema,
+			Strict: true,
+		},
+	},
+}
+
+// parseTags decodes a reply matching tagsSchema. Confidence is clamped to
+// [0, 1].
+func parseTags(reply string) ([]ai.Tag, error) {
+	var parsed struct {
+		Tags []struct {
+			Name       string  `json:"name"`
+			Confidence float64 `json:"confidence"`
+		} `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(reply), &parsed); err != nil {
+		return []ai.Tag{}, errs.AIUnavailable("invalid tags from OpenAI", err)
 	}
-	return tags
+
+	tags := make([]ai.Tag, len(parsed.Tags))
+	for i, t := range parsed.Tags {
+		tags[i] = ai.Tag{Name: t.Name, Confidence: math.Min(math.Max(t.Confidence, 0), 1)}
+	}
+	return tags, nil
 }
\ No newline at end of file
@@ -2,17 +2,21 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/ai"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
 )
 
 // MockOpenAIService is a mock implementation for testing
 type MockOpenAIService struct {
 	GenerateTitleFunc      func(ctx context.Context, content string) (string, error)
-	GenerateTagsFunc       func(ctx context.Context, content string) ([]string, error)
+	GenerateTagsFunc       func(ctx context.Context, content string, existingTags []string) ([]ai.Tag, error)
 	SummarizeContentFunc   func(ctx context.Context, content string) (string, error)
 	MergeContentsFunc      func(ctx context.Context, contents []string) (string, error)
 }
@@ -24,11 +28,11 @@ func (m *MockOpenAIService) GenerateTitle(ctx context.Context, content string) (
 	return "Test Title", nil
 }
 
-func (m *MockOpenAIService) GenerateTags(ctx context.Context, content string) ([]string, error) {
+func (m *MockOpenAIService) GenerateTags(ctx context.Context, content string, existingTags []string) ([]ai.Tag, error) {
 	if m.GenerateTagsFunc != nil {
-		return m.GenerateTagsFunc(ctx, content)
+		return m.GenerateTagsFunc(ctx, content, existingTags)
 	}
-	return []string{"test", "tag", "mock"}, nil
+	return []ai.Tag{{Name: "test", Confidence: 1}, {Name: "mock", Confidence: 1}}, nil
 }
 
 func (m *MockOpenAIService) SummarizeContent(ctx context.Context, content string) (string, error) {
@@ -52,7 +56,8 @@ func TestOpenAIService_GenerateTitle(t *testing.T) {
 		t.Skip("OPENAI_API_KEY not set, skipping integration test")
 	}
 
-	service := NewOpenAIService(apiKey)
+	service, err := NewOpenAIService(apiKey, DefaultConfig())
+	require.NoError(t, err)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -98,7 +103,8 @@ func TestOpenAIService_GenerateTags(t *testing.T) {
 		t.Skip("OPENAI_API_KEY not set, skipping integration test")
 	}
 
-	service := NewOpenAIService(apiKey)
+	service, err := NewOpenAIService(apiKey, DefaultConfig())
+	require.NoError(t, err)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -120,7 +126,7 @@ func TestOpenAIService_GenerateTags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tags, err := service.GenerateTags(ctx, tt.content)
+			tags, err := service.GenerateTags(ctx, tt.content, nil)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -140,7 +146,8 @@ func TestOpenAIService_SummarizeContent(t *testing.T) {
 		t.Skip("OPENAI_API_KEY not set, skipping integration test")
 	}
 
-	service := NewOpenAIService(apiKey)
+	service, err := NewOpenAIService(apiKey, DefaultConfig())
+	require.NoError(t, err)
 	ctx := context.Background()
 
 	content := `GraphQL is a query language for APIs and a runtime for executing those queries. 
@@ -164,7 +171,8 @@ func TestOpenAIService_MergeContents(t *testing.T) {
 		t.Skip("OPENAI_API_KEY not set, skipping integration test")
 	}
 
-	service := NewOpenAIService(apiKey)
+	service, err := NewOpenAIService(apiKey, DefaultConfig())
+	require.NoError(t, err)
 	ctx := context.Background()
 
 	contents := []string{
@@ -203,43 +211,44 @@ func TestTruncateContent(t *testing.T) {
 			maxChars: 8,
 			want:     "Hello...",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := truncateContent(tt.content, tt.maxChars)
-			assert.Equal(t, tt.want, got)
-		})
-	}
-}
-
-func TestExtractTagsFromText(t *testing.T) {
-	tests := []struct {
-		name string
-		text string
-		want []string
-	}{
 		{
-			name: "simple tags",
-			text: "GraphQL\nAPI\nREST",
-			want: []string{"GraphQL", "API", "REST"},
+			name:     "multibyte content",
+			content:  "積読の本を読む",
+			maxChars: 5,
+			want:     "積読...",
 		},
 		{
-			name: "tags with punctuation",
-			text: "- GraphQL\n- API\n- REST",
-			want: []string{"GraphQL", "API", "REST"},
+			name:     "no room for an ellipsis",
+			content:  "Hello",
+			maxChars: 2,
+			want:     "He",
 		},
 		{
-			name: "mixed content",
-			text: "Here are the tags:\nGraphQL\nThis is a long sentence that should not be a tag\nAPI",
-			want: []string{"GraphQL", "API"},
+			name:     "zero limit",
+			content:  "Hello",
+			maxChars: 0,
+			want:     "",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := extractTagsFromText(tt.text)
+			got := truncateContent(tt.content, tt.maxChars)
 			assert.Equal(t, tt.want, got)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestParseTags(t *testing.T) {
+	tags, err := parseTags(`{"tags": [{"name": "GraphQL", "confidence": 0.9}, {"name": "api", "confidence": -1}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, []ai.Tag{{Name: "GraphQL", Confidence: 0.9}, {Name: "api", Confidence: 0}}, tags)
+
+	tags, err = parseTags(`{"tags": []}`)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	// Replies that are not JSON are errors rather than guessed at
+	_, err = parseTags("Here are the tags:\nGraphQL\nAPI")
+	assert.True(t, errors.Is(err, errs.ErrAIUnavailable))
+}
@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/motoya-k/tsundoc/internal/domain/ai"
+)
+
+//go:embed prompts
+var defaultPrompts embed.FS
+
+// PromptData is what prompt templates can refer to
+type PromptData struct {
+	// Content is the content to work on, truncated to fit the prompt
+	Content string
+	// Language is the main language of the content, such as English
+	Language string
+	// ExistingTags are tags already used in the library, most used first
+	ExistingTags []string
+}
+
+// Prompt is the template of the system and user messages of an operation. A
+// template defines them as the "system" and "user" templates.
+type Prompt struct {
+	// Version identifies the template text, so that output can be traced
+	// back to the prompt that produced it
+	Version string
+	tmpl    *template.Template
+}
+
+// Render executes the template and returns the system and user messages
+func (p *Prompt) Render(data PromptData) (string, string, error) {
+	var system, user bytes.Buffer
+	if err := p.tmpl.ExecuteTemplate(&system, "system", data); err != nil {
+		return "", "", fmt.Errorf("failed to render system prompt: %w", err)
+	}
+	if err := p.tmpl.ExecuteTemplate(&user, "user", data); err != nil {
+		return "", "", fmt.Errorf("failed to render user prompt: %w", err)
+	}
+	return strings.TrimSpace(system.String()), strings.TrimSpace(user.String()), nil
+}
+
+// LoadPrompts loads the prompt of every operation from <operation>.tmpl in
+// dir, falling back to the embedded default when dir is empty or has no such
+// file
+func LoadPrompts(dir string) (map[ai.Operation]*Prompt, error) {
+	prompts := make(map[ai.Operation]*Prompt, len(ai.Operations))
+	for _, op := range ai.Operations {
+		name := string(op) + ".tmpl"
+
+		text, err := fs.ReadFile(defaultPrompts, "prompts/"+name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read default prompt %s: %w", name, err)
+		}
+		if dir != "" {
+			override, err := os.ReadFile(filepath.Join(dir, name))
+			switch {
+			case err == nil:
+				text = override
+			case !errors.Is(err, fs.ErrNotExist):
+				return nil, fmt.Errorf("failed to read prompt %s: %w", name, err)
+			}
+		}
+
+		prompt, err := parsePrompt(name, text)
+		if err != nil {
+			return nil, err
+		}
+		prompts[op] = prompt
+	}
+	return prompts, nil
+}
+
+func parsePrompt(name string, text []byte) (*Prompt, error) {
+	tmpl, err := template.New(name).
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt %s: %w", name, err)
+	}
+	for _, part := range []string{"system", "user"} {
+		if tmpl.Lookup(part) == nil {
+			return nil, fmt.Errorf("prompt %s does not define %q", name, part)
+		}
+	}
+
+	sum := sha256.Sum256(text)
+	prompt := &Prompt{Version: hex.EncodeToString(sum[:])[:12], tmpl: tmpl}
+
+	// Catch references to unknown variables now rather than on the first call
+	if _, _, err := prompt.Render(PromptData{}); err != nil {
+		return nil, fmt.Errorf("invalid prompt %s: %w", name, err)
+	}
+	return prompt, nil
+}
+
+// detectLanguage guesses the main language of content from its letters. It
+// only tells apart the languages the library is mostly written in.
+func detectLanguage(content string) string {
+	var latin, japanese, other int
+	for _, r := range content {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han):
+			japanese++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.IsLetter(r):
+			other++
+		}
+	}
+
+	// A Japanese character carries about as much as a short English word
+	switch {
+	case japanese > 0 && japanese*3 >= latin:
+		return "Japanese"
+	case other > latin:
+		return "the same language as the content"
+	}
+	return "English"
+}
@@ -0,0 +1,145 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/ai"
+)
+
+func TestLoadPrompts_Defaults(t *testing.T) {
+	prompts, err := LoadPrompts("")
+	require.NoError(t, err)
+
+	for _, op := range ai.Operations {
+		require.Contains(t, prompts, op)
+		assert.Len(t, prompts[op].Version, 12)
+	}
+
+	system, user, err := prompts[ai.OperationGenerateTags].Render(PromptData{
+		Content:      "GraphQL is a query language",
+		Language:     "English",
+		ExistingTags: []string{"api", "go"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, system, "reuse the library's tags")
+	assert.Contains(t, user, "The library already uses these tags: api, go\n")
+	assert.Contains(t, user, "Content:\nGraphQL is a query language")
+
+	_, user, err = prompts[ai.OperationGenerateTags].Render(PromptData{Content: "GraphQL"})
+	require.NoError(t, err)
+	assert.NotContains(t, user, "already uses")
+}
+
+func TestLoadPrompts_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	override := `{{define "system"}}Be brief.{{end}}{{define "user"}}Title in {{.Language}}: {{.Content}}{{end}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "generate_title.tmpl"), []byte(override), 0o644))
+
+	defaults, err := LoadPrompts("")
+	require.NoError(t, err)
+	prompts, err := LoadPrompts(dir)
+	require.NoError(t, err)
+
+	system, user, err := prompts[ai.OperationGenerateTitle].Render(PromptData{Content: "本文", Language: "Japanese"})
+	require.NoError(t, err)
+	assert.Equal(t, "Be brief.", system)
+	assert.Equal(t, "Title in Japanese: 本文", user)
+
+	// The version follows the template, other operations keep the default
+	assert.NotEqual(t, defaults[ai.OperationGenerateTitle].Version, prompts[ai.OperationGenerateTitle].Version)
+	assert.Equal(t, defaults[ai.OperationGenerateTags].Version, prompts[ai.OperationGenerateTags].Version)
+}
+
+func TestLoadPrompts_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "syntax error", template: `{{define "system"}}x{{end}}{{define "user"}}{{.Content}`},
+		{name: "missing user message", template: `{{define "system"}}x{{end}}`},
+		{name: "unknown variable", template: `{{define "system"}}x{{end}}{{define "user"}}{{.Text}}{{end}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "merge_contents.tmpl"), []byte(tt.template), 0o644))
+
+			_, err := LoadPrompts(dir)
+			assert.ErrorContains(t, err, "merge_contents.tmpl")
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	assert.Equal(t, "English", detectLanguage("GraphQL is a query language for APIs"))
+	assert.Equal(t, "Japanese", detectLanguage("GraphQLはAPIのためのクエリ言語です"))
+	assert.Equal(t, "the same language as the content", detectLanguage("Это язык запросов"))
+	assert.Equal(t, "English", detectLanguage(""))
+}
+
+func TestOpenAIService_OperationConfig(t *testing.T) {
+	// The schema is a json.Marshaler in openai.ChatCompletionRequest, which
+	// cannot be decoded into
+	var request struct {
+		Model          string                         `json:"model"`
+		Temperature    float32                        `json:"temperature"`
+		MaxTokens      int                            `json:"max_tokens"`
+		Messages       []openai.ChatCompletionMessage `json:"messages"`
+		ResponseFormat struct {
+			Type       string `json:"type"`
+			JSONSchema struct {
+				Strict bool           `json:"strict"`
+				Schema map[string]any `json:"schema"`
+			} `json:"json_schema"`
+		} `json:"response_format"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: `{"tags": [{"name": "api", "confidence": 0.9}, {"name": "GraphQL", "confidence": 1.2}]}`}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.Operations = map[ai.Operation]OperationConfig{
+		ai.OperationGenerateTags: {Model: "gpt-4o-mini", Temperature: 0.2, MaxTokens: 64, Timeout: time.Second, MaxContentChars: 10},
+	}
+	svc, err := NewOpenAIService("test-key", cfg)
+	require.NoError(t, err)
+	clientConfig := openai.DefaultConfig("test-key")
+	clientConfig.BaseURL = server.URL
+	svc.client = openai.NewClientWithConfig(clientConfig)
+
+	tags, err := svc.GenerateTags(context.Background(), "GraphQL is a query language", []string{"api"})
+	require.NoError(t, err)
+	assert.Equal(t, []ai.Tag{{Name: "api", Confidence: 0.9}, {Name: "GraphQL", Confidence: 1}}, tags)
+
+	assert.Equal(t, "gpt-4o-mini", request.Model)
+	assert.Equal(t, float32(0.2), request.Temperature)
+	assert.Equal(t, 64, request.MaxTokens)
+	require.Len(t, request.Messages, 2)
+	assert.Contains(t, request.Messages[1].Content, "Content:\nGraphQL...")
+	assert.Contains(t, request.Messages[1].Content, "already uses these tags: api")
+	assert.Equal(t, "json_schema", request.ResponseFormat.Type)
+	assert.True(t, request.ResponseFormat.JSONSchema.Strict)
+	assert.Equal(t, []any{"tags"}, request.ResponseFormat.JSONSchema.Schema["required"])
+
+	// Operations left out of the config keep their defaults
+	assert.Equal(t, DefaultConfig().Operations[ai.OperationGenerateTitle], svc.operations[ai.OperationGenerateTitle])
+	assert.Equal(t, svc.prompts[ai.OperationGenerateTags].Version, svc.PromptVersion(ai.OperationGenerateTags))
+}
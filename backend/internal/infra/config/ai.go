@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	domainAI "github.com/motoya-k/tsundoc/internal/domain/ai"
+	"github.com/motoya-k/tsundoc/internal/infra/ai"
+)
+
+// NewAIConfig reads the prompt directory and the model settings of each
+// operation. AI_MODEL changes the model of every operation;
+// AI_<OPERATION>_MODEL, _TEMPERATURE, _MAX_TOKENS and _TIMEOUT, such as
+// AI_GENERATE_TAGS_MODEL, change one operation.
+func NewAIConfig() (*ai.Config, error) {
+	cfg := ai.DefaultConfig()
+	cfg.PromptsDir = os.Getenv("AI_PROMPTS_DIR")
+
+	defaultModel := os.Getenv("AI_MODEL")
+	for _, op := range domainAI.Operations {
+		opCfg := cfg.Operations[op]
+		prefix := "AI_" + strings.ToUpper(string(op)) + "_"
+
+		if defaultModel != "" {
+			opCfg.Model = defaultModel
+		}
+		opCfg.Model = getEnvOrDefault(prefix+"MODEL", opCfg.Model)
+
+		temperature, err := getEnvTemperature(prefix+"TEMPERATURE", opCfg.Temperature)
+		if err != nil {
+			return nil, err
+		}
+		opCfg.Temperature = temperature
+
+		maxTokens, err := getEnvInt(prefix+"MAX_TOKENS", opCfg.MaxTokens)
+		if err != nil {
+			return nil, err
+		}
+		opCfg.MaxTokens = maxTokens
+
+		timeout, err := getEnvDuration(prefix+"TIMEOUT", opCfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		opCfg.Timeout = timeout
+
+		cfg.Operations[op] = opCfg
+	}
+
+	return &cfg, nil
+}
+
+func getEnvTemperature(key string, defaultValue float32) (float32, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	f, err := strconv.ParseFloat(value, 32)
+	if err != nil || f < 0 || f > 2 {
+		return 0, fmt.Errorf("%s must be a number between 0 and 2, got %q", key, value)
+	}
+	return float32(f), nil
+}
@@ -8,6 +8,7 @@ import (
 )
 
 type DatabaseConfig struct {
+	URL      string
 	Host     string
 	Port     string
 	User     string
@@ -24,6 +25,7 @@ func NewDatabaseConfig() *DatabaseConfig {
 	}
 	
 	return &DatabaseConfig{
+		URL:      os.Getenv("DATABASE_URL"),
 		Host:     getEnvOrDefault("DB_HOST", defaultHost),
 		Port:     getEnvOrDefault("DB_PORT", "5432"),
 		User:     getEnvOrDefault("DB_USER", "tsundoc"),
@@ -33,7 +35,12 @@ func NewDatabaseConfig() *DatabaseConfig {
 	}
 }
 
+// DSN returns DATABASE_URL when set; otherwise a Postgres URL is built from
+// the DB_* variables
 func (dc *DatabaseConfig) DSN() string {
+	if dc.URL != "" {
+		return dc.URL
+	}
 	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		dc.User, dc.Password, dc.Host, dc.Port, dc.DBName, dc.SSLMode)
 }
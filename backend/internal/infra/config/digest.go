@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// DigestConfig holds the settings for sending email digests. Digests are only
+// sent when SMTPHost is set; previews work without it.
+type DigestConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// From is the sender address of the digest emails
+	From string
+	// AppURL is the address of the web app that digests link to
+	AppURL string
+	// CheckInterval is how often the scheduler looks for digests that are due
+	CheckInterval time.Duration
+}
+
+func NewDigestConfig() (*DigestConfig, error) {
+	port, err := getEnvInt("SMTP_PORT", 587)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := getEnvDuration("DIGEST_CHECK_INTERVAL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DigestConfig{
+		SMTPHost:      os.Getenv("SMTP_HOST"),
+		SMTPPort:      port,
+		SMTPUsername:  os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:  os.Getenv("SMTP_PASSWORD"),
+		From:          getEnvOrDefault("SMTP_FROM", "tsundoc <digest@tsundoc.local>"),
+		AppURL:        os.Getenv("DIGEST_APP_URL"),
+		CheckInterval: interval,
+	}, nil
+}
+
+// Enabled reports whether digests can be sent
+func (c *DigestConfig) Enabled() bool {
+	return c.SMTPHost != ""
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// GraphQLConfig holds the limits applied to the GraphQL endpoint
+type GraphQLConfig struct {
+	MaxDepth      int
+	MaxComplexity int
+	APQCacheSize  int
+	// AllowlistFile is the persisted documents manifest; when set only the
+	// documents it lists can be executed
+	AllowlistFile string
+	// Development enables introspection and the playground
+	Development bool
+}
+
+func NewGraphQLConfig() (*GraphQLConfig, error) {
+	maxDepth, err := getEnvInt("GRAPHQL_MAX_DEPTH", 10)
+	if err != nil {
+		return nil, err
+	}
+	maxComplexity, err := getEnvInt("GRAPHQL_MAX_COMPLEXITY", 500)
+	if err != nil {
+		return nil, err
+	}
+	apqCacheSize, err := getEnvInt("GRAPHQL_APQ_CACHE_SIZE", 100)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GraphQLConfig{
+		MaxDepth:      maxDepth,
+		MaxComplexity: maxComplexity,
+		APQCacheSize:  apqCacheSize,
+		AllowlistFile: os.Getenv("GRAPHQL_ALLOWLIST_FILE"),
+		Development:   os.Getenv("ENVIRONMENT") == "development",
+	}, nil
+}
+
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+	}
+	return n, nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	Port string
+	// MetricsAddr is where /metrics is served, apart from the public port
+	MetricsAddr       string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	// WriteTimeout bounds the whole request, so it must leave room for AI calls
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout is how long in-flight requests and webhook deliveries
+	// may take to finish after SIGTERM
+	ShutdownTimeout time.Duration
+	// ReadinessTimeout bounds the checks run by /readyz
+	ReadinessTimeout time.Duration
+	// ReadinessCheckAI adds the AI provider to the readiness checks
+	ReadinessCheckAI bool
+}
+
+func NewServerConfig() (*ServerConfig, error) {
+	cfg := &ServerConfig{
+		Port:             getEnvOrDefault("PORT", "8080"),
+		MetricsAddr:      getEnvOrDefault("METRICS_ADDR", "localhost:9090"),
+		ReadinessCheckAI: os.Getenv("READINESS_CHECK_AI") == "true",
+	}
+
+	durations := []struct {
+		key          string
+		defaultValue time.Duration
+		target       *time.Duration
+	}{
+		{"SERVER_READ_HEADER_TIMEOUT", 5 * time.Second, &cfg.ReadHeaderTimeout},
+		{"SERVER_READ_TIMEOUT", 15 * time.Second, &cfg.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", 90 * time.Second, &cfg.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", 120 * time.Second, &cfg.IdleTimeout},
+		{"SERVER_SHUTDOWN_TIMEOUT", 30 * time.Second, &cfg.ShutdownTimeout},
+		{"READINESS_TIMEOUT", 2 * time.Second, &cfg.ReadinessTimeout},
+	}
+	for _, d := range durations {
+		value, err := getEnvDuration(d.key, d.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		*d.target = value
+	}
+
+	return cfg, nil
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration such as 30s, got %q", key, value)
+	}
+	return d, nil
+}
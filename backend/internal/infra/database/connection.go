@@ -1,9 +1,12 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -12,23 +15,77 @@ type DB struct {
 	*gorm.DB
 }
 
+// NewConnection opens the database named by databaseURL. The scheme selects
+// the backend: postgres:// and postgresql:// connect to Postgres, while
+// sqlite: and file: open a local SQLite database file.
 func NewConnection(databaseURL string) (*DB, error) {
 	config := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	}
 
-	db, err := gorm.Open(postgres.Open(databaseURL), config)
+	dialector, err := openDialector(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	return &DB{DB: db}, nil
+	conn := &DB{DB: db}
+	if conn.Dialect() == DialectSQLite {
+		if err := conn.checkSQLiteFTS5(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func openDialector(databaseURL string) (gorm.Dialector, error) {
+	switch {
+	case strings.HasPrefix(databaseURL, "postgres://"), strings.HasPrefix(databaseURL, "postgresql://"):
+		return postgres.Open(databaseURL), nil
+	case strings.HasPrefix(databaseURL, "sqlite:"), strings.HasPrefix(databaseURL, "file:"):
+		return sqlite.Open(sqliteDSN(databaseURL)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database URL scheme: use postgres:// or sqlite://")
+	}
+}
+
+// sqliteDSN turns sqlite:///abs/path.db, sqlite://rel/path.db, and
+// sqlite:path.db into a go-sqlite3 DSN. file: URIs are passed through.
+func sqliteDSN(databaseURL string) string {
+	dsn := databaseURL
+	if strings.HasPrefix(dsn, "sqlite:") {
+		path := strings.TrimPrefix(strings.TrimPrefix(dsn, "sqlite:"), "//")
+		if path == ":memory:" {
+			// Every pooled connection would otherwise get its own empty database
+			path = "file::memory:?cache=shared"
+		}
+		dsn = path
+	}
+
+	// Enforce foreign keys and let readers proceed while a write is in progress
+	params := "_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000"
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + params
+	}
+	return dsn + "?" + params
 }
 
-func (db *DB) AutoMigrate() error {
-	return db.DB.AutoMigrate(
-		&Book{},
-	)
+// checkSQLiteFTS5 fails early when the binary was built without the
+// sqlite_fts5 tag, since book search relies on an FTS5 index
+func (db *DB) checkSQLiteFTS5() error {
+	var enabled bool
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled).Error; err != nil {
+		return fmt.Errorf("failed to inspect SQLite build: %w", err)
+	}
+	if !enabled {
+		return fmt.Errorf("SQLite support requires FTS5; rebuild with -tags sqlite_fts5")
+	}
+	return nil
 }
 
 func (db *DB) Close() error {
@@ -45,4 +102,13 @@ func (db *DB) Ping() error {
 		return err
 	}
 	return sqlDB.Ping()
+}
+
+// PingContext checks that a pooled connection to the database is usable
+func (db *DB) PingContext(ctx context.Context) error {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
 }
\ No newline at end of file
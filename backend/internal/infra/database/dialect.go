@@ -0,0 +1,90 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Dialect identifies the SQL backend behind a DB
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+// booksFTSTable is the FTS5 index over books created by the SQLite migrations
+const booksFTSTable = "books_fts"
+
+// minFTSKeywordLength is the shortest keyword the trigram tokenizer can match
+const minFTSKeywordLength = 3
+
+func (db *DB) Dialect() Dialect {
+	return Dialect(db.DB.Dialector.Name())
+}
+
+// JSONArrayElements returns a table expression that yields the elements of
+// the JSON array in column as rows with a single "value" column
+func (db *DB) JSONArrayElements(column string) string {
+	if db.Dialect() == DialectSQLite {
+		return fmt.Sprintf("json_each(%s)", column)
+	}
+	return fmt.Sprintf("jsonb_array_elements_text(%s) AS elements(value)", column)
+}
+
+// DayOf returns an expression for the UTC day of the timestamp in column, as
+// YYYY-MM-DD text
+func (db *DB) DayOf(column string) string {
+	if db.Dialect() == DialectSQLite {
+		return fmt.Sprintf("date(%s)", column)
+	}
+	return fmt.Sprintf("to_char(%s AT TIME ZONE 'UTC', 'YYYY-MM-DD')", column)
+}
+
+// WeekOf returns an expression for the Monday that starts the UTC week of the
+// timestamp in column, as YYYY-MM-DD text
+func (db *DB) WeekOf(column string) string {
+	if db.Dialect() == DialectSQLite {
+		return fmt.Sprintf("date(%s, '-6 days', 'weekday 1')", column)
+	}
+	return fmt.Sprintf("to_char(date_trunc('week', %s AT TIME ZONE 'UTC'), 'YYYY-MM-DD')", column)
+}
+
+// annotatedBooks matches books that a user annotated with a note containing
+// a pattern
+const annotatedBooks = "books.id IN (SELECT book_id FROM annotations WHERE user_id = ? AND LOWER(note) LIKE ?)"
+
+// MatchBooks limits a books query to rows whose title, content, or tags
+// contain keyword, ignoring case, and to books where one of userID's
+// annotation notes contains it. SQLite uses the FTS5 index for the books
+// columns when it exists; otherwise they are scanned with LIKE.
+func (db *DB) MatchBooks(keyword, userID string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if keyword == "" {
+			return tx
+		}
+		query, args := db.MatchBooksCondition(keyword, userID)
+		return tx.Where(query, args...)
+	}
+}
+
+// MatchBooksCondition returns the condition MatchBooks applies for a
+// non-empty keyword, for use in larger conditions
+func (db *DB) MatchBooksCondition(keyword, userID string) (string, []interface{}) {
+	pattern := "%" + strings.ToLower(keyword) + "%"
+	if db.Dialect() == DialectSQLite && utf8.RuneCountInString(keyword) >= minFTSKeywordLength && db.Migrator().HasTable(booksFTSTable) {
+		return "books.rowid IN (SELECT rowid FROM " + booksFTSTable + " WHERE " + booksFTSTable + " MATCH ?) OR " + annotatedBooks,
+			[]interface{}{ftsPhrase(keyword), userID, pattern}
+	}
+	return "LOWER(books.title) LIKE ? OR LOWER(books.content) LIKE ? OR EXISTS (SELECT 1 FROM " + db.JSONArrayElements("books.tags") + " WHERE LOWER(value) LIKE ?) OR " + annotatedBooks,
+		[]interface{}{pattern, pattern, pattern, userID, pattern}
+}
+
+// ftsPhrase quotes keyword as an FTS5 phrase so that operators in user input
+// are matched literally
+func ftsPhrase(keyword string) string {
+	return `"` + strings.ReplaceAll(keyword, `"`, `""`) + `"`
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/migrations"
+)
+
+func TestMatchBooks_SQLite(t *testing.T) {
+	db := openSQLiteFile(t)
+
+	fsys, err := migrations.For(string(db.Dialect()))
+	require.NoError(t, err)
+	m, err := NewMigrator(db, fsys)
+	require.NoError(t, err)
+	_, err = m.Up(context.Background())
+	require.NoError(t, err)
+
+	books := []Book{
+		{ID: "b1", Title: "GraphQL basics", Content: "Schemas and resolvers", Tags: []string{"api"}, UserID: "u1"},
+		{ID: "b2", Title: "Go concurrency", Content: "Channels and goroutines", Tags: []string{"golang", "Go"}, UserID: "u1"},
+		{ID: "b3", Title: "Cooking", Content: "Say \"hello\" to pasta", Tags: []string{}, UserID: "u1"},
+	}
+	require.NoError(t, db.Create(&books).Error)
+	annotations := []Annotation{
+		{ID: "a1", BookID: "b3", UserID: "u1", Quote: "pasta", Color: "yellow", Note: "Tasty with basil"},
+		{ID: "a2", BookID: "b1", UserID: "u2", Quote: "Schemas", Color: "yellow", Note: "Secret plan"},
+	}
+	require.NoError(t, db.Create(&annotations).Error)
+
+	tests := []struct {
+		name    string
+		keyword string
+		want    []string
+	}{
+		{name: "title substring ignores case", keyword: "graphql", want: []string{"b1"}},
+		{name: "content substring", keyword: "routine", want: []string{"b2"}},
+		{name: "tag", keyword: "golang", want: []string{"b2"}},
+		{name: "short keyword falls back to LIKE", keyword: "go", want: []string{"b2"}},
+		{name: "quotes are matched literally", keyword: `"hello"`, want: []string{"b3"}},
+		{name: "FTS operators are matched literally", keyword: "api OR pasta", want: []string{}},
+		{name: "no match", keyword: "python", want: []string{}},
+		{name: "annotation note", keyword: "basil", want: []string{"b3"}},
+		{name: "short keyword in annotation note", keyword: "ba", want: []string{"b1", "b3"}},
+		{name: "annotation notes of other users", keyword: "secret", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			err := db.Model(&Book{}).Scopes(db.MatchBooks(tt.keyword, "u1")).Order("id").Pluck("id", &ids).Error
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+
+	// The FTS index follows updates and deletes through triggers
+	require.NoError(t, db.Model(&Book{}).Where("id = ?", "b1").Update("title", "REST basics").Error)
+	require.NoError(t, db.Where("id = ?", "b2").Delete(&Book{}).Error)
+
+	var ids []string
+	require.NoError(t, db.Model(&Book{}).Scopes(db.MatchBooks("graphql", "u1")).Pluck("id", &ids).Error)
+	assert.Empty(t, ids)
+	require.NoError(t, db.Model(&Book{}).Scopes(db.MatchBooks("rest basics", "u1")).Pluck("id", &ids).Error)
+	assert.Equal(t, []string{"b1"}, ids)
+}
+
+func TestDayOfAndWeekOf_SQLite(t *testing.T) {
+	db := openSQLiteFile(t)
+	require.NoError(t, db.AutoMigrate(&Book{}))
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	books := []Book{
+		// Sunday in UTC
+		{ID: "b1", Title: "b1", UserID: "u1", CreatedAt: time.Date(2024, 5, 13, 8, 0, 0, 0, tokyo)},
+		{ID: "b2", Title: "b2", UserID: "u1", CreatedAt: time.Date(2024, 5, 13, 0, 30, 0, 0, time.UTC)},
+		{ID: "b3", Title: "b3", UserID: "u1", CreatedAt: time.Date(2024, 5, 15, 12, 0, 0, 123456789, time.UTC)},
+	}
+	require.NoError(t, db.Create(&books).Error)
+
+	var rows []struct {
+		ID   string
+		Day  string
+		Week string
+	}
+	err := db.Model(&Book{}).
+		Select("id, " + db.DayOf("created_at") + " AS day, " + db.WeekOf("created_at") + " AS week").
+		Order("id").Scan(&rows).Error
+	require.NoError(t, err)
+
+	require.Len(t, rows, 3)
+	assert.Equal(t, "2024-05-12", rows[0].Day)
+	assert.Equal(t, "2024-05-06", rows[0].Week)
+	assert.Equal(t, "2024-05-13", rows[1].Day)
+	assert.Equal(t, "2024-05-13", rows[1].Week)
+	assert.Equal(t, "2024-05-15", rows[2].Day)
+	assert.Equal(t, "2024-05-13", rows[2].Week)
+}
@@ -0,0 +1,225 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// migrationsTable is compatible with golang-migrate, so databases migrated
+// with the migrate CLI keep their version
+const migrationsTable = "schema_migrations"
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a pair of SQL scripts moving the schema one version up or down
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// ErrSchemaOutdated is returned by CheckVersion when migrations are pending
+var ErrSchemaOutdated = errors.New("database schema is out of date")
+
+// MigrationStatus describes the schema version of the database
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+	Pending []Migration
+}
+
+// Migrator applies versioned SQL migrations
+type Migrator struct {
+	db         *DB
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations in fsys, named NNNNNN_name.up.sql and
+// NNNNNN_name.down.sql
+func NewMigrator(db *DB, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the version of the newest migration
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status returns the current version and the migrations not applied yet
+func (m *Migrator) Status(ctx context.Context) (*MigrationStatus, error) {
+	version, dirty, err := m.version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Version: version, Dirty: dirty, Latest: m.Latest()}
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// CheckVersion fails when the database is dirty or behind the embedded
+// migrations. A database ahead of the binary is accepted so that rolling back
+// a deployment does not require rolling back the schema.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("database schema is dirty at version %d; fix it manually and force the version", status.Version)
+	}
+	if status.Version < status.Latest {
+		return fmt.Errorf("%w: version %d, expected %d", ErrSchemaOutdated, status.Version, status.Latest)
+	}
+	return nil
+}
+
+// Up applies all pending migrations in order and returns the applied ones
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status.Dirty {
+		return nil, fmt.Errorf("database schema is dirty at version %d; fix it manually and force the version", status.Version)
+	}
+
+	var applied []Migration
+	for _, migration := range status.Pending {
+		if err := m.apply(ctx, migration.Up, migration.Version); err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// Down reverts the given number of migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	version, dirty, err := m.version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("database schema is dirty at version %d; fix it manually and force the version", version)
+	}
+
+	var reverted []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := m.migrations[i]
+		if migration.Version > version {
+			continue
+		}
+		if migration.Down == "" {
+			return reverted, fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+
+		var previous uint
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+		if err := m.apply(ctx, migration.Down, previous); err != nil {
+			return reverted, fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// apply runs a script and records the resulting version in one transaction.
+// Version 0 means no migration is applied.
+func (m *Migrator) apply(ctx context.Context, script string, version uint) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(script).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM " + migrationsTable).Error; err != nil {
+			return err
+		}
+		if version == 0 {
+			return nil
+		}
+		return tx.Exec("INSERT INTO "+migrationsTable+" (version, dirty) VALUES (?, ?)", version, false).Error
+	})
+}
+
+func (m *Migrator) version(ctx context.Context) (uint, bool, error) {
+	db := m.db.WithContext(ctx)
+	err := db.Exec("CREATE TABLE IF NOT EXISTS " + migrationsTable + " (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").Error
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create %s table: %w", migrationsTable, err)
+	}
+
+	var rows []struct {
+		Version uint
+		Dirty   bool
+	}
+	if err := db.Raw("SELECT version, dirty FROM " + migrationsTable + " LIMIT 1").Scan(&rows).Error; err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Version, rows[0].Dirty, nil
+}
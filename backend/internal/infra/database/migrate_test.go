@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/migrations"
+)
+
+func setupMigratorTestDB(t *testing.T) *DB {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	return &DB{DB: gormDB}
+}
+
+var testMigrations = fstest.MapFS{
+	"000001_create_notes.up.sql":   {Data: []byte("CREATE TABLE notes (id INTEGER PRIMARY KEY);")},
+	"000001_create_notes.down.sql": {Data: []byte("DROP TABLE notes;")},
+	"000002_add_title.up.sql":      {Data: []byte("ALTER TABLE notes ADD COLUMN title TEXT;\nCREATE INDEX idx_notes_title ON notes(title);")},
+	"000002_add_title.down.sql":    {Data: []byte("DROP INDEX idx_notes_title;\nALTER TABLE notes DROP COLUMN title;")},
+	"README.md":                    {Data: []byte("not a migration")},
+}
+
+func TestMigrator_UpAndDown(t *testing.T) {
+	db := setupMigratorTestDB(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), m.Latest())
+
+	err = m.CheckVersion(ctx)
+	assert.True(t, errors.Is(err, ErrSchemaOutdated))
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+	require.NoError(t, m.CheckVersion(ctx))
+	assert.True(t, db.Migrator().HasColumn("notes", "title"))
+
+	// Up is a no-op once the schema is current
+	applied, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	reverted, err := m.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, uint(2), reverted[0].Version)
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), status.Version)
+	require.Len(t, status.Pending, 1)
+	assert.Equal(t, "add_title", status.Pending[0].Name)
+
+	_, err = m.Down(ctx, 5)
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable("notes"))
+
+	status, err = m.Status(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, status.Version)
+}
+
+func TestMigrator_FailedMigrationKeepsVersion(t *testing.T) {
+	db := setupMigratorTestDB(t)
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"000001_create_notes.up.sql": {Data: []byte("CREATE TABLE notes (id INTEGER PRIMARY KEY);")},
+		"000002_broken.up.sql":       {Data: []byte("ALTER TABLE missing ADD COLUMN title TEXT;")},
+	}
+	m, err := NewMigrator(db, fsys)
+	require.NoError(t, err)
+
+	applied, err := m.Up(ctx)
+	assert.Error(t, err)
+	assert.Len(t, applied, 1)
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), status.Version)
+	assert.False(t, status.Dirty)
+}
+
+func TestMigrator_DirtyDatabaseIsRejected(t *testing.T) {
+	db := setupMigratorTestDB(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+	_, err = m.Status(ctx)
+	require.NoError(t, err)
+
+	// golang-migrate leaves the version dirty when a migration fails halfway
+	require.NoError(t, db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (1, true)").Error)
+
+	_, err = m.Up(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, m.CheckVersion(ctx).Error(), "dirty")
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	latest := map[string]uint{}
+	for _, dialect := range []string{"postgres", "sqlite"} {
+		fsys, err := migrations.For(dialect)
+		require.NoError(t, err)
+		m, err := NewMigrator(setupMigratorTestDB(t), fsys)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, m.migrations, dialect)
+		for i, migration := range m.migrations {
+			assert.Equal(t, uint(i+1), migration.Version, "%s migration versions must be contiguous", dialect)
+			assert.NotEmpty(t, migration.Down, "%s migration %d_%s needs a down script", dialect, migration.Version, migration.Name)
+		}
+		latest[dialect] = m.Latest()
+	}
+	assert.Equal(t, latest["postgres"], latest["sqlite"], "SQLite migrations must stay aligned with Postgres")
+
+	_, err := migrations.For("mysql")
+	assert.Error(t, err)
+}
+
+func TestSQLiteMigrations_UpAndDown(t *testing.T) {
+	db := openSQLiteFile(t)
+	ctx := context.Background()
+
+	fsys, err := migrations.For(string(db.Dialect()))
+	require.NoError(t, err)
+	m, err := NewMigrator(db, fsys)
+	require.NoError(t, err)
+
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, m.CheckVersion(ctx))
+	for _, table := range []string{"books", "books_fts", "share_links", "workspaces", "workspace_members", "access_tokens", "webhooks", "webhook_deliveries", "annotations", "book_links", "reading_states", "digest_settings"} {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	_, err = m.Down(ctx, int(m.Latest()))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable("books"))
+}
@@ -7,16 +7,217 @@ import (
 )
 
 type Book struct {
-	ID        string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
-	Title     string         `gorm:"not null" json:"title"`
-	Content   string         `gorm:"type:text" json:"content"`
-	Tags      []string       `gorm:"type:text[]" json:"tags"`
-	UserID    string         `gorm:"not null;index" json:"user_id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID             string            `gorm:"primaryKey;type:uuid" json:"id"`
+	Title          string            `gorm:"not null" json:"title"`
+	Content        string            `gorm:"type:text" json:"content"`
+	URL            string            `gorm:"type:text;not null;default:''" json:"url"`
+	Tags           []string          `gorm:"serializer:json" json:"tags"` // jsonb on Postgres, TEXT on SQLite
+	UserID         string            `gorm:"not null;index" json:"user_id"`
+	WorkspaceID    *string           `gorm:"type:uuid;index" json:"workspace_id,omitempty"`
+	Version        int               `gorm:"not null;default:1" json:"version"`
+	Enrichment     string            `gorm:"not null;default:''" json:"enrichment"`
+	MergedFrom     int               `gorm:"not null;default:0" json:"merged_from"`
+	PromptVersions map[string]string `gorm:"serializer:json" json:"prompt_versions"` // jsonb on Postgres, TEXT on SQLite
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (Book) TableName() string {
 	return "books"
-}
\ No newline at end of file
+}
+
+type ShareLink struct {
+	ID           string     `gorm:"primaryKey;type:uuid" json:"id"`
+	Token        string     `gorm:"not null;uniqueIndex" json:"token"`
+	BookID       string     `gorm:"type:uuid;not null;index" json:"book_id"`
+	UserID       string     `gorm:"not null;index" json:"user_id"`
+	PasswordHash string     `json:"-"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ViewCount    int        `gorm:"not null;default:0" json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (ShareLink) TableName() string {
+	return "share_links"
+}
+
+type Annotation struct {
+	ID          string    `gorm:"primaryKey;type:uuid" json:"id"`
+	BookID      string    `gorm:"type:uuid;not null;index" json:"book_id"`
+	UserID      string    `gorm:"not null;index" json:"user_id"`
+	StartOffset int       `gorm:"not null" json:"start_offset"`
+	EndOffset   int       `gorm:"not null" json:"end_offset"`
+	Quote       string    `gorm:"type:text;not null" json:"quote"`
+	Color       string    `gorm:"not null" json:"color"`
+	Note        string    `gorm:"type:text;not null;default:''" json:"note"`
+	Detached    bool      `gorm:"not null;default:false" json:"detached"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Annotation) TableName() string {
+	return "annotations"
+}
+
+// BookLink is a [[target]] link written in the content of a source book
+type BookLink struct {
+	SourceID string  `gorm:"primaryKey;type:uuid" json:"source_id"`
+	Target   string  `gorm:"primaryKey" json:"target"`
+	TargetID *string `gorm:"type:uuid;index" json:"target_id,omitempty"`
+	Position int     `gorm:"not null" json:"position"`
+}
+
+func (BookLink) TableName() string {
+	return "book_links"
+}
+
+// ReadingState is where a user stands with reading a book. Books without a
+// row are unread.
+type ReadingState struct {
+	BookID     string     `gorm:"primaryKey;type:uuid" json:"book_id"`
+	UserID     string     `gorm:"primaryKey;index:idx_reading_states_user_id_status" json:"user_id"`
+	Status     string     `gorm:"not null;index:idx_reading_states_user_id_status" json:"status"`
+	Progress   int        `gorm:"not null;default:0" json:"progress"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (ReadingState) TableName() string {
+	return "reading_states"
+}
+
+type DigestSettings struct {
+	UserID     string     `gorm:"primaryKey" json:"user_id"`
+	Enabled    bool       `gorm:"not null;default:false;index" json:"enabled"`
+	Email      string     `gorm:"not null;default:''" json:"email"`
+	Frequency  string     `gorm:"not null" json:"frequency"`
+	Timezone   string     `gorm:"not null" json:"timezone"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (DigestSettings) TableName() string {
+	return "digest_settings"
+}
+
+// TagSynonym makes a user's tag alias stand for another tag. Aliases are
+// stored lowercased.
+type TagSynonym struct {
+	UserID    string    `gorm:"primaryKey" json:"user_id"`
+	Alias     string    `gorm:"primaryKey" json:"alias"`
+	Tag       string    `gorm:"not null" json:"tag"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (TagSynonym) TableName() string {
+	return "tag_synonyms"
+}
+
+// SavedSearch is a named book search query of a user's, over their personal
+// books or a workspace's
+type SavedSearch struct {
+	ID          string    `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID      string    `gorm:"not null;index" json:"user_id"`
+	WorkspaceID *string   `gorm:"type:uuid" json:"workspace_id,omitempty"`
+	Name        string    `gorm:"not null" json:"name"`
+	Query       string    `gorm:"type:text;not null" json:"query"`
+	Pinned      bool      `gorm:"not null;default:false" json:"pinned"`
+	Subscribed  bool      `gorm:"not null;default:false" json:"subscribed"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+type Workspace struct {
+	ID        string    `gorm:"primaryKey;type:uuid" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	OwnerID   string    `gorm:"not null;index" json:"owner_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+type WorkspaceMember struct {
+	WorkspaceID string    `gorm:"primaryKey;type:uuid" json:"workspace_id"`
+	UserID      string    `gorm:"primaryKey;index" json:"user_id"`
+	Role        string    `gorm:"not null" json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
+
+type WorkspaceInvitation struct {
+	ID          string     `gorm:"primaryKey;type:uuid" json:"id"`
+	WorkspaceID string     `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	Token       string     `gorm:"not null;uniqueIndex" json:"token"`
+	Role        string     `gorm:"not null" json:"role"`
+	InvitedBy   string     `gorm:"not null" json:"invited_by"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedBy  *string    `json:"accepted_by,omitempty"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (WorkspaceInvitation) TableName() string {
+	return "workspace_invitations"
+}
+
+type AccessToken struct {
+	ID          string     `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID      string     `gorm:"not null;index" json:"user_id"`
+	Name        string     `gorm:"not null" json:"name"`
+	TokenPrefix string     `gorm:"not null" json:"token_prefix"`
+	TokenHash   string     `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes      string     `gorm:"not null" json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (AccessToken) TableName() string {
+	return "access_tokens"
+}
+
+type Webhook struct {
+	ID        string    `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID    string    `gorm:"not null;index" json:"user_id"`
+	URL       string    `gorm:"not null" json:"url"`
+	Secret    string    `gorm:"not null" json:"-"`
+	Events    string    `gorm:"not null" json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+type WebhookDelivery struct {
+	ID         string    `gorm:"primaryKey;type:uuid" json:"id"`
+	WebhookID  string    `gorm:"type:uuid;not null;index" json:"webhook_id"`
+	EventID    string    `gorm:"type:uuid;not null" json:"event_id"`
+	EventType  string    `gorm:"not null" json:"event_type"`
+	Payload    string    `gorm:"type:text;not null" json:"payload"`
+	Attempt    int       `gorm:"not null" json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	Succeeded  bool      `gorm:"not null" json:"succeeded"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/transaction"
+)
+
+var _ transaction.UnitOfWork = (*DB)(nil)
+
+type txKey struct{}
+
+// RunInTransaction calls fn inside a database transaction carried by ctx.
+// Repositories pick it up through Conn, so every statement they run with the
+// context passed to fn commits or rolls back together.
+func (db *DB) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction open in ctx, or the connection pool when
+// there is none
+func (db *DB) Conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.DB.WithContext(ctx)
+}
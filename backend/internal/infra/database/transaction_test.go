@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type note struct {
+	ID   uint
+	Body string
+}
+
+func setupTransactionTestDB(t *testing.T) *DB {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&note{}))
+	return &DB{DB: gormDB}
+}
+
+func countNotes(t *testing.T, db *DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&note{}).Count(&count).Error)
+	return count
+}
+
+func TestRunInTransaction_Commits(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	ctx := context.Background()
+
+	err := db.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := db.Conn(ctx).Create(&note{Body: "one"}).Error; err != nil {
+			return err
+		}
+		return db.Conn(ctx).Create(&note{Body: "two"}).Error
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), countNotes(t, db))
+}
+
+func TestRunInTransaction_RollsBackOnError(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	ctx := context.Background()
+	failure := errors.New("second step failed")
+
+	err := db.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := db.Conn(ctx).Create(&note{Body: "one"}).Error; err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, int64(0), countNotes(t, db))
+}
+
+func TestRunInTransaction_NestedCallJoinsOuter(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	ctx := context.Background()
+	failure := errors.New("outer step failed")
+
+	err := db.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := db.RunInTransaction(ctx, func(ctx context.Context) error {
+			return db.Conn(ctx).Create(&note{Body: "inner"}).Error
+		})
+		if err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	// The inner call did not commit on its own
+	assert.Equal(t, int64(0), countNotes(t, db))
+}
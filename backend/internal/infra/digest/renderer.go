@@ -0,0 +1,88 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var funcs = map[string]interface{}{
+	"date": func(t time.Time) string {
+		return t.Format("Jan 2, 2006")
+	},
+	"join":    strings.Join,
+	"minutes": reading.EstimateMinutes,
+}
+
+// Renderer renders digests from the HTML and plain-text templates
+type Renderer struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+	// libraryURL is linked from the digest when set
+	libraryURL string
+}
+
+// NewRenderer parses the templates. appURL is the address of the web app,
+// such as https://tsundoc.app; the digest links to the library there when it
+// is set.
+func NewRenderer(appURL string) (*Renderer, error) {
+	html, err := htmltemplate.New("digest.html").Funcs(funcs).ParseFS(templates, "templates/digest.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML digest template: %w", err)
+	}
+	text, err := texttemplate.New("digest.txt").Funcs(funcs).ParseFS(templates, "templates/digest.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text digest template: %w", err)
+	}
+
+	r := &Renderer{html: html, text: text}
+	if appURL != "" {
+		r.libraryURL = strings.TrimSuffix(appURL, "/") + "/library"
+	}
+	return r, nil
+}
+
+type templateData struct {
+	Digest     *digest.Digest
+	Subject    string
+	LibraryURL string
+}
+
+func (r *Renderer) Render(d *digest.Digest, to string) (*digest.Email, error) {
+	data := templateData{Digest: d, Subject: subject(d), LibraryURL: r.libraryURL}
+
+	var html, text bytes.Buffer
+	if err := r.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render HTML digest: %w", err)
+	}
+	if err := r.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render text digest: %w", err)
+	}
+
+	return &digest.Email{
+		To:      to,
+		Subject: data.Subject,
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+func subject(d *digest.Digest) string {
+	switch n := len(d.NewBooks); {
+	case n == 1:
+		return "Your tsundoc digest: 1 new book"
+	case n > 1:
+		return fmt.Sprintf("Your tsundoc digest: %d new books", n)
+	}
+	return "Your tsundoc digest: books waiting for you"
+}
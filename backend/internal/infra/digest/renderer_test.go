@@ -0,0 +1,65 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	r, err := NewRenderer("https://tsundoc.example/")
+	require.NoError(t, err)
+
+	d := &digest.Digest{
+		UserID: "user-123",
+		Since:  time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+		Until:  time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC),
+		NewBooks: []*book.Book{
+			{ID: "book-1", Title: "Go <Generics>", Content: "one two three", Tags: []string{"go", "programming"}},
+		},
+		Shelves: []*digest.Shelf{{
+			Search: &search.SavedSearch{Name: "Team <Go>"},
+			Books:  []*book.Book{{ID: "book-4", Title: "Shared notes", Content: "notes"}},
+		}},
+		Reminders: []*book.Book{{ID: "book-2", Title: "Half read", Content: "half"}},
+		Resurfaced: []*book.Book{
+			{ID: "book-3", Title: "Old one", Content: "old words", CreatedAt: time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	email, err := r.Render(d, "reader@example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, "reader@example.com", email.To)
+	assert.Equal(t, "Your tsundoc digest: 1 new book", email.Subject)
+
+	// The HTML is escaped, the text is not
+	assert.Contains(t, email.HTML, "Go &lt;Generics&gt;")
+	assert.Contains(t, email.HTML, `href="https://tsundoc.example/library"`)
+	assert.Contains(t, email.Text, "- Go <Generics> [go, programming] (1 min)")
+	assert.Contains(t, email.HTML, "New on Team &lt;Go&gt;")
+	assert.Contains(t, email.Text, "New on Team <Go>\n- Shared notes (1 min)")
+	assert.Contains(t, email.Text, "Pick up where you left off\n- Half read")
+	assert.Contains(t, email.Text, "- Old one (1 min), saved Dec 24, 2023")
+	assert.Contains(t, email.Text, "Mar 4, 2024 – Mar 5, 2024")
+	assert.Contains(t, email.Text, "Open your library: https://tsundoc.example/library")
+}
+
+func TestRenderer_RenderEmpty(t *testing.T) {
+	r, err := NewRenderer("")
+	require.NoError(t, err)
+
+	email, err := r.Render(&digest.Digest{}, "reader@example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Your tsundoc digest: books waiting for you", email.Subject)
+	assert.Contains(t, email.Text, "Nothing new this time.")
+	assert.NotContains(t, email.Text, "Open your library")
+	assert.NotContains(t, email.HTML, "/library")
+}
@@ -0,0 +1,79 @@
+package digest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DigestSender sends the digests that are due at a given time
+type DigestSender interface {
+	SendDueDigests(ctx context.Context, now time.Time) (int, error)
+}
+
+// Scheduler periodically sends the digests that are due
+type Scheduler struct {
+	sender   DigestSender
+	interval time.Duration
+
+	stopOnce sync.Once
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+// NewScheduler creates a scheduler that checks for due digests every
+// interval. Call Start to begin.
+func NewScheduler(sender DigestSender, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		sender:   sender,
+		interval: interval,
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start checks for due digests right away and then every interval, until
+// ctx is cancelled or Shutdown is called
+func (s *Scheduler) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Shutdown stops the scheduler and waits for a run in progress to finish. If
+// ctx expires first its error is returned; cancel the context passed to
+// Start to abort the run.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopping) })
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := s.sender.SendDueDigests(ctx, time.Now())
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to send digests")
+		} else if sent > 0 {
+			log.Info().Int("sent", sent).Msg("Sent digests")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopping:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package digest
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+)
+
+// smtpMessage is a message received by fakeSMTPServer
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer accepts a single SMTP session on a local port and sends the
+// message it received on the returned channel
+func fakeSMTPServer(t *testing.T) (string, int, <-chan smtpMessage) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP")
+
+		var msg smtpMessage
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			switch verb := strings.ToUpper(strings.Fields(cmd + " ")[0]); verb {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "MAIL":
+				msg.from = strings.TrimPrefix(cmd, "MAIL FROM:")
+				reply("250 OK")
+			case "RCPT":
+				msg.to = append(msg.to, strings.TrimPrefix(cmd, "RCPT TO:"))
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(line, "."))
+				}
+				msg.data = data.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				messages <- msg
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return host, p, messages
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	host, port, messages := fakeSMTPServer(t)
+
+	sender, err := NewSMTPSender(host, port, "", "", "tsundoc <digest@tsundoc.local>")
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), &digest.Email{
+		To:      "reader@example.com",
+		Subject: "Your tsundoc digest: 積読",
+		HTML:    "<p>積読 is fun</p>",
+		Text:    "積読 is fun",
+	})
+	require.NoError(t, err)
+
+	received := <-messages
+	assert.Equal(t, "<digest@tsundoc.local>", received.from)
+	assert.Equal(t, []string{"<reader@example.com>"}, received.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(received.data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Your tsundoc digest: 積読", subject)
+	assert.Equal(t, "<reader@example.com>", msg.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	// multipart.Reader decodes quoted-printable parts
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	assert.Equal(t, []string{
+		"text/plain; charset=utf-8: 積読 is fun",
+		"text/html; charset=utf-8: <p>積読 is fun</p>",
+	}, bodies)
+}
+
+func TestSMTPSender_InvalidAddresses(t *testing.T) {
+	_, err := NewSMTPSender("localhost", 25, "", "", "not an address")
+	assert.Error(t, err)
+
+	sender, err := NewSMTPSender("localhost", 25, "", "", "digest@tsundoc.local")
+	require.NoError(t, err)
+	err = sender.Send(context.Background(), &digest.Email{To: "nobody"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package markdown
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/motoya-k/tsundoc/internal/domain/markdown"
+)
+
+// DefaultCacheSize is the number of rendered documents kept by default
+const DefaultCacheSize = 512
+
+// CachedRenderer keeps recently rendered documents keyed on a hash of their
+// source, so that a book is rendered once rather than once per field and
+// request
+type CachedRenderer struct {
+	next  markdown.Renderer
+	cache *lru.Cache[[sha256.Size]byte, *markdown.Document]
+}
+
+// NewCachedRenderer wraps next with a cache of up to size documents
+func NewCachedRenderer(next markdown.Renderer, size int) (*CachedRenderer, error) {
+	cache, err := lru.New[[sha256.Size]byte, *markdown.Document](size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create markdown cache: %w", err)
+	}
+
+	return &CachedRenderer{
+		next:  next,
+		cache: cache,
+	}, nil
+}
+
+// Render returns the cached document for source, rendering it on a miss
+func (r *CachedRenderer) Render(ctx context.Context, source string) (*markdown.Document, error) {
+	key := sha256.Sum256([]byte(source))
+	if doc, ok := r.cache.Get(key); ok {
+		return doc, nil
+	}
+
+	doc, err := r.next.Render(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Add(key, doc)
+	return doc, nil
+}
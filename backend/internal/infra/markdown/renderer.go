@@ -0,0 +1,219 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/motoya-k/tsundoc/internal/domain/markdown"
+)
+
+// highlightStyle is the chroma style used for code blocks. Colors are inlined
+// so that clients need no stylesheet.
+const highlightStyle = "github"
+
+// Renderer renders GitHub Flavored Markdown with goldmark
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+func NewRenderer() *Renderer {
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(
+				extension.GFM,
+				highlighting.NewHighlighting(
+					highlighting.WithStyle(highlightStyle),
+					highlighting.WithFormatOptions(chromahtml.WithClasses(false)),
+				),
+			),
+		),
+		policy: newPolicy(),
+	}
+}
+
+// newPolicy allows what the Markdown renderer produces for user content, plus
+// heading anchors, task list checkboxes and highlighting colors
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration").OnElements("pre", "span")
+	return p
+}
+
+// Render parses source and renders it. Raw HTML in the source is dropped.
+func (r *Renderer) Render(ctx context.Context, source string) (*markdown.Document, error) {
+	src := []byte(source)
+	root := r.md.Parser().Parse(text.NewReader(src))
+
+	outline := r.addAnchors(root, src)
+
+	var html bytes.Buffer
+	if err := r.md.Renderer().Render(&html, src, root); err != nil {
+		return nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	return &markdown.Document{
+		Outline:   outline,
+		HTML:      r.policy.Sanitize(html.String()),
+		PlainText: plainText(root, src),
+	}, nil
+}
+
+// addAnchors gives every heading an id derived from its text and returns the
+// outline
+func (r *Renderer) addAnchors(root ast.Node, src []byte) []markdown.Heading {
+	outline := []markdown.Heading{}
+	used := map[string]bool{}
+
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		title := inlineText(h, src)
+		anchor := uniqueAnchor(slugify(title), used)
+		h.SetAttributeString("id", []byte(anchor))
+		outline = append(outline, markdown.Heading{Level: h.Level, Text: title, Anchor: anchor})
+		return ast.WalkSkipChildren, nil
+	})
+
+	return outline
+}
+
+// slugify keeps letters and digits of any script, lowercased, and joins
+// words with hyphens
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+func uniqueAnchor(base string, used map[string]bool) string {
+	if base == "" {
+		base = "section"
+	}
+	anchor := base
+	for i := 1; used[anchor]; i++ {
+		anchor = fmt.Sprintf("%s-%d", base, i)
+	}
+	used[anchor] = true
+	return anchor
+}
+
+// inlineText returns the text of n's inline children on one line
+func inlineText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			b.Write(n.Segment.Value(src))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		case *ast.AutoLink:
+			b.Write(n.Label(src))
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// plainText returns the text of the document with a blank line between
+// blocks, one line per list item and tab-separated table cells
+func plainText(root ast.Node, src []byte) string {
+	var b strings.Builder
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			switch n.(type) {
+			case *ast.Paragraph, *ast.Heading, *ast.List, *ast.CodeBlock, *ast.FencedCodeBlock, *east.Table:
+				b.WriteString("\n\n")
+			case *ast.TextBlock, *east.TableRow, *east.TableHeader:
+				b.WriteByte('\n')
+			case *east.TableCell:
+				b.WriteByte('\t')
+			}
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Text:
+			b.Write(n.Segment.Value(src))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteByte('\n')
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		case *ast.AutoLink:
+			b.Write(n.Label(src))
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				line := lines.At(i)
+				b.Write(line.Value(src))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return tidyLines(b.String())
+}
+
+// tidyLines trims trailing whitespace from each line and collapses runs of
+// blank lines into one
+func tidyLines(s string) string {
+	lines := strings.Split(s, "\n")
+	result := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			if !blank && len(result) > 0 {
+				result = append(result, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		result = append(result, line)
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
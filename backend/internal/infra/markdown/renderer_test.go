@@ -0,0 +1,91 @@
+package markdown
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/markdown"
+)
+
+func TestRenderer_Outline(t *testing.T) {
+	source := "# Getting Started\n\nIntro\n\n## Install `tsundoc`\n\n## Getting Started\n\n### 日本語の見出し\n\n#### !!!\n"
+
+	doc, err := NewRenderer().Render(context.Background(), source)
+	require.NoError(t, err)
+
+	assert.Equal(t, []markdown.Heading{
+		{Level: 1, Text: "Getting Started", Anchor: "getting-started"},
+		{Level: 2, Text: "Install tsundoc", Anchor: "install-tsundoc"},
+		{Level: 2, Text: "Getting Started", Anchor: "getting-started-1"},
+		{Level: 3, Text: "日本語の見出し", Anchor: "日本語の見出し"},
+		{Level: 4, Text: "!!!", Anchor: "section"},
+	}, doc.Outline)
+	assert.Contains(t, doc.HTML, `<h2 id="getting-started-1">Getting Started</h2>`)
+	assert.Contains(t, doc.HTML, `<h3 id="日本語の見出し">`)
+}
+
+func TestRenderer_SanitizesHTML(t *testing.T) {
+	source := "Hello <script>alert(1)</script>\n\n<img src=x onerror=alert(1)>\n\n[click](javascript:alert(1))\n\n- [x] done\n"
+
+	doc, err := NewRenderer().Render(context.Background(), source)
+	require.NoError(t, err)
+
+	assert.NotContains(t, doc.HTML, "<script")
+	assert.NotContains(t, doc.HTML, "onerror")
+	assert.NotContains(t, doc.HTML, "javascript:")
+	assert.Contains(t, doc.HTML, `<input checked="" disabled="" type="checkbox"`)
+}
+
+func TestRenderer_HighlightsCode(t *testing.T) {
+	source := "```go\nfunc main() {}\n```\n"
+
+	doc, err := NewRenderer().Render(context.Background(), source)
+	require.NoError(t, err)
+
+	assert.Contains(t, doc.HTML, `<pre style="`)
+	assert.Contains(t, doc.HTML, `<span style="`)
+	assert.Contains(t, doc.HTML, "main")
+}
+
+func TestRenderer_PlainText(t *testing.T) {
+	source := "# Title\n\nSome **bold** and [a link](https://example.com).\n\n- one\n- two\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n```\ncode\n```\n\n<div>raw</div>\n"
+
+	doc, err := NewRenderer().Render(context.Background(), source)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Title\n\nSome bold and a link.\n\none\ntwo\n\na\tb\n1\t2\n\ncode", doc.PlainText)
+}
+
+// countingRenderer counts the documents it renders
+type countingRenderer struct {
+	calls int
+}
+
+func (r *countingRenderer) Render(ctx context.Context, source string) (*markdown.Document, error) {
+	r.calls++
+	return &markdown.Document{PlainText: source}, nil
+}
+
+func TestCachedRenderer(t *testing.T) {
+	next := &countingRenderer{}
+	r, err := NewCachedRenderer(next, 1)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	first, err := r.Render(ctx, "one")
+	require.NoError(t, err)
+	again, err := r.Render(ctx, "one")
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+	assert.Equal(t, 1, next.calls)
+
+	// The least recently used document is evicted
+	_, err = r.Render(ctx, "two")
+	require.NoError(t, err)
+	_, err = r.Render(ctx, "one")
+	require.NoError(t, err)
+	assert.Equal(t, 3, next.calls)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type AccessTokenRepository struct {
+	db *database.DB
+}
+
+func NewAccessTokenRepository(db *database.DB) accesstoken.Repository {
+	return &AccessTokenRepository{
+		db: db,
+	}
+}
+
+func (r *AccessTokenRepository) Save(ctx context.Context, t *accesstoken.Token) error {
+	scopes := make([]string, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = string(s)
+	}
+
+	dbToken := &database.AccessToken{
+		ID:          t.ID,
+		UserID:      t.UserID,
+		Name:        t.Name,
+		TokenPrefix: t.TokenPrefix,
+		TokenHash:   t.Hash,
+		Scopes:      strings.Join(scopes, ","),
+		ExpiresAt:   t.ExpiresAt,
+		CreatedAt:   t.CreatedAt,
+	}
+
+	if err := r.db.Conn(ctx).Create(dbToken).Error; err != nil {
+		return fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AccessTokenRepository) FindByHash(ctx context.Context, hash string) (*accesstoken.Token, error) {
+	var dbToken database.AccessToken
+	err := r.db.Conn(ctx).Where("token_hash = ?", hash).First(&dbToken).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, accesstoken.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	return r.mapToTokenDomain(&dbToken), nil
+}
+
+func (r *AccessTokenRepository) FindByUserID(ctx context.Context, userID string) ([]*accesstoken.Token, error) {
+	var dbTokens []database.AccessToken
+	err := r.db.Conn(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&dbTokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access tokens: %w", err)
+	}
+
+	tokens := make([]*accesstoken.Token, len(dbTokens))
+	for i, dbToken := range dbTokens {
+		tokens[i] = r.mapToTokenDomain(&dbToken)
+	}
+
+	return tokens, nil
+}
+
+func (r *AccessTokenRepository) Revoke(ctx context.Context, id, userID string) error {
+	result := r.db.Conn(ctx).
+		Model(&database.AccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke access token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errs.NotFound("access token not found or not authorized")
+	}
+
+	return nil
+}
+
+func (r *AccessTokenRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	err := r.db.Conn(ctx).
+		Model(&database.AccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to update access token usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AccessTokenRepository) mapToTokenDomain(dbToken *database.AccessToken) *accesstoken.Token {
+	var scopes []accesstoken.Scope
+	for _, s := range strings.Split(dbToken.Scopes, ",") {
+		if s != "" {
+			scopes = append(scopes, accesstoken.Scope(s))
+		}
+	}
+
+	return &accesstoken.Token{
+		ID:          dbToken.ID,
+		UserID:      dbToken.UserID,
+		Name:        dbToken.Name,
+		TokenPrefix: dbToken.TokenPrefix,
+		Hash:        dbToken.TokenHash,
+		Scopes:      scopes,
+		ExpiresAt:   dbToken.ExpiresAt,
+		LastUsedAt:  dbToken.LastUsedAt,
+		RevokedAt:   dbToken.RevokedAt,
+		CreatedAt:   dbToken.CreatedAt,
+	}
+}
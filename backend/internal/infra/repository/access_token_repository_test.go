@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+)
+
+func TestAccessTokenRepository_SaveAndFindByHash(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAccessTokenRepository(db)
+	ctx := context.Background()
+
+	scopes := []accesstoken.Scope{accesstoken.ScopeRead, accesstoken.ScopeWrite}
+	token, secret, err := accesstoken.NewToken("user-123", "cron job", scopes, nil)
+	require.NoError(t, err)
+
+	err = repo.Save(ctx, token)
+	require.NoError(t, err)
+
+	found, err := repo.FindByHash(ctx, accesstoken.Hash(secret))
+	require.NoError(t, err)
+
+	assert.Equal(t, token.ID, found.ID)
+	assert.Equal(t, "user-123", found.UserID)
+	assert.Equal(t, "cron job", found.Name)
+	assert.Equal(t, scopes, found.Scopes)
+	assert.Nil(t, found.ExpiresAt)
+
+	_, err = repo.FindByHash(ctx, accesstoken.Hash(accesstoken.Prefix+"unknown"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "access token not found")
+}
+
+func TestAccessTokenRepository_Revoke(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAccessTokenRepository(db)
+	ctx := context.Background()
+
+	token, _, err := accesstoken.NewToken("user-123", "cron job", []accesstoken.Scope{accesstoken.ScopeRead}, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, token))
+
+	err = repo.Revoke(ctx, token.ID, "other-user")
+	assert.Error(t, err)
+
+	err = repo.Revoke(ctx, token.ID, "user-123")
+	require.NoError(t, err)
+
+	tokens, err := repo.FindByUserID(ctx, "user-123")
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	found, err := repo.FindByHash(ctx, token.Hash)
+	require.NoError(t, err)
+	assert.False(t, found.IsActive(time.Now()))
+}
+
+func TestAccessTokenRepository_TouchLastUsed(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAccessTokenRepository(db)
+	ctx := context.Background()
+
+	token, _, err := accesstoken.NewToken("user-123", "cron job", []accesstoken.Scope{accesstoken.ScopeRead}, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, token))
+
+	usedAt := time.Now()
+	err = repo.TouchLastUsed(ctx, token.ID, usedAt)
+	require.NoError(t, err)
+
+	found, err := repo.FindByHash(ctx, token.Hash)
+	require.NoError(t, err)
+	require.NotNil(t, found.LastUsedAt)
+	assert.WithinDuration(t, usedAt, *found.LastUsedAt, time.Second)
+}
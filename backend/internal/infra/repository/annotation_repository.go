@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type AnnotationRepository struct {
+	db *database.DB
+}
+
+func NewAnnotationRepository(db *database.DB) annotation.Repository {
+	return &AnnotationRepository{
+		db: db,
+	}
+}
+
+func (r *AnnotationRepository) Save(ctx context.Context, a *annotation.Annotation) error {
+	dbAnnotation := &database.Annotation{
+		ID:          a.ID,
+		BookID:      a.BookID,
+		UserID:      a.UserID,
+		StartOffset: a.Start,
+		EndOffset:   a.End,
+		Quote:       a.Quote,
+		Color:       string(a.Color),
+		Note:        a.Note,
+		Detached:    a.Detached,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+
+	if err := r.db.Conn(ctx).Create(dbAnnotation).Error; err != nil {
+		return fmt.Errorf("failed to create annotation: %w", err)
+	}
+
+	a.CreatedAt = dbAnnotation.CreatedAt
+	a.UpdatedAt = dbAnnotation.UpdatedAt
+	return nil
+}
+
+func (r *AnnotationRepository) FindByID(ctx context.Context, id, userID string) (*annotation.Annotation, error) {
+	var dbAnnotation database.Annotation
+	err := r.db.Conn(ctx).Where("id = ? AND user_id = ?", id, userID).First(&dbAnnotation).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, annotation.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get annotation: %w", err)
+	}
+
+	return r.mapToAnnotationDomain(&dbAnnotation), nil
+}
+
+func (r *AnnotationRepository) FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string][]*annotation.Annotation, error) {
+	result := make(map[string][]*annotation.Annotation, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return result, nil
+	}
+
+	var dbAnnotations []database.Annotation
+	err := r.db.Conn(ctx).
+		Where("book_id IN ? AND user_id = ?", bookIDs, userID).
+		Order("start_offset, created_at").
+		Find(&dbAnnotations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotations: %w", err)
+	}
+
+	for i := range dbAnnotations {
+		a := r.mapToAnnotationDomain(&dbAnnotations[i])
+		result[a.BookID] = append(result[a.BookID], a)
+	}
+
+	return result, nil
+}
+
+func (r *AnnotationRepository) FindAllByBookID(ctx context.Context, bookID string) ([]*annotation.Annotation, error) {
+	var dbAnnotations []database.Annotation
+	err := r.db.Conn(ctx).Where("book_id = ?", bookID).Find(&dbAnnotations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotations: %w", err)
+	}
+
+	annotations := make([]*annotation.Annotation, len(dbAnnotations))
+	for i := range dbAnnotations {
+		annotations[i] = r.mapToAnnotationDomain(&dbAnnotations[i])
+	}
+
+	return annotations, nil
+}
+
+func (r *AnnotationRepository) Update(ctx context.Context, a *annotation.Annotation) error {
+	now := time.Now()
+	result := r.db.Conn(ctx).
+		Model(&database.Annotation{}).
+		Where("id = ? AND user_id = ?", a.ID, a.UserID).
+		Updates(map[string]interface{}{
+			"color":      string(a.Color),
+			"note":       a.Note,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update annotation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errs.NotFound("annotation not found or not authorized")
+	}
+
+	a.UpdatedAt = now
+	return nil
+}
+
+func (r *AnnotationRepository) UpdateAnchors(ctx context.Context, annotations []*annotation.Annotation) error {
+	return r.db.RunInTransaction(ctx, func(ctx context.Context) error {
+		for _, a := range annotations {
+			err := r.db.Conn(ctx).
+				Model(&database.Annotation{}).
+				Where("id = ?", a.ID).
+				Updates(map[string]interface{}{
+					"start_offset": a.Start,
+					"end_offset":   a.End,
+					"detached":     a.Detached,
+				}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update annotation anchor: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *AnnotationRepository) Delete(ctx context.Context, id, userID string) error {
+	result := r.db.Conn(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&database.Annotation{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete annotation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errs.NotFound("annotation not found or not authorized")
+	}
+
+	return nil
+}
+
+func (r *AnnotationRepository) mapToAnnotationDomain(dbAnnotation *database.Annotation) *annotation.Annotation {
+	return &annotation.Annotation{
+		ID:        dbAnnotation.ID,
+		BookID:    dbAnnotation.BookID,
+		UserID:    dbAnnotation.UserID,
+		Start:     dbAnnotation.StartOffset,
+		End:       dbAnnotation.EndOffset,
+		Quote:     dbAnnotation.Quote,
+		Color:     annotation.Color(dbAnnotation.Color),
+		Note:      dbAnnotation.Note,
+		Detached:  dbAnnotation.Detached,
+		CreatedAt: dbAnnotation.CreatedAt,
+		UpdatedAt: dbAnnotation.UpdatedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+func saveAnnotation(t *testing.T, repo annotation.Repository, userID, bookID string, start, end int) *annotation.Annotation {
+	a, err := annotation.NewAnnotation(userID, bookID, "The quick brown fox jumps over the lazy dog", start, end, annotation.ColorBlue, "")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), a))
+	return a
+}
+
+func TestAnnotationRepository_FindByBookIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAnnotationRepository(db)
+	ctx := context.Background()
+	createTestBooks(t, db, "user-123", "book-1", "book-2")
+
+	fox := saveAnnotation(t, repo, "user-123", "book-1", 16, 19)
+	quick := saveAnnotation(t, repo, "user-123", "book-1", 4, 9)
+	saveAnnotation(t, repo, "user-123", "book-2", 0, 3)
+	saveAnnotation(t, repo, "user-456", "book-1", 0, 3)
+
+	byBook, err := repo.FindByBookIDs(ctx, []string{"book-1", "book-2", "book-3"}, "user-123")
+	require.NoError(t, err)
+
+	// Only the user's own annotations, ordered by position
+	require.Len(t, byBook["book-1"], 2)
+	assert.Equal(t, quick.ID, byBook["book-1"][0].ID)
+	assert.Equal(t, fox.ID, byBook["book-1"][1].ID)
+	assert.Equal(t, "fox", byBook["book-1"][1].Quote)
+	assert.Equal(t, annotation.ColorBlue, byBook["book-1"][1].Color)
+	assert.Len(t, byBook["book-2"], 1)
+	assert.Empty(t, byBook["book-3"])
+
+	all, err := repo.FindAllByBookID(ctx, "book-1")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestAnnotationRepository_UpdateAndDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAnnotationRepository(db)
+	ctx := context.Background()
+	createTestBooks(t, db, "user-123", "book-1")
+
+	a := saveAnnotation(t, repo, "user-123", "book-1", 4, 9)
+
+	a.Note = "fast"
+	a.Color = annotation.ColorPink
+	require.NoError(t, repo.Update(ctx, a))
+
+	found, err := repo.FindByID(ctx, a.ID, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, "fast", found.Note)
+	assert.Equal(t, annotation.ColorPink, found.Color)
+
+	// Other users can neither see nor change it
+	_, err = repo.FindByID(ctx, a.ID, "user-456")
+	assert.ErrorIs(t, err, annotation.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, a.ID, "user-456"), errs.ErrNotFound)
+
+	require.NoError(t, repo.Delete(ctx, a.ID, "user-123"))
+	_, err = repo.FindByID(ctx, a.ID, "user-123")
+	assert.ErrorIs(t, err, annotation.ErrNotFound)
+}
+
+func TestAnnotationRepository_UpdateAnchors(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAnnotationRepository(db)
+	ctx := context.Background()
+	createTestBooks(t, db, "user-123", "book-1")
+
+	a := saveAnnotation(t, repo, "user-123", "book-1", 4, 9)
+	require.True(t, a.Reanchor("A quick fox"))
+
+	require.NoError(t, repo.UpdateAnchors(ctx, []*annotation.Annotation{a}))
+
+	found, err := repo.FindByID(ctx, a.ID, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, 2, found.Start)
+	assert.Equal(t, 7, found.End)
+	assert.False(t, found.Detached)
+}
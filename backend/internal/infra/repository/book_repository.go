@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
-	
+
 	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
 	"github.com/motoya-k/tsundoc/internal/infra/database"
 )
 
@@ -22,18 +27,48 @@ func NewBookRepository(db *database.DB) book.Repository {
 }
 
 func (r *BookRepository) Save(ctx context.Context, b *book.Book) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
 	dbBook := &database.Book{
-		Title:   b.Title,
-		Content: b.Content,
-		Tags:    b.Tags,
-		UserID:  b.UserID,
+		ID:             b.ID,
+		Title:          b.Title,
+		Content:        b.Content,
+		URL:            b.URL,
+		Tags:           tagsOrEmpty(b.Tags),
+		UserID:         b.UserID,
+		WorkspaceID:    toWorkspaceID(b.WorkspaceID),
+		Version:        1,
+		Enrichment:     string(b.Enrichment),
+		MergedFrom:     b.MergedFrom,
+		PromptVersions: promptVersionsOrEmpty(b.PromptVersions),
 	}
 
-	if err := r.db.WithContext(ctx).Create(dbBook).Error; err != nil {
-		return fmt.Errorf("failed to create book: %w", err)
+	if b.WorkspaceID != "" {
+		var count int64
+		err := r.db.Conn(ctx).
+			Model(&database.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ? AND role IN ?", b.WorkspaceID, b.UserID, writableRoles).
+			Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to check workspace membership: %w", err)
+		}
+		if count == 0 {
+			return errs.Unauthorized("not authorized to add books to this workspace")
+		}
 	}
 
-	b.ID = dbBook.ID
+	err := r.db.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := r.db.Conn(ctx).Create(dbBook).Error; err != nil {
+			return fmt.Errorf("failed to create book: %w", err)
+		}
+		return syncLinks(ctx, r.db, dbBook)
+	})
+	if err != nil {
+		return err
+	}
+
+	b.Version = dbBook.Version
 	b.CreatedAt = dbBook.CreatedAt
 	b.UpdatedAt = dbBook.UpdatedAt
 	return nil
@@ -41,10 +76,10 @@ func (r *BookRepository) Save(ctx context.Context, b *book.Book) error {
 
 func (r *BookRepository) FindByID(ctx context.Context, id, userID string) (*book.Book, error) {
 	var dbBook database.Book
-	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&dbBook).Error
+	err := r.db.Conn(ctx).Scopes(readableBy(userID)).Where("id = ?", id).First(&dbBook).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("book not found")
+			return nil, book.ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get book: %w", err)
 	}
@@ -52,78 +87,294 @@ func (r *BookRepository) FindByID(ctx context.Context, id, userID string) (*book
 	return r.mapToBookDomain(&dbBook), nil
 }
 
+func (r *BookRepository) FindByIDs(ctx context.Context, ids []string, userID string) ([]*book.Book, error) {
+	books := make([]*book.Book, len(ids))
+	if len(ids) == 0 {
+		return books, nil
+	}
+
+	var dbBooks []database.Book
+	err := r.db.Conn(ctx).Scopes(readableBy(userID)).Where("id IN ?", ids).Find(&dbBooks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+
+	byID := make(map[string]*database.Book, len(dbBooks))
+	for i := range dbBooks {
+		byID[dbBooks[i].ID] = &dbBooks[i]
+	}
+	for i, id := range ids {
+		if dbBook, ok := byID[id]; ok {
+			books[i] = r.mapToBookDomain(dbBook)
+		}
+	}
+
+	return books, nil
+}
+
 func (r *BookRepository) FindByUserID(ctx context.Context, userID string, keyword string) ([]*book.Book, error) {
 	var dbBooks []database.Book
-	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
-
-	if keyword != "" {
-		// Search in title, content, and tags
-		keyword = strings.ToLower(keyword)
-		query = query.Where(
-			"LOWER(title) LIKE ? OR LOWER(content) LIKE ? OR EXISTS (SELECT 1 FROM unnest(tags) AS tag WHERE LOWER(tag) LIKE ?)",
-			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%",
-		)
+	query := r.db.Conn(ctx).
+		Where("user_id = ? AND workspace_id IS NULL", userID).
+		Scopes(r.db.MatchBooks(keyword, userID))
+
+	if err := query.Order("created_at DESC").Find(&dbBooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
 	}
 
+	return r.mapToBookDomains(dbBooks), nil
+}
+
+func (r *BookRepository) FindByWorkspaceID(ctx context.Context, workspaceID, userID string, keyword string) ([]*book.Book, error) {
+	var dbBooks []database.Book
+	query := r.db.Conn(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Scopes(readableBy(userID), r.db.MatchBooks(keyword, userID))
+
 	if err := query.Order("created_at DESC").Find(&dbBooks).Error; err != nil {
 		return nil, fmt.Errorf("failed to get books: %w", err)
 	}
 
-	books := make([]*book.Book, len(dbBooks))
-	for i, dbBook := range dbBooks {
-		books[i] = r.mapToBookDomain(&dbBook)
+	return r.mapToBookDomains(dbBooks), nil
+}
+
+func (r *BookRepository) FindTags(ctx context.Context, userID, workspaceID string, limit int) ([]string, error) {
+	var tags []string
+	err := r.db.Conn(ctx).Model(&database.Book{}).
+		Joins("CROSS JOIN "+r.db.JSONArrayElements("books.tags")).
+		Scopes(userLibrary(userID, workspaceID)).
+		Group("value").
+		Order("COUNT(*) DESC, value").
+		Limit(limit).
+		Pluck("value", &tags).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
 	}
 
-	return books, nil
+	return tags, nil
 }
 
-func (r *BookRepository) Update(ctx context.Context, b *book.Book) error {
-	dbBook := &database.Book{
-		ID:      b.ID,
-		Title:   b.Title,
-		Content: b.Content,
-		Tags:    b.Tags,
-		UserID:  b.UserID,
+func (r *BookRepository) Search(ctx context.Context, userID, workspaceID string, q *search.Query, limit int) ([]*book.Book, error) {
+	query := r.db.Conn(ctx)
+	if workspaceID != "" {
+		query = query.Where("books.workspace_id = ?", workspaceID).Scopes(readableBy(userID))
+	} else {
+		query = query.Where("books.user_id = ? AND books.workspace_id IS NULL", userID)
+	}
+	for _, t := range q.Terms {
+		condition, args, err := r.searchCondition(t, userID)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("("+condition+")", args...)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
 	}
 
-	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", b.ID, b.UserID).Updates(dbBook)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update book: %w", result.Error)
+	var dbBooks []database.Book
+	if err := query.Order("books.created_at DESC").Find(&dbBooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to search books: %w", err)
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("book not found or not authorized")
+
+	return r.mapToBookDomains(dbBooks), nil
+}
+
+// searchCondition translates a search term into SQL for either dialect
+func (r *BookRepository) searchCondition(t search.Term, userID string) (string, []interface{}, error) {
+	switch t := t.(type) {
+	case *search.Text:
+		condition, args := r.db.MatchBooksCondition(t.Text, userID)
+		return condition, args, nil
+	case *search.Tag:
+		tag := strings.ToLower(t.Tag)
+		return "EXISTS (SELECT 1 FROM " + r.db.JSONArrayElements("books.tags") + ` WHERE LOWER(value) = ? OR LOWER(value) LIKE ? ESCAPE '\')`,
+			[]interface{}{tag, escapeLike(tag) + "/%"}, nil
+	case *search.Status:
+		// Books without a reading state are unread
+		if t.Status == reading.StatusUnread {
+			return "NOT EXISTS (SELECT 1 FROM reading_states WHERE reading_states.book_id = books.id AND reading_states.user_id = ? AND reading_states.status <> ?)",
+				[]interface{}{userID, string(reading.StatusUnread)}, nil
+		}
+		return "EXISTS (SELECT 1 FROM reading_states WHERE reading_states.book_id = books.id AND reading_states.user_id = ? AND reading_states.status = ?)",
+			[]interface{}{userID, string(t.Status)}, nil
+	case *search.Date:
+		column := "books.created_at"
+		if t.Field == search.FieldUpdated {
+			column = "books.updated_at"
+		}
+		from, to := t.Range()
+		switch {
+		case from.IsZero():
+			return column + " < ?", []interface{}{to}, nil
+		case to.IsZero():
+			return column + " >= ?", []interface{}{from}, nil
+		default:
+			return column + " >= ? AND " + column + " < ?", []interface{}{from, to}, nil
+		}
+	case *search.Not:
+		condition, args, err := r.searchCondition(t.Term, userID)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + condition + ")", args, nil
+	default:
+		return "", nil, fmt.Errorf("unknown search term %T", t)
 	}
+}
 
-	// Fetch updated book to get the new UpdatedAt time
-	var updatedBook database.Book
-	if err := r.db.WithContext(ctx).Where("id = ?", b.ID).First(&updatedBook).Error; err != nil {
-		return fmt.Errorf("failed to fetch updated book: %w", err)
+func (r *BookRepository) Update(ctx context.Context, b *book.Book, userID string) error {
+	dbBook := &database.Book{
+		ID:             b.ID,
+		Title:          b.Title,
+		Content:        b.Content,
+		URL:            b.URL,
+		Tags:           tagsOrEmpty(b.Tags),
+		Version:        b.Version + 1,
+		PromptVersions: promptVersionsOrEmpty(b.PromptVersions),
 	}
 
-	b.UpdatedAt = updatedBook.UpdatedAt
-	return nil
+	// The re-read runs in the same transaction so that it sees this update
+	// rather than one committed concurrently
+	return r.db.RunInTransaction(ctx, func(ctx context.Context) error {
+		// Select the columns so that an emptied URL is written too
+		result := r.db.Conn(ctx).Scopes(writableBy(userID)).Where("id = ? AND version = ?", b.ID, b.Version).
+			Select("title", "content", "url", "tags", "version", "prompt_versions").
+			Updates(dbBook)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update book: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return r.updateFailure(ctx, b, userID)
+		}
+
+		// Fetch updated book to get the new UpdatedAt time
+		var updatedBook database.Book
+		if err := r.db.Conn(ctx).Where("id = ?", b.ID).First(&updatedBook).Error; err != nil {
+			return fmt.Errorf("failed to fetch updated book: %w", err)
+		}
+
+		b.Version = updatedBook.Version
+		b.UpdatedAt = updatedBook.UpdatedAt
+		return syncLinks(ctx, r.db, &updatedBook)
+	})
 }
 
-func (r *BookRepository) Delete(ctx context.Context, id, userID string) error {
-	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&database.Book{})
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete book: %w", result.Error)
+// updateFailure explains why an update matched no rows: the book is gone or
+// not writable by the user, or its version moved on
+func (r *BookRepository) updateFailure(ctx context.Context, b *book.Book, userID string) error {
+	var current database.Book
+	err := r.db.Conn(ctx).Scopes(readableBy(userID)).Where("id = ?", b.ID).First(&current).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errs.NotFound("book not found or not authorized")
+		}
+		return fmt.Errorf("failed to get book: %w", err)
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("book not found or not authorized")
+	if current.Version != b.Version {
+		return &book.ConflictError{Current: r.mapToBookDomain(&current)}
 	}
+	return errs.NotFound("book not found or not authorized")
+}
 
-	return nil
+func (r *BookRepository) Delete(ctx context.Context, id, userID string) error {
+	return r.db.RunInTransaction(ctx, func(ctx context.Context) error {
+		var dbBook database.Book
+		err := r.db.Conn(ctx).Scopes(writableBy(userID)).Where("id = ?", id).First(&dbBook).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errs.NotFound("book not found or not authorized")
+			}
+			return fmt.Errorf("failed to get book: %w", err)
+		}
+
+		if err := r.db.Conn(ctx).Delete(&dbBook).Error; err != nil {
+			return fmt.Errorf("failed to delete book: %w", err)
+		}
+
+		return unlinkBook(ctx, r.db, &dbBook)
+	})
 }
 
 func (r *BookRepository) mapToBookDomain(dbBook *database.Book) *book.Book {
-	return &book.Book{
-		ID:        dbBook.ID,
-		Title:     dbBook.Title,
-		Content:   dbBook.Content,
-		Tags:      dbBook.Tags,
-		UserID:    dbBook.UserID,
-		CreatedAt: dbBook.CreatedAt,
-		UpdatedAt: dbBook.UpdatedAt,
-	}
-}
\ No newline at end of file
+	b := &book.Book{
+		ID:             dbBook.ID,
+		Title:          dbBook.Title,
+		Content:        dbBook.Content,
+		URL:            dbBook.URL,
+		Tags:           dbBook.Tags,
+		UserID:         dbBook.UserID,
+		Version:        dbBook.Version,
+		Enrichment:     book.Enrichment(dbBook.Enrichment),
+		MergedFrom:     dbBook.MergedFrom,
+		PromptVersions: dbBook.PromptVersions,
+		CreatedAt:      dbBook.CreatedAt,
+		UpdatedAt:      dbBook.UpdatedAt,
+	}
+	if dbBook.WorkspaceID != nil {
+		b.WorkspaceID = *dbBook.WorkspaceID
+	}
+	return b
+}
+
+func (r *BookRepository) mapToBookDomains(dbBooks []database.Book) []*book.Book {
+	books := make([]*book.Book, len(dbBooks))
+	for i, dbBook := range dbBooks {
+		books[i] = r.mapToBookDomain(&dbBook)
+	}
+	return books
+}
+
+// writableRoles are the workspace roles allowed to change books
+var writableRoles = []string{string(workspace.RoleOwner), string(workspace.RoleEditor)}
+
+// readableBy limits a query to personal books of the user and books in
+// workspaces the user is a member of
+func readableBy(userID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"(workspace_id IS NULL AND user_id = ?) OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)",
+			userID, userID,
+		)
+	}
+}
+
+// writableBy limits a query to books the user is allowed to change
+func writableBy(userID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"(workspace_id IS NULL AND user_id = ?) OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ? AND role IN ?)",
+			userID, userID, writableRoles,
+		)
+	}
+}
+
+// promptVersionsOrEmpty stores missing prompt versions as an empty JSON
+// object rather than null
+func promptVersionsOrEmpty(versions map[string]string) map[string]string {
+	if versions == nil {
+		return map[string]string{}
+	}
+	return versions
+}
+
+// tagsOrEmpty stores missing tags as an empty JSON array rather than null
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// escapeLike makes the wildcards in s match literally in a LIKE pattern with
+// ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func toWorkspaceID(workspaceID string) *string {
+	if workspaceID == "" {
+		return nil
+	}
+	return &workspaceID
+}
@@ -2,25 +2,51 @@ package repository
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
 	"github.com/motoya-k/tsundoc/internal/infra/database"
+	"github.com/motoya-k/tsundoc/migrations"
 )
 
+// setupTestDB opens a SQLite database with the embedded migrations applied,
+// so that the repositories are tested against the schema they run on
 func setupTestDB(t *testing.T) *database.DB {
-	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := database.NewConnection("sqlite://" + filepath.Join(t.TempDir(), "tsundoc.db"))
+	if err != nil && strings.Contains(err.Error(), "FTS5") {
+		t.Skip("SQLite was built without FTS5; run with -tags sqlite_fts5")
+	}
 	require.NoError(t, err)
-	
-	// Auto migrate
-	err = gormDB.AutoMigrate(&database.Book{})
+	t.Cleanup(func() { db.Close() })
+
+	fsys, err := migrations.For(string(db.Dialect()))
 	require.NoError(t, err)
-	
-	return &database.DB{DB: gormDB}
+	m, err := database.NewMigrator(db, fsys)
+	require.NoError(t, err)
+	_, err = m.Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+// createTestBooks inserts bare books for rows that must reference one
+func createTestBooks(t *testing.T, db *database.DB, userID string, ids ...string) {
+	for _, id := range ids {
+		require.NoError(t, db.Create(&database.Book{ID: id, Title: id, Content: id, Tags: []string{}, UserID: userID}).Error)
+	}
+}
+
+// createTestWorkspace inserts a bare workspace for rows that must reference one
+func createTestWorkspace(t *testing.T, db *database.DB, id string) {
+	require.NoError(t, db.Create(&database.Workspace{ID: id, Name: "Workspace", OwnerID: "user-456"}).Error)
 }
 
 func TestBookRepository_Save(t *testing.T) {
@@ -56,6 +82,8 @@ func TestBookRepository_FindByID(t *testing.T) {
 	originalBook := book.NewBook("user-123", "Test content")
 	originalBook.Title = "Test Book"
 	originalBook.Tags = []string{"test", "book"}
+	originalBook.URL = "https://example.com/article"
+	originalBook.PromptVersions = map[string]string{"generate_title": "0123456789ab"}
 	
 	err := repo.Save(ctx, originalBook)
 	require.NoError(t, err)
@@ -70,6 +98,8 @@ func TestBookRepository_FindByID(t *testing.T) {
 	assert.Equal(t, originalBook.Title, foundBook.Title)
 	assert.Equal(t, originalBook.Content, foundBook.Content)
 	assert.Equal(t, originalBook.Tags, foundBook.Tags)
+	assert.Equal(t, originalBook.URL, foundBook.URL)
+	assert.Equal(t, originalBook.PromptVersions, foundBook.PromptVersions)
 }
 
 func TestBookRepository_FindByID_NotFound(t *testing.T) {
@@ -98,6 +128,34 @@ func TestBookRepository_FindByID_WrongUser(t *testing.T) {
 	assert.Contains(t, err.Error(), "book not found")
 }
 
+func TestBookRepository_FindByIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	first := book.NewBook("user-123", "First")
+	second := book.NewBook("user-123", "Second")
+	other := book.NewBook("user-456", "Someone else's")
+	for _, b := range []*book.Book{first, second, other} {
+		require.NoError(t, repo.Save(ctx, b))
+	}
+
+	books, err := repo.FindByIDs(ctx, []string{second.ID, "non-existent-id", first.ID, other.ID}, "user-123")
+	require.NoError(t, err)
+
+	// Results follow the requested order; missing and unreadable books are nil
+	require.Len(t, books, 4)
+	assert.Equal(t, second.ID, books[0].ID)
+	assert.Nil(t, books[1])
+	assert.Equal(t, first.ID, books[2].ID)
+	assert.Equal(t, "First", books[2].Content)
+	assert.Nil(t, books[3])
+
+	books, err = repo.FindByIDs(ctx, nil, "user-123")
+	require.NoError(t, err)
+	assert.Empty(t, books)
+}
+
 func TestBookRepository_FindByUserID(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewBookRepository(db)
@@ -135,6 +193,30 @@ func TestBookRepository_FindByUserID(t *testing.T) {
 	}
 }
 
+func TestBookRepository_FindTags(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	for _, tags := range [][]string{{"go", "api"}, {"go", "web"}, {"go", "api"}, {}} {
+		b := book.NewBook("user-123", "Content")
+		b.Tags = tags
+		require.NoError(t, repo.Save(ctx, b))
+	}
+	other := book.NewBook("user-456", "Content")
+	other.Tags = []string{"other", "other-2", "other-3"}
+	require.NoError(t, repo.Save(ctx, other))
+
+	// Most used first, then by name
+	tags, err := repo.FindTags(ctx, "user-123", "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "api", "web"}, tags)
+
+	tags, err = repo.FindTags(ctx, "user-123", "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "api"}, tags)
+}
+
 func TestBookRepository_FindByUserID_WithKeyword(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewBookRepository(db)
@@ -216,6 +298,92 @@ func TestBookRepository_FindByUserID_WithKeyword(t *testing.T) {
 	}
 }
 
+func TestBookRepository_Search(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	workspaceID := "11111111-1111-1111-1111-111111111111"
+	createTestWorkspace(t, db, workspaceID)
+	books := []database.Book{
+		{ID: "go", Title: "Go concurrency", Content: "Channels and goroutines", Tags: []string{"lang/go"}, UserID: "user-123", CreatedAt: day(3), UpdatedAt: day(9)},
+		{ID: "go-draft", Title: "Go generics draft", Content: "Type parameters", Tags: []string{"Lang/Go/generics", "draft"}, UserID: "user-123", CreatedAt: day(2), UpdatedAt: day(2)},
+		{ID: "golang", Title: "Golang jobs", Content: "Hiring", Tags: []string{"golang"}, UserID: "user-123", CreatedAt: day(1), UpdatedAt: day(1)},
+		{ID: "underscore", Title: "Snake case", Content: "a", Tags: []string{"lang_go"}, UserID: "user-123", CreatedAt: day(1), UpdatedAt: day(1)},
+		{ID: "theirs", Title: "Go at work", Content: "a", Tags: []string{"lang/go"}, UserID: "user-456", CreatedAt: day(1), UpdatedAt: day(1)},
+		{ID: "shared", Title: "Shared Go notes", Content: "a", Tags: []string{"lang/go"}, UserID: "user-456", WorkspaceID: &workspaceID, CreatedAt: day(1), UpdatedAt: day(1)},
+	}
+	require.NoError(t, db.DB.Create(&books).Error)
+	require.NoError(t, db.DB.Create(&database.WorkspaceMember{WorkspaceID: workspaceID, UserID: "user-123", Role: "viewer"}).Error)
+	require.NoError(t, db.DB.Create([]database.ReadingState{
+		{BookID: "go", UserID: "user-123", Status: string(reading.StatusRead)},
+		{BookID: "go-draft", UserID: "user-123", Status: string(reading.StatusUnread)},
+		{BookID: "golang", UserID: "user-456", Status: string(reading.StatusReading)},
+	}).Error)
+	require.NoError(t, db.DB.Create(&database.Annotation{ID: "note", BookID: "golang", UserID: "user-123", Quote: "Hiring", Color: "yellow", Note: "ask about remote"}).Error)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty query", query: "", want: []string{"go", "go-draft", "golang", "underscore"}},
+		{name: "tag and descendants", query: "tag:lang/go", want: []string{"go", "go-draft"}},
+		{name: "tag ignores case", query: "tag:LANG/GO/Generics", want: []string{"go-draft"}},
+		{name: "negated tag", query: "tag:lang/go -tag:draft", want: []string{"go"}},
+		{name: "text in title", query: "concurrency", want: []string{"go"}},
+		{name: "text in annotation notes", query: `"ask about remote"`, want: []string{"golang"}},
+		{name: "negated text", query: "-goroutines -hiring", want: []string{"go-draft", "underscore"}},
+		{name: "status read", query: "status:read", want: []string{"go"}},
+		{name: "status unread includes books without a state", query: "status:unread", want: []string{"go-draft", "golang", "underscore"}},
+		{name: "created after", query: "created:>2025-01-01", want: []string{"go", "go-draft"}},
+		{name: "created on", query: "created:2025-01-02", want: []string{"go-draft"}},
+		{name: "created before", query: "created:<2025-01-02", want: []string{"golang", "underscore"}},
+		{name: "updated on or after", query: "updated:>=2025-01-09", want: []string{"go"}},
+		{name: "every term must match", query: "tag:lang status:unread created:<=2025-01-02", want: []string{"go-draft"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := search.Parse(tt.query)
+			require.NoError(t, err)
+
+			found, err := repo.Search(ctx, "user-123", "", q, 0)
+			require.NoError(t, err)
+
+			ids := make([]string, len(found))
+			for i, b := range found {
+				ids[i] = b.ID
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+
+	t.Run("newest first with a limit", func(t *testing.T) {
+		found, err := repo.Search(ctx, "user-123", "", &search.Query{}, 2)
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, "go", found[0].ID)
+		assert.Equal(t, "go-draft", found[1].ID)
+	})
+
+	t.Run("workspace", func(t *testing.T) {
+		q, err := search.Parse("tag:lang/go")
+		require.NoError(t, err)
+
+		found, err := repo.Search(ctx, "user-123", workspaceID, q, 0)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, "shared", found[0].ID)
+
+		// Only members search a workspace
+		found, err = repo.Search(ctx, "user-789", workspaceID, q, 0)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+}
+
 func TestBookRepository_Update(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewBookRepository(db)
@@ -234,7 +402,7 @@ func TestBookRepository_Update(t *testing.T) {
 	book.Content = "Updated content"
 	book.Tags = []string{"updated", "modified"}
 
-	err = repo.Update(ctx, book)
+	err = repo.Update(ctx, book, book.UserID)
 	require.NoError(t, err)
 
 	// Verify the update
@@ -244,6 +412,76 @@ func TestBookRepository_Update(t *testing.T) {
 	assert.Equal(t, "Updated Title", updatedBook.Title)
 	assert.Equal(t, "Updated content", updatedBook.Content)
 	assert.Equal(t, []string{"updated", "modified"}, updatedBook.Tags)
+	assert.Equal(t, 2, updatedBook.Version)
+	assert.Equal(t, 2, book.Version)
+}
+
+func TestBookRepository_Update_URL(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	b := book.NewBook("user-123", "Content")
+	b.Title = "Title"
+	b.URL = "https://example.com/a"
+	require.NoError(t, repo.Save(ctx, b))
+
+	b.URL = "https://example.com/b"
+	require.NoError(t, repo.Update(ctx, b, b.UserID))
+	found, err := repo.FindByID(ctx, b.ID, b.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/b", found.URL)
+
+	// An emptied URL is saved too
+	b.URL = ""
+	require.NoError(t, repo.Update(ctx, b, b.UserID))
+	found, err = repo.FindByID(ctx, b.ID, b.UserID)
+	require.NoError(t, err)
+	assert.Empty(t, found.URL)
+	assert.Equal(t, "Title", found.Title)
+}
+
+func TestBookRepository_Update_StaleVersion(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	b := book.NewBook("user-123", "Original content")
+	require.NoError(t, repo.Save(ctx, b))
+
+	// Two editors start from the same version
+	first, err := repo.FindByID(ctx, b.ID, "user-123")
+	require.NoError(t, err)
+	second, err := repo.FindByID(ctx, b.ID, "user-123")
+	require.NoError(t, err)
+
+	first.Content = "First edit"
+	require.NoError(t, repo.Update(ctx, first, "user-123"))
+
+	second.Content = "Second edit"
+	err = repo.Update(ctx, second, "user-123")
+
+	var conflict *book.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "First edit", conflict.Current.Content)
+	assert.Equal(t, 2, conflict.Current.Version)
+
+	stored, err := repo.FindByID(ctx, b.ID, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, "First edit", stored.Content)
+}
+
+func TestBookRepository_Update_WrongUser(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	b := book.NewBook("user-123", "Original content")
+	require.NoError(t, repo.Save(ctx, b))
+
+	b.Content = "Hijacked"
+	err := repo.Update(ctx, b, "user-456")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
 }
 
 func TestBookRepository_Delete(t *testing.T) {
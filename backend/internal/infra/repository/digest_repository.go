@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type DigestRepository struct {
+	db *database.DB
+}
+
+func NewDigestRepository(db *database.DB) digest.Repository {
+	return &DigestRepository{
+		db: db,
+	}
+}
+
+func (r *DigestRepository) FindByUserID(ctx context.Context, userID string) (*digest.Settings, error) {
+	var dbSettings database.DigestSettings
+	err := r.db.Conn(ctx).Where("user_id = ?", userID).First(&dbSettings).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, digest.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get digest settings: %w", err)
+	}
+
+	return mapToDigestSettingsDomain(&dbSettings), nil
+}
+
+func (r *DigestRepository) FindEnabled(ctx context.Context) ([]*digest.Settings, error) {
+	var dbSettings []database.DigestSettings
+	if err := r.db.Conn(ctx).Where("enabled = ?", true).Order("user_id").Find(&dbSettings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get digest settings: %w", err)
+	}
+
+	settings := make([]*digest.Settings, len(dbSettings))
+	for i := range dbSettings {
+		settings[i] = mapToDigestSettingsDomain(&dbSettings[i])
+	}
+	return settings, nil
+}
+
+func (r *DigestRepository) Save(ctx context.Context, s *digest.Settings) error {
+	dbSettings := &database.DigestSettings{
+		UserID:     s.UserID,
+		Enabled:    s.Enabled,
+		Email:      s.Email,
+		Frequency:  string(s.Frequency),
+		Timezone:   s.Timezone,
+		LastSentAt: s.LastSentAt,
+		UpdatedAt:  time.Now(),
+	}
+
+	err := r.db.Conn(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "email", "frequency", "timezone", "updated_at"}),
+		}).
+		Create(dbSettings).Error
+	if err != nil {
+		return fmt.Errorf("failed to save digest settings: %w", err)
+	}
+
+	s.UpdatedAt = dbSettings.UpdatedAt
+	return nil
+}
+
+func (r *DigestRepository) MarkSent(ctx context.Context, userID string, sentAt time.Time) error {
+	err := r.db.Conn(ctx).
+		Model(&database.DigestSettings{}).
+		Where("user_id = ?", userID).
+		Update("last_sent_at", sentAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to record digest delivery: %w", err)
+	}
+	return nil
+}
+
+func mapToDigestSettingsDomain(dbSettings *database.DigestSettings) *digest.Settings {
+	return &digest.Settings{
+		UserID:     dbSettings.UserID,
+		Enabled:    dbSettings.Enabled,
+		Email:      dbSettings.Email,
+		Frequency:  digest.Frequency(dbSettings.Frequency),
+		Timezone:   dbSettings.Timezone,
+		LastSentAt: dbSettings.LastSentAt,
+		UpdatedAt:  dbSettings.UpdatedAt,
+	}
+}
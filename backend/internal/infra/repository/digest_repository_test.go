@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+)
+
+func TestDigestRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewDigestRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.FindByUserID(ctx, "user-123")
+	assert.ErrorIs(t, err, digest.ErrNotFound)
+
+	s := digest.NewSettings("user-123")
+	s.Enabled = true
+	s.Email = "reader@example.com"
+	require.NoError(t, repo.Save(ctx, s))
+	require.NoError(t, repo.Save(ctx, digest.NewSettings("user-456")))
+
+	sentAt := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.MarkSent(ctx, "user-123", sentAt))
+
+	// Saving the settings again keeps the delivery record
+	s.Frequency = digest.FrequencyDaily
+	s.Timezone = "Asia/Tokyo"
+	require.NoError(t, repo.Save(ctx, s))
+
+	found, err := repo.FindByUserID(ctx, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, digest.FrequencyDaily, found.Frequency)
+	assert.Equal(t, "Asia/Tokyo", found.Timezone)
+	require.NotNil(t, found.LastSentAt)
+	assert.True(t, sentAt.Equal(*found.LastSentAt))
+
+	enabled, err := repo.FindEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 1)
+	assert.Equal(t, "user-123", enabled[0].UserID)
+}
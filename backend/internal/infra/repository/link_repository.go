@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/link"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type LinkRepository struct {
+	db *database.DB
+}
+
+func NewLinkRepository(db *database.DB) link.Repository {
+	return &LinkRepository{
+		db: db,
+	}
+}
+
+func (r *LinkRepository) FindBySourceIDs(ctx context.Context, sourceIDs []string) (map[string][]*link.Link, error) {
+	result := make(map[string][]*link.Link, len(sourceIDs))
+	if len(sourceIDs) == 0 {
+		return result, nil
+	}
+
+	var dbLinks []database.BookLink
+	err := r.db.Conn(ctx).Where("source_id IN ?", sourceIDs).Order("position").Find(&dbLinks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+
+	for i := range dbLinks {
+		l := mapToLinkDomain(&dbLinks[i])
+		result[l.SourceID] = append(result[l.SourceID], l)
+	}
+	return result, nil
+}
+
+func (r *LinkRepository) FindByTargetIDs(ctx context.Context, targetIDs []string) (map[string][]*link.Link, error) {
+	result := make(map[string][]*link.Link, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	var dbLinks []database.BookLink
+	err := r.db.Conn(ctx).
+		Joins("JOIN books ON books.id = book_links.source_id AND books.deleted_at IS NULL").
+		Where("book_links.target_id IN ?", targetIDs).
+		Order("books.created_at DESC").
+		Find(&dbLinks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backlinks: %w", err)
+	}
+
+	for i := range dbLinks {
+		l := mapToLinkDomain(&dbLinks[i])
+		result[l.TargetID] = append(result[l.TargetID], l)
+	}
+	return result, nil
+}
+
+func (r *LinkRepository) FindUnresolved(ctx context.Context, userID, workspaceID string) ([]*link.Link, error) {
+	query := r.db.Conn(ctx).
+		Joins("JOIN books ON books.id = book_links.source_id AND books.deleted_at IS NULL").
+		Where("book_links.target_id IS NULL")
+	if workspaceID != "" {
+		query = query.Where(
+			"books.workspace_id = ? AND books.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)",
+			workspaceID, userID,
+		)
+	} else {
+		query = query.Where("books.workspace_id IS NULL AND books.user_id = ?", userID)
+	}
+
+	var dbLinks []database.BookLink
+	if err := query.Order("books.created_at DESC, book_links.position").Find(&dbLinks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get unresolved links: %w", err)
+	}
+
+	links := make([]*link.Link, len(dbLinks))
+	for i := range dbLinks {
+		links[i] = mapToLinkDomain(&dbLinks[i])
+	}
+	return links, nil
+}
+
+func mapToLinkDomain(dbLink *database.BookLink) *link.Link {
+	l := &link.Link{
+		SourceID: dbLink.SourceID,
+		Target:   dbLink.Target,
+		Position: dbLink.Position,
+	}
+	if dbLink.TargetID != nil {
+		l.TargetID = *dbLink.TargetID
+	}
+	return l
+}
+
+// The functions below keep book_links in sync with book content. The book
+// repository calls them in the transaction that writes the book.
+//
+// A link resolves within the library of its source book: the workspace the
+// book belongs to, or its owner's personal books. A target that is the ID of
+// a book in the library resolves to that book; otherwise it resolves to the
+// most recently updated book whose title matches, ignoring case.
+
+// inLibrary limits a books query to the library of b
+func inLibrary(b *database.Book) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if b.WorkspaceID != nil {
+			return db.Where("workspace_id = ?", *b.WorkspaceID)
+		}
+		return db.Where("workspace_id IS NULL AND user_id = ?", b.UserID)
+	}
+}
+
+// resolveTarget returns the book in lib's library that target refers to, or
+// nil when there is none
+func resolveTarget(ctx context.Context, db *database.DB, lib *database.Book, target string) (*string, error) {
+	var ids []string
+	if _, err := uuid.Parse(target); err == nil {
+		err := db.Conn(ctx).Model(&database.Book{}).Scopes(inLibrary(lib)).
+			Where("id = ?", target).
+			Limit(1).Pluck("id", &ids).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve link: %w", err)
+		}
+		if len(ids) > 0 {
+			return &ids[0], nil
+		}
+	}
+
+	err := db.Conn(ctx).Model(&database.Book{}).Scopes(inLibrary(lib)).
+		Where("LOWER(title) = LOWER(?)", target).
+		Order("updated_at DESC").
+		Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve link: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return &ids[0], nil
+}
+
+// syncLinks replaces the links written in b with those in its content. It
+// then re-resolves links that point at b by a title b no longer has, and
+// resolves the library's unresolved links that name b's current title.
+func syncLinks(ctx context.Context, db *database.DB, b *database.Book) error {
+	if err := db.Conn(ctx).Where("source_id = ?", b.ID).Delete(&database.BookLink{}).Error; err != nil {
+		return fmt.Errorf("failed to delete links: %w", err)
+	}
+
+	targets := link.Parse(b.Content)
+	if len(targets) > 0 {
+		dbLinks := make([]database.BookLink, len(targets))
+		for i, target := range targets {
+			targetID, err := resolveTarget(ctx, db, b, target)
+			if err != nil {
+				return err
+			}
+			dbLinks[i] = database.BookLink{SourceID: b.ID, Target: target, TargetID: targetID, Position: i}
+		}
+		if err := db.Conn(ctx).Create(&dbLinks).Error; err != nil {
+			return fmt.Errorf("failed to create links: %w", err)
+		}
+	}
+
+	// Links that named the old title of b
+	var stale []string
+	err := db.Conn(ctx).Model(&database.BookLink{}).
+		Where("target_id = ? AND LOWER(target) <> LOWER(?) AND target <> ?", b.ID, b.Title, b.ID).
+		Distinct().Pluck("target", &stale).Error
+	if err != nil {
+		return fmt.Errorf("failed to find stale links: %w", err)
+	}
+	if err := relink(ctx, db, b, stale); err != nil {
+		return err
+	}
+
+	// Links that were waiting for a book with this title
+	err = db.Conn(ctx).Model(&database.BookLink{}).
+		Where("target_id IS NULL AND LOWER(target) = LOWER(?)", b.Title).
+		Where("source_id IN (?)", db.Conn(ctx).Model(&database.Book{}).Scopes(inLibrary(b)).Select("id")).
+		Update("target_id", b.ID).Error
+	if err != nil {
+		return fmt.Errorf("failed to resolve links: %w", err)
+	}
+	return nil
+}
+
+// unlinkBook re-resolves the links pointing at b after it was deleted
+func unlinkBook(ctx context.Context, db *database.DB, b *database.Book) error {
+	var targets []string
+	err := db.Conn(ctx).Model(&database.BookLink{}).
+		Where("target_id = ?", b.ID).
+		Distinct().Pluck("target", &targets).Error
+	if err != nil {
+		return fmt.Errorf("failed to find links: %w", err)
+	}
+	return relink(ctx, db, b, targets)
+}
+
+// relink resolves again every link in lib's library whose target is one of
+// targets
+func relink(ctx context.Context, db *database.DB, lib *database.Book, targets []string) error {
+	for _, target := range targets {
+		targetID, err := resolveTarget(ctx, db, lib, target)
+		if err != nil {
+			return err
+		}
+
+		err = db.Conn(ctx).Model(&database.BookLink{}).
+			Where("LOWER(target) = LOWER(?)", target).
+			Where("source_id IN (?)", db.Conn(ctx).Model(&database.Book{}).Scopes(inLibrary(lib)).Select("id")).
+			Update("target_id", targetID).Error
+		if err != nil {
+			return fmt.Errorf("failed to update links: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/link"
+)
+
+func saveLinkedBook(t *testing.T, repo book.Repository, userID, title, content string) *book.Book {
+	b := book.NewBook(userID, content)
+	b.Title = title
+	require.NoError(t, repo.Save(context.Background(), b))
+	return b
+}
+
+func linkTargets(links []*link.Link) map[string]string {
+	targets := make(map[string]string, len(links))
+	for _, l := range links {
+		targets[l.Target] = l.TargetID
+	}
+	return targets
+}
+
+func TestLinkRepository_ResolvesWithinLibrary(t *testing.T) {
+	db := setupTestDB(t)
+	books := NewBookRepository(db)
+	links := NewLinkRepository(db)
+	ctx := context.Background()
+
+	alpha := saveLinkedBook(t, books, "user-123", "Alpha", "See [[Beta]] and [[Missing]]")
+	// Saving Beta resolves the link that was waiting for it
+	beta := saveLinkedBook(t, books, "user-123", "Beta", "Back to [[alpha]] and [["+alpha.ID+"|by id]]")
+	// Books of other users never match
+	saveLinkedBook(t, books, "user-456", "Missing", "Elsewhere")
+
+	outgoing, err := links.FindBySourceIDs(ctx, []string{alpha.ID, beta.ID})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Beta": beta.ID, "Missing": ""}, linkTargets(outgoing[alpha.ID]))
+	assert.Equal(t, map[string]string{"alpha": alpha.ID, alpha.ID: alpha.ID}, linkTargets(outgoing[beta.ID]))
+	assert.Equal(t, "alpha", outgoing[beta.ID][0].Target)
+
+	backlinks, err := links.FindByTargetIDs(ctx, []string{alpha.ID, beta.ID})
+	require.NoError(t, err)
+	assert.Len(t, backlinks[alpha.ID], 2)
+	require.Len(t, backlinks[beta.ID], 1)
+	assert.Equal(t, alpha.ID, backlinks[beta.ID][0].SourceID)
+
+	unresolved, err := links.FindUnresolved(ctx, "user-123", "")
+	require.NoError(t, err)
+	require.Len(t, unresolved, 1)
+	assert.Equal(t, "Missing", unresolved[0].Target)
+}
+
+func TestLinkRepository_FollowsRenamesAndDeletes(t *testing.T) {
+	db := setupTestDB(t)
+	books := NewBookRepository(db)
+	links := NewLinkRepository(db)
+	ctx := context.Background()
+
+	alpha := saveLinkedBook(t, books, "user-123", "Alpha", "See [[Beta]]")
+	beta := saveLinkedBook(t, books, "user-123", "Beta", "Back to [[Alpha]]")
+
+	// Renaming Beta leaves Alpha's link without a target
+	beta.Title = "Gamma"
+	require.NoError(t, books.Update(ctx, beta, "user-123"))
+
+	outgoing, err := links.FindBySourceIDs(ctx, []string{alpha.ID})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Beta": ""}, linkTargets(outgoing[alpha.ID]))
+
+	// Editing Alpha to name the new title links them again
+	alpha.Content = "See [[Gamma]]"
+	require.NoError(t, books.Update(ctx, alpha, "user-123"))
+
+	backlinks, err := links.FindByTargetIDs(ctx, []string{beta.ID})
+	require.NoError(t, err)
+	assert.Len(t, backlinks[beta.ID], 1)
+
+	// Deleting Alpha drops its links from backlinks and unresolves links to it
+	require.NoError(t, books.Delete(ctx, alpha.ID, "user-123"))
+
+	backlinks, err = links.FindByTargetIDs(ctx, []string{beta.ID})
+	require.NoError(t, err)
+	assert.Empty(t, backlinks[beta.ID])
+
+	unresolved, err := links.FindUnresolved(ctx, "user-123", "")
+	require.NoError(t, err)
+	require.Len(t, unresolved, 1)
+	assert.Equal(t, beta.ID, unresolved[0].SourceID)
+	assert.Equal(t, "Alpha", unresolved[0].Target)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type ReadingRepository struct {
+	db *database.DB
+}
+
+func NewReadingRepository(db *database.DB) reading.Repository {
+	return &ReadingRepository{
+		db: db,
+	}
+}
+
+func (r *ReadingRepository) Save(ctx context.Context, s *reading.State) error {
+	dbState := &database.ReadingState{
+		BookID:     s.BookID,
+		UserID:     s.UserID,
+		Status:     string(s.Status),
+		Progress:   s.Progress,
+		StartedAt:  s.StartedAt,
+		FinishedAt: s.FinishedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+
+	err := r.db.Conn(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "book_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "progress", "started_at", "finished_at", "updated_at"}),
+		}).
+		Create(dbState).Error
+	if err != nil {
+		return fmt.Errorf("failed to save reading state: %w", err)
+	}
+
+	s.UpdatedAt = dbState.UpdatedAt
+	return nil
+}
+
+func (r *ReadingRepository) FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string]*reading.State, error) {
+	result := make(map[string]*reading.State, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return result, nil
+	}
+
+	var dbStates []database.ReadingState
+	err := r.db.Conn(ctx).Where("book_id IN ? AND user_id = ?", bookIDs, userID).Find(&dbStates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading states: %w", err)
+	}
+
+	for i := range dbStates {
+		s := mapToReadingStateDomain(&dbStates[i])
+		result[s.BookID] = s
+	}
+	return result, nil
+}
+
+func mapToReadingStateDomain(dbState *database.ReadingState) *reading.State {
+	return &reading.State{
+		BookID:     dbState.BookID,
+		UserID:     dbState.UserID,
+		Status:     reading.Status(dbState.Status),
+		Progress:   dbState.Progress,
+		StartedAt:  dbState.StartedAt,
+		FinishedAt: dbState.FinishedAt,
+		UpdatedAt:  dbState.UpdatedAt,
+	}
+}
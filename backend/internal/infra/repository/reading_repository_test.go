@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+)
+
+func TestReadingRepository_SaveAndFind(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewReadingRepository(db)
+	ctx := context.Background()
+	createTestBooks(t, db, "user-123", "book-1")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	mine := reading.NewState("user-123", "book-1")
+	progress := 25
+	require.NoError(t, mine.SetStatus(reading.StatusReading, &progress, now))
+	require.NoError(t, repo.Save(ctx, mine))
+
+	theirs := reading.NewState("user-456", "book-1")
+	require.NoError(t, theirs.SetStatus(reading.StatusRead, nil, now))
+	require.NoError(t, repo.Save(ctx, theirs))
+
+	// Saving again replaces the state
+	require.NoError(t, mine.SetStatus(reading.StatusAbandoned, nil, now.Add(time.Hour)))
+	require.NoError(t, repo.Save(ctx, mine))
+
+	states, err := repo.FindByBookIDs(ctx, []string{"book-1", "book-2"}, "user-123")
+	require.NoError(t, err)
+
+	require.Len(t, states, 1)
+	s := states["book-1"]
+	assert.Equal(t, reading.StatusAbandoned, s.Status)
+	assert.Equal(t, 25, s.Progress)
+	assert.True(t, now.Equal(*s.StartedAt))
+	assert.True(t, now.Add(time.Hour).Equal(*s.FinishedAt))
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type SavedSearchRepository struct {
+	db *database.DB
+}
+
+func NewSavedSearchRepository(db *database.DB) search.Repository {
+	return &SavedSearchRepository{
+		db: db,
+	}
+}
+
+func (r *SavedSearchRepository) Save(ctx context.Context, s *search.SavedSearch) error {
+	if err := r.db.Conn(ctx).Create(mapToSavedSearchModel(s)).Error; err != nil {
+		return fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SavedSearchRepository) FindByID(ctx context.Context, id, userID string) (*search.SavedSearch, error) {
+	var dbSearch database.SavedSearch
+	err := r.db.Conn(ctx).Where("id = ? AND user_id = ?", id, userID).First(&dbSearch).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, search.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	return mapToSavedSearchDomain(&dbSearch), nil
+}
+
+func (r *SavedSearchRepository) FindByUserID(ctx context.Context, userID string) ([]*search.SavedSearch, error) {
+	var dbSearches []database.SavedSearch
+	err := r.db.Conn(ctx).
+		Where("user_id = ?", userID).
+		Order("pinned DESC, name, created_at").
+		Find(&dbSearches).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+
+	searches := make([]*search.SavedSearch, len(dbSearches))
+	for i := range dbSearches {
+		searches[i] = mapToSavedSearchDomain(&dbSearches[i])
+	}
+
+	return searches, nil
+}
+
+func (r *SavedSearchRepository) Update(ctx context.Context, s *search.SavedSearch) error {
+	s.UpdatedAt = time.Now()
+
+	// Select the columns so that false booleans are written too
+	result := r.db.Conn(ctx).
+		Model(&database.SavedSearch{}).
+		Where("id = ? AND user_id = ?", s.ID, s.UserID).
+		Select("name", "query", "pinned", "subscribed", "updated_at").
+		Updates(mapToSavedSearchModel(s))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update saved search: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return search.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SavedSearchRepository) Delete(ctx context.Context, id, userID string) error {
+	result := r.db.Conn(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&database.SavedSearch{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete saved search: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return search.ErrNotFound
+	}
+
+	return nil
+}
+
+func mapToSavedSearchModel(s *search.SavedSearch) *database.SavedSearch {
+	var workspaceID *string
+	if s.WorkspaceID != "" {
+		workspaceID = &s.WorkspaceID
+	}
+
+	return &database.SavedSearch{
+		ID:          s.ID,
+		UserID:      s.UserID,
+		WorkspaceID: workspaceID,
+		Name:        s.Name,
+		Query:       s.Query,
+		Pinned:      s.Pinned,
+		Subscribed:  s.Subscribed,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+func mapToSavedSearchDomain(dbSearch *database.SavedSearch) *search.SavedSearch {
+	s := &search.SavedSearch{
+		ID:         dbSearch.ID,
+		UserID:     dbSearch.UserID,
+		Name:       dbSearch.Name,
+		Query:      dbSearch.Query,
+		Pinned:     dbSearch.Pinned,
+		Subscribed: dbSearch.Subscribed,
+		CreatedAt:  dbSearch.CreatedAt,
+		UpdatedAt:  dbSearch.UpdatedAt,
+	}
+	if dbSearch.WorkspaceID != nil {
+		s.WorkspaceID = *dbSearch.WorkspaceID
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+)
+
+func TestSavedSearchRepository_SaveAndFind(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSavedSearchRepository(db)
+	ctx := context.Background()
+	createTestWorkspace(t, db, "11111111-1111-1111-1111-111111111111")
+
+	backlog, err := search.NewSavedSearch("user-123", "", "Go backlog", "tag:go status:unread")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, backlog))
+
+	team, err := search.NewSavedSearch("user-123", "11111111-1111-1111-1111-111111111111", "Team reading", "status:reading")
+	require.NoError(t, err)
+	team.Pinned = true
+	require.NoError(t, repo.Save(ctx, team))
+
+	theirs, err := search.NewSavedSearch("user-456", "", "Another", "rust")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, theirs))
+
+	found, err := repo.FindByID(ctx, team.ID, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, team.WorkspaceID, found.WorkspaceID)
+	assert.Equal(t, "status:reading", found.Query)
+	assert.True(t, found.Pinned)
+
+	_, err = repo.FindByID(ctx, theirs.ID, "user-123")
+	assert.ErrorIs(t, err, search.ErrNotFound)
+
+	// Pinned searches first, then by name
+	searches, err := repo.FindByUserID(ctx, "user-123")
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, "Team reading", searches[0].Name)
+	assert.Equal(t, "Go backlog", searches[1].Name)
+	assert.Empty(t, searches[1].WorkspaceID)
+}
+
+func TestSavedSearchRepository_Update(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSavedSearchRepository(db)
+	ctx := context.Background()
+
+	s, err := search.NewSavedSearch("user-123", "", "Go backlog", "tag:go")
+	require.NoError(t, err)
+	s.Pinned = true
+	require.NoError(t, repo.Save(ctx, s))
+
+	s.Name = "Go reading"
+	s.Query = "tag:go status:reading"
+	s.Pinned = false
+	s.Subscribed = true
+	require.NoError(t, repo.Update(ctx, s))
+
+	found, err := repo.FindByID(ctx, s.ID, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, "Go reading", found.Name)
+	assert.Equal(t, "tag:go status:reading", found.Query)
+	assert.False(t, found.Pinned)
+	assert.True(t, found.Subscribed)
+
+	// Another user's search is left alone
+	s.UserID = "user-456"
+	assert.ErrorIs(t, repo.Update(ctx, s), search.ErrNotFound)
+}
+
+func TestSavedSearchRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSavedSearchRepository(db)
+	ctx := context.Background()
+
+	s, err := search.NewSavedSearch("user-123", "", "Go backlog", "tag:go")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, s))
+
+	assert.ErrorIs(t, repo.Delete(ctx, s.ID, "user-456"), search.ErrNotFound)
+	require.NoError(t, repo.Delete(ctx, s.ID, "user-123"))
+	assert.ErrorIs(t, repo.Delete(ctx, s.ID, "user-123"), search.ErrNotFound)
+}
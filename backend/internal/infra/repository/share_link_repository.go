@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/share"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type ShareLinkRepository struct {
+	db *database.DB
+}
+
+func NewShareLinkRepository(db *database.DB) share.Repository {
+	return &ShareLinkRepository{
+		db: db,
+	}
+}
+
+func (r *ShareLinkRepository) Save(ctx context.Context, l *share.Link) error {
+	dbLink := &database.ShareLink{
+		ID:           l.ID,
+		Token:        l.Token,
+		BookID:       l.BookID,
+		UserID:       l.UserID,
+		PasswordHash: l.PasswordHash,
+		ExpiresAt:    l.ExpiresAt,
+		CreatedAt:    l.CreatedAt,
+	}
+
+	if err := r.db.Conn(ctx).Create(dbLink).Error; err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	l.CreatedAt = dbLink.CreatedAt
+	return nil
+}
+
+func (r *ShareLinkRepository) FindByToken(ctx context.Context, token string) (*share.Link, error) {
+	var dbLink database.ShareLink
+	err := r.db.Conn(ctx).Where("token = ?", token).First(&dbLink).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, share.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	return r.mapToLinkDomain(&dbLink), nil
+}
+
+func (r *ShareLinkRepository) FindByBookID(ctx context.Context, bookID, userID string) ([]*share.Link, error) {
+	var dbLinks []database.ShareLink
+	err := r.db.Conn(ctx).
+		Where("book_id = ? AND user_id = ?", bookID, userID).
+		Order("created_at DESC").
+		Find(&dbLinks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share links: %w", err)
+	}
+
+	links := make([]*share.Link, len(dbLinks))
+	for i, dbLink := range dbLinks {
+		links[i] = r.mapToLinkDomain(&dbLink)
+	}
+
+	return links, nil
+}
+
+func (r *ShareLinkRepository) Revoke(ctx context.Context, id, userID string) error {
+	result := r.db.Conn(ctx).
+		Model(&database.ShareLink{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke share link: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errs.NotFound("share link not found or not authorized")
+	}
+
+	return nil
+}
+
+func (r *ShareLinkRepository) IncrementViewCount(ctx context.Context, id string) error {
+	result := r.db.Conn(ctx).
+		Model(&database.ShareLink{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"view_count":     gorm.Expr("view_count + 1"),
+			"last_viewed_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to record share link view: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (r *ShareLinkRepository) mapToLinkDomain(dbLink *database.ShareLink) *share.Link {
+	return &share.Link{
+		ID:           dbLink.ID,
+		Token:        dbLink.Token,
+		BookID:       dbLink.BookID,
+		UserID:       dbLink.UserID,
+		PasswordHash: dbLink.PasswordHash,
+		ExpiresAt:    dbLink.ExpiresAt,
+		RevokedAt:    dbLink.RevokedAt,
+		ViewCount:    dbLink.ViewCount,
+		LastViewedAt: dbLink.LastViewedAt,
+		CreatedAt:    dbLink.CreatedAt,
+	}
+}
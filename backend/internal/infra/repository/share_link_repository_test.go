@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/share"
+)
+
+func TestShareLinkRepository_SaveAndFindByToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewShareLinkRepository(db)
+	ctx := context.Background()
+	createTestBooks(t, db, "user-123", "book-123")
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	link, err := share.NewLink("user-123", "book-123", &expiresAt)
+	require.NoError(t, err)
+	require.NoError(t, link.SetPassword("secret"))
+
+	err = repo.Save(ctx, link)
+	require.NoError(t, err)
+
+	found, err := repo.FindByToken(ctx, link.Token)
+	require.NoError(t, err)
+
+	assert.Equal(t, link.ID, found.ID)
+	assert.Equal(t, link.BookID, found.BookID)
+	assert.Equal(t, link.UserID, found.UserID)
+	assert.True(t, found.CheckPassword("secret"))
+	require.NotNil(t, found.ExpiresAt)
+	assert.WithinDuration(t, expiresAt, *found.ExpiresAt, time.Second)
+
+	_, err = repo.FindByToken(ctx, "unknown-token")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "share link not found")
+}
+
+func TestShareLinkRepository_Revoke(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewShareLinkRepository(db)
+	ctx := context.Background()
+	createTestBooks(t, db, "user-123", "book-123")
+
+	link, err := share.NewLink("user-123", "book-123", nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, link))
+
+	// Another user cannot revoke the link
+	err = repo.Revoke(ctx, link.ID, "user-456")
+	assert.Error(t, err)
+
+	err = repo.Revoke(ctx, link.ID, "user-123")
+	require.NoError(t, err)
+
+	found, err := repo.FindByToken(ctx, link.Token)
+	require.NoError(t, err)
+	assert.False(t, found.IsActive(time.Now()))
+
+	// Revoking twice reports not found
+	err = repo.Revoke(ctx, link.ID, "user-123")
+	assert.Error(t, err)
+}
+
+func TestShareLinkRepository_IncrementViewCount(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewShareLinkRepository(db)
+	ctx := context.Background()
+	createTestBooks(t, db, "user-123", "book-123")
+
+	link, err := share.NewLink("user-123", "book-123", nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, link))
+
+	require.NoError(t, repo.IncrementViewCount(ctx, link.ID))
+	require.NoError(t, repo.IncrementViewCount(ctx, link.ID))
+
+	links, err := repo.FindByBookID(ctx, "book-123", "user-123")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, 2, links[0].ViewCount)
+	assert.NotNil(t, links[0].LastViewedAt)
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/stats"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+// dayLayout is the layout of the days returned by DayOf and WeekOf
+const dayLayout = "2006-01-02"
+
+type StatsRepository struct {
+	db *database.DB
+}
+
+func NewStatsRepository(db *database.DB) stats.Repository {
+	return &StatsRepository{
+		db: db,
+	}
+}
+
+// bucketCount is a count in the bucket starting on the day Bucket
+type bucketCount struct {
+	Bucket string
+	Count  int
+}
+
+type tagBucketCount struct {
+	Tag    string
+	Bucket string
+	Count  int
+}
+
+func (r *StatsRepository) GetLibraryStats(ctx context.Context, userID, workspaceID string, since time.Time, interval stats.Interval) (*stats.LibraryStats, error) {
+	bucketOf := r.db.DayOf
+	if interval == stats.IntervalWeek {
+		bucketOf = r.db.WeekOf
+	}
+	sinceDay := since.Format(dayLayout)
+	starts := stats.BucketStarts(since, time.Now().UTC(), interval)
+	library := userLibrary(userID, workspaceID)
+	inRange := func(db *gorm.DB) *gorm.DB {
+		return db.Where(r.db.DayOf("books.created_at")+" >= ?", sinceDay)
+	}
+
+	result := &stats.LibraryStats{Interval: interval, Since: since}
+
+	var totals struct {
+		Count         int
+		AverageLength float64
+	}
+	err := r.db.Conn(ctx).Model(&database.Book{}).Scopes(library).
+		Select("COUNT(*) AS count, COALESCE(AVG(LENGTH(books.content)), 0) AS average_length").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count books: %w", err)
+	}
+	result.TotalBooks = totals.Count
+	result.AverageContentLength = int(math.Round(totals.AverageLength))
+
+	// Books saved and books started, over the whole history of the library so
+	// that the backlog before the range is known
+	var saved []bucketCount
+	err = r.db.Conn(ctx).Model(&database.Book{}).Scopes(library).
+		Select(bucketOf("books.created_at") + " AS bucket, COUNT(*) AS count").
+		Group("bucket").
+		Scan(&saved).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count saved books: %w", err)
+	}
+
+	var started []bucketCount
+	err = r.db.Conn(ctx).Model(&database.ReadingState{}).
+		Joins("JOIN books ON books.id = reading_states.book_id AND books.deleted_at IS NULL").
+		Scopes(library).
+		Where("reading_states.user_id = ? AND reading_states.started_at IS NOT NULL", userID).
+		Select(bucketOf("reading_states.started_at") + " AS bucket, COUNT(*) AS count").
+		Group("bucket").
+		Scan(&started).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count started books: %w", err)
+	}
+
+	savedBefore, savedIn := splitBuckets(saved, sinceDay, starts)
+	startedBefore, startedIn := splitBuckets(started, sinceDay, starts)
+	backlog := savedBefore - startedBefore
+	for i, start := range starts {
+		backlog += savedIn[i] - startedIn[i]
+		result.SavedBooks = append(result.SavedBooks, &stats.Bucket{Start: start, Count: savedIn[i]})
+		result.BacklogTrend = append(result.BacklogTrend, &stats.Bucket{Start: start, Count: backlog})
+	}
+	result.BacklogSize = totals.Count - sumCounts(started)
+
+	var tagged []tagBucketCount
+	err = r.db.Conn(ctx).Model(&database.Book{}).
+		Joins("CROSS JOIN "+r.db.JSONArrayElements("books.tags")).
+		Scopes(library, inRange).
+		Select("value AS tag, " + bucketOf("books.created_at") + " AS bucket, COUNT(*) AS count").
+		Group("tag, bucket").
+		Scan(&tagged).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	result.TagTrends = tagTrends(tagged, sinceDay, starts)
+
+	var merged []struct {
+		Tag   string
+		Count int
+	}
+	err = r.db.Conn(ctx).Model(&database.Book{}).
+		Joins("CROSS JOIN "+r.db.JSONArrayElements("books.tags")).
+		Scopes(library, inRange).
+		Where("books.merged_from > 0").
+		Select("value AS tag, COUNT(*) AS count").
+		Group("tag").
+		Order("count DESC, tag").
+		Limit(stats.MaxTags).
+		Scan(&merged).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count merged topics: %w", err)
+	}
+	result.MergedTopics = []*stats.TagCount{}
+	for _, m := range merged {
+		result.MergedTopics = append(result.MergedTopics, &stats.TagCount{Tag: m.Tag, Count: m.Count})
+	}
+
+	var outcomes []struct {
+		Enrichment string
+		Count      int
+	}
+	err = r.db.Conn(ctx).Model(&database.Book{}).
+		Scopes(library, inRange).
+		Where("books.enrichment <> ''").
+		Select("books.enrichment AS enrichment, COUNT(*) AS count").
+		Group("books.enrichment").
+		Scan(&outcomes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count enrichments: %w", err)
+	}
+	for _, o := range outcomes {
+		result.EnrichmentAttempts += o.Count
+		if book.Enrichment(o.Enrichment) == book.EnrichmentSucceeded {
+			result.EnrichmentSuccesses += o.Count
+		}
+	}
+
+	return result, nil
+}
+
+// userLibrary limits a books query to the user's personal books, or to a
+// workspace the user is a member of when workspaceID is set
+func userLibrary(userID, workspaceID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if workspaceID != "" {
+			return db.Where(
+				"books.workspace_id = ? AND books.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)",
+				workspaceID, userID,
+			)
+		}
+		return db.Where("books.workspace_id IS NULL AND books.user_id = ?", userID)
+	}
+}
+
+// splitBuckets returns the total of the counts before sinceDay, and the count
+// of each of starts
+func splitBuckets(counts []bucketCount, sinceDay string, starts []time.Time) (int, []int) {
+	index := bucketIndex(starts)
+	before := 0
+	in := make([]int, len(starts))
+	for _, c := range counts {
+		if c.Bucket < sinceDay {
+			before += c.Count
+		} else if i, ok := index[c.Bucket]; ok {
+			in[i] += c.Count
+		}
+	}
+	return before, in
+}
+
+// tagTrends follows the most frequent tags over starts
+func tagTrends(counts []tagBucketCount, sinceDay string, starts []time.Time) []*stats.TagTrend {
+	index := bucketIndex(starts)
+	byTag := map[string]*stats.TagTrend{}
+	for _, c := range counts {
+		i, ok := index[c.Bucket]
+		if !ok || c.Bucket < sinceDay {
+			continue
+		}
+		trend, ok := byTag[c.Tag]
+		if !ok {
+			trend = &stats.TagTrend{Tag: c.Tag, Buckets: make([]*stats.Bucket, len(starts))}
+			for j, start := range starts {
+				trend.Buckets[j] = &stats.Bucket{Start: start}
+			}
+			byTag[c.Tag] = trend
+		}
+		trend.Count += c.Count
+		trend.Buckets[i].Count += c.Count
+	}
+
+	trends := make([]*stats.TagTrend, 0, len(byTag))
+	for _, trend := range byTag {
+		trends = append(trends, trend)
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		if trends[i].Count != trends[j].Count {
+			return trends[i].Count > trends[j].Count
+		}
+		return trends[i].Tag < trends[j].Tag
+	})
+	if len(trends) > stats.MaxTags {
+		trends = trends[:stats.MaxTags]
+	}
+	return trends
+}
+
+func bucketIndex(starts []time.Time) map[string]int {
+	index := make(map[string]int, len(starts))
+	for i, start := range starts {
+		index[start.Format(dayLayout)] = i
+	}
+	return index
+}
+
+func sumCounts(counts []bucketCount) int {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	return total
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/stats"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+func TestStatsRepository_GetLibraryStats(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewStatsRepository(db)
+	ctx := context.Background()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	daysAgo := func(n int) time.Time {
+		return today.AddDate(0, 0, -n).Add(time.Hour)
+	}
+	workspaceID := "11111111-1111-1111-1111-111111111111"
+	createTestWorkspace(t, db, workspaceID)
+
+	books := []database.Book{
+		{ID: "old", Title: "old", Content: "aaaa", Tags: []string{"go"}, UserID: "user-123", CreatedAt: daysAgo(20)},
+		{ID: "old-read", Title: "old read", Content: "aaaa", Tags: []string{}, UserID: "user-123", CreatedAt: daysAgo(20)},
+		{ID: "b1", Title: "b1", Content: "aa", Tags: []string{"go", "api"}, UserID: "user-123", Enrichment: "succeeded", CreatedAt: daysAgo(3)},
+		{ID: "b2", Title: "b2", Content: "aa", Tags: []string{"go"}, UserID: "user-123", Enrichment: "failed", MergedFrom: 2, CreatedAt: daysAgo(3)},
+		{ID: "b3", Title: "b3", Content: "積読", Tags: []string{"rust"}, UserID: "user-123", CreatedAt: daysAgo(0)},
+		{ID: "theirs", Title: "theirs", Content: "a", Tags: []string{"go"}, UserID: "user-456", CreatedAt: daysAgo(0)},
+		{ID: "shared", Title: "shared", Content: "a", Tags: []string{"go"}, UserID: "user-456", WorkspaceID: &workspaceID, CreatedAt: daysAgo(0)},
+	}
+	require.NoError(t, db.Create(&books).Error)
+
+	startedOld, startedToday := daysAgo(10), daysAgo(0)
+	states := []database.ReadingState{
+		{BookID: "old-read", UserID: "user-123", Status: "read", StartedAt: &startedOld, FinishedAt: &startedOld},
+		{BookID: "b3", UserID: "user-123", Status: "reading", StartedAt: &startedToday},
+		{BookID: "old", UserID: "user-456", Status: "reading", StartedAt: &startedToday},
+	}
+	require.NoError(t, db.Create(&states).Error)
+
+	since, interval := stats.RangeWeek.Window(time.Now())
+	result, err := repo.GetLibraryStats(ctx, "user-123", "", since, interval)
+	require.NoError(t, err)
+
+	counts := func(buckets []*stats.Bucket) []int {
+		result := make([]int, len(buckets))
+		for i, b := range buckets {
+			result[i] = b.Count
+		}
+		return result
+	}
+
+	assert.Equal(t, 5, result.TotalBooks)
+	assert.Equal(t, 3, result.AverageContentLength)
+	assert.Equal(t, since, result.SavedBooks[0].Start)
+	assert.Equal(t, []int{0, 0, 0, 2, 0, 0, 1}, counts(result.SavedBooks))
+	// One unread book before the range, two saved, then one saved and one started
+	assert.Equal(t, 3, result.BacklogSize)
+	assert.Equal(t, []int{1, 1, 1, 3, 3, 3, 3}, counts(result.BacklogTrend))
+
+	require.Len(t, result.TagTrends, 3)
+	assert.Equal(t, "go", result.TagTrends[0].Tag)
+	assert.Equal(t, 2, result.TagTrends[0].Count)
+	assert.Equal(t, []int{0, 0, 0, 2, 0, 0, 0}, counts(result.TagTrends[0].Buckets))
+	assert.Equal(t, "api", result.TagTrends[1].Tag)
+	assert.Equal(t, "rust", result.TagTrends[2].Tag)
+
+	assert.Equal(t, []*stats.TagCount{{Tag: "go", Count: 1}}, result.MergedTopics)
+	assert.Equal(t, 2, result.EnrichmentAttempts)
+	assert.Equal(t, 1, result.EnrichmentSuccesses)
+
+	t.Run("workspace needs membership", func(t *testing.T) {
+		result, err := repo.GetLibraryStats(ctx, "user-123", workspaceID, since, interval)
+		require.NoError(t, err)
+		assert.Zero(t, result.TotalBooks)
+
+		require.NoError(t, db.Create(&database.WorkspaceMember{WorkspaceID: workspaceID, UserID: "user-123", Role: "viewer"}).Error)
+		result, err = repo.GetLibraryStats(ctx, "user-123", workspaceID, since, interval)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.TotalBooks)
+		assert.Equal(t, 1, result.BacklogSize)
+	})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type TagRepository struct {
+	db *database.DB
+}
+
+func NewTagRepository(db *database.DB) tag.Repository {
+	return &TagRepository{
+		db: db,
+	}
+}
+
+func (r *TagRepository) FindSynonyms(ctx context.Context, userID string) ([]*tag.Synonym, error) {
+	var dbSynonyms []database.TagSynonym
+	if err := r.db.Conn(ctx).Where("user_id = ?", userID).Order("alias").Find(&dbSynonyms).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tag synonyms: %w", err)
+	}
+
+	synonyms := make([]*tag.Synonym, len(dbSynonyms))
+	for i := range dbSynonyms {
+		synonyms[i] = mapToTagSynonymDomain(&dbSynonyms[i])
+	}
+	return synonyms, nil
+}
+
+func (r *TagRepository) SaveSynonym(ctx context.Context, s *tag.Synonym) error {
+	dbSynonym := &database.TagSynonym{
+		UserID:    s.UserID,
+		Alias:     s.Alias,
+		Tag:       s.Tag,
+		CreatedAt: time.Now(),
+	}
+
+	err := r.db.Conn(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "alias"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tag"}),
+		}).
+		Create(dbSynonym).Error
+	if err != nil {
+		return fmt.Errorf("failed to save tag synonym: %w", err)
+	}
+
+	s.CreatedAt = dbSynonym.CreatedAt
+	return nil
+}
+
+func (r *TagRepository) DeleteSynonym(ctx context.Context, userID, alias string) error {
+	result := r.db.Conn(ctx).Where("user_id = ? AND alias = ?", userID, alias).Delete(&database.TagSynonym{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete tag synonym: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return tag.ErrSynonymNotFound
+	}
+	return nil
+}
+
+func (r *TagRepository) FindBookTags(ctx context.Context, userID, workspaceID string) ([][]string, error) {
+	var dbBooks []database.Book
+	err := r.db.Conn(ctx).
+		Select("books.tags").
+		Scopes(userLibrary(userID, workspaceID)).
+		Order("books.created_at, books.id").
+		Find(&dbBooks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	tags := make([][]string, len(dbBooks))
+	for i := range dbBooks {
+		tags[i] = dbBooks[i].Tags
+	}
+	return tags, nil
+}
+
+func mapToTagSynonymDomain(dbSynonym *database.TagSynonym) *tag.Synonym {
+	return &tag.Synonym{
+		UserID:    dbSynonym.UserID,
+		Alias:     dbSynonym.Alias,
+		Tag:       dbSynonym.Tag,
+		CreatedAt: dbSynonym.CreatedAt,
+	}
+}
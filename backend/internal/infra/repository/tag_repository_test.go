@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+func TestTagRepository_Synonyms(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTagRepository(db)
+	ctx := context.Background()
+
+	golang := &tag.Synonym{UserID: "user-123", Alias: "golang", Tag: "go"}
+	require.NoError(t, repo.SaveSynonym(ctx, golang))
+	assert.False(t, golang.CreatedAt.IsZero())
+	require.NoError(t, repo.SaveSynonym(ctx, &tag.Synonym{UserID: "user-123", Alias: "js", Tag: "lang/javascript"}))
+	require.NoError(t, repo.SaveSynonym(ctx, &tag.Synonym{UserID: "user-456", Alias: "golang", Tag: "golang"}))
+
+	// Saving an alias again points it at the new tag
+	require.NoError(t, repo.SaveSynonym(ctx, &tag.Synonym{UserID: "user-123", Alias: "golang", Tag: "lang/go"}))
+
+	synonyms, err := repo.FindSynonyms(ctx, "user-123")
+	require.NoError(t, err)
+	require.Len(t, synonyms, 2)
+	assert.Equal(t, "golang", synonyms[0].Alias)
+	assert.Equal(t, "lang/go", synonyms[0].Tag)
+	assert.Equal(t, "js", synonyms[1].Alias)
+
+	require.NoError(t, repo.DeleteSynonym(ctx, "user-123", "golang"))
+	assert.ErrorIs(t, repo.DeleteSynonym(ctx, "user-123", "golang"), tag.ErrSynonymNotFound)
+
+	synonyms, err = repo.FindSynonyms(ctx, "user-456")
+	require.NoError(t, err)
+	assert.Len(t, synonyms, 1)
+}
+
+func TestTagRepository_FindBookTags(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTagRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	workspaceID := "11111111-1111-1111-1111-111111111111"
+	createTestWorkspace(t, db, workspaceID)
+	books := []database.Book{
+		{ID: "b2", Title: "b2", Content: "a", Tags: []string{"lang/rust"}, UserID: "user-123", CreatedAt: now},
+		{ID: "b1", Title: "b1", Content: "a", Tags: []string{"lang/go", "web"}, UserID: "user-123", CreatedAt: now.Add(-time.Hour)},
+		{ID: "theirs", Title: "theirs", Content: "a", Tags: []string{"cooking"}, UserID: "user-456", CreatedAt: now},
+		{ID: "shared", Title: "shared", Content: "a", Tags: []string{"lang/go"}, UserID: "user-456", WorkspaceID: &workspaceID, CreatedAt: now},
+	}
+	require.NoError(t, db.Create(&books).Error)
+	require.NoError(t, db.Create(&database.WorkspaceMember{WorkspaceID: workspaceID, UserID: "user-123", Role: "viewer"}).Error)
+
+	tags, err := repo.FindBookTags(ctx, "user-123", "")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"lang/go", "web"}, {"lang/rust"}}, tags)
+
+	tags, err = repo.FindBookTags(ctx, "user-123", workspaceID)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"lang/go"}}, tags)
+
+	// Only members see a workspace's tags
+	tags, err = repo.FindBookTags(ctx, "user-789", workspaceID)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type WebhookRepository struct {
+	db *database.DB
+}
+
+func NewWebhookRepository(db *database.DB) webhook.Repository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+func (r *WebhookRepository) Save(ctx context.Context, w *webhook.Webhook) error {
+	events := make([]string, len(w.Events))
+	for i, e := range w.Events {
+		events[i] = string(e)
+	}
+
+	dbWebhook := &database.Webhook{
+		ID:        w.ID,
+		UserID:    w.UserID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Events:    strings.Join(events, ","),
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+
+	if err := r.db.Conn(ctx).Create(dbWebhook).Error; err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) FindByID(ctx context.Context, id, userID string) (*webhook.Webhook, error) {
+	var dbWebhook database.Webhook
+	err := r.db.Conn(ctx).Where("id = ? AND user_id = ?", id, userID).First(&dbWebhook).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, webhook.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return r.mapToWebhookDomain(&dbWebhook), nil
+}
+
+func (r *WebhookRepository) FindByUserID(ctx context.Context, userID string) ([]*webhook.Webhook, error) {
+	var dbWebhooks []database.Webhook
+	err := r.db.Conn(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&dbWebhooks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	webhooks := make([]*webhook.Webhook, len(dbWebhooks))
+	for i, dbWebhook := range dbWebhooks {
+		webhooks[i] = r.mapToWebhookDomain(&dbWebhook)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id, userID string) error {
+	return r.db.RunInTransaction(ctx, func(ctx context.Context) error {
+		result := r.db.Conn(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&database.Webhook{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete webhook: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errs.NotFound("webhook not found or not authorized")
+		}
+
+		if err := r.db.Conn(ctx).Where("webhook_id = ?", id).Delete(&database.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, d *webhook.Delivery) error {
+	dbDelivery := &database.WebhookDelivery{
+		ID:         d.ID,
+		WebhookID:  d.WebhookID,
+		EventID:    d.EventID,
+		EventType:  string(d.EventType),
+		Payload:    d.Payload,
+		Attempt:    d.Attempt,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Succeeded:  d.Succeeded,
+		CreatedAt:  d.CreatedAt,
+	}
+
+	if err := r.db.Conn(ctx).Create(dbDelivery).Error; err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, webhookID, userID string, limit int) ([]*webhook.Delivery, error) {
+	var dbDeliveries []database.WebhookDelivery
+	err := r.db.Conn(ctx).
+		Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+		Where("webhook_deliveries.webhook_id = ? AND webhooks.user_id = ?", webhookID, userID).
+		Order("webhook_deliveries.created_at DESC").
+		Limit(limit).
+		Find(&dbDeliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*webhook.Delivery, len(dbDeliveries))
+	for i, dbDelivery := range dbDeliveries {
+		deliveries[i] = &webhook.Delivery{
+			ID:         dbDelivery.ID,
+			WebhookID:  dbDelivery.WebhookID,
+			EventID:    dbDelivery.EventID,
+			EventType:  webhook.EventType(dbDelivery.EventType),
+			Payload:    dbDelivery.Payload,
+			Attempt:    dbDelivery.Attempt,
+			StatusCode: dbDelivery.StatusCode,
+			Error:      dbDelivery.Error,
+			Succeeded:  dbDelivery.Succeeded,
+			CreatedAt:  dbDelivery.CreatedAt,
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) mapToWebhookDomain(dbWebhook *database.Webhook) *webhook.Webhook {
+	var events []webhook.EventType
+	for _, e := range strings.Split(dbWebhook.Events, ",") {
+		if e != "" {
+			events = append(events, webhook.EventType(e))
+		}
+	}
+
+	return &webhook.Webhook{
+		ID:        dbWebhook.ID,
+		UserID:    dbWebhook.UserID,
+		URL:       dbWebhook.URL,
+		Secret:    dbWebhook.Secret,
+		Events:    events,
+		CreatedAt: dbWebhook.CreatedAt,
+		UpdatedAt: dbWebhook.UpdatedAt,
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+func TestWebhookRepository_SaveAndFind(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWebhookRepository(db)
+	ctx := context.Background()
+
+	events := []webhook.EventType{webhook.EventBookCreated, webhook.EventBookMerged}
+	w, err := webhook.NewWebhook("user-123", "https://example.com/hook", "s3cret", events)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, w))
+
+	found, err := repo.FindByID(ctx, w.ID, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", found.URL)
+	assert.Equal(t, "s3cret", found.Secret)
+	assert.Equal(t, events, found.Events)
+
+	_, err = repo.FindByID(ctx, w.ID, "other-user")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook not found")
+
+	webhooks, err := repo.FindByUserID(ctx, "user-123")
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+}
+
+func TestWebhookRepository_Deliveries(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWebhookRepository(db)
+	ctx := context.Background()
+
+	w, err := webhook.NewWebhook("user-123", "https://example.com/hook", "", []webhook.EventType{webhook.EventBookCreated})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, w))
+
+	eventID := uuid.New().String()
+	start := time.Now()
+	for attempt := 1; attempt <= 3; attempt++ {
+		err := repo.SaveDelivery(ctx, &webhook.Delivery{
+			ID:         uuid.New().String(),
+			WebhookID:  w.ID,
+			EventID:    eventID,
+			EventType:  webhook.EventBookCreated,
+			Payload:    "{}",
+			Attempt:    attempt,
+			StatusCode: 500,
+			CreatedAt:  start.Add(time.Duration(attempt) * time.Second),
+		})
+		require.NoError(t, err)
+	}
+
+	deliveries, err := repo.FindDeliveries(ctx, w.ID, "user-123", 2)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.Equal(t, 2, deliveries[1].Attempt)
+
+	deliveries, err = repo.FindDeliveries(ctx, w.ID, "other-user", 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	require.NoError(t, repo.Delete(ctx, w.ID, "user-123"))
+	var count int64
+	db.Model(&database.WebhookDelivery{}).Count(&count)
+	assert.Zero(t, count)
+
+	err = repo.Delete(ctx, w.ID, "user-123")
+	assert.Error(t, err)
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+type WorkspaceRepository struct {
+	db *database.DB
+}
+
+func NewWorkspaceRepository(db *database.DB) workspace.Repository {
+	return &WorkspaceRepository{
+		db: db,
+	}
+}
+
+func (r *WorkspaceRepository) Save(ctx context.Context, ws *workspace.Workspace) error {
+	dbWorkspace := &database.Workspace{
+		ID:        ws.ID,
+		Name:      ws.Name,
+		OwnerID:   ws.OwnerID,
+		CreatedAt: ws.CreatedAt,
+		UpdatedAt: ws.UpdatedAt,
+	}
+
+	if err := r.db.Conn(ctx).Create(dbWorkspace).Error; err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) FindByID(ctx context.Context, id, userID string) (*workspace.Workspace, error) {
+	var dbWorkspace database.Workspace
+	err := r.db.Conn(ctx).
+		Where("id = ? AND id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)", id, userID).
+		First(&dbWorkspace).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, workspace.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return r.mapToWorkspaceDomain(&dbWorkspace), nil
+}
+
+func (r *WorkspaceRepository) FindByUserID(ctx context.Context, userID string) ([]*workspace.Workspace, error) {
+	var dbWorkspaces []database.Workspace
+	err := r.db.Conn(ctx).
+		Where("id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)", userID).
+		Order("name ASC").
+		Find(&dbWorkspaces).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+
+	workspaces := make([]*workspace.Workspace, len(dbWorkspaces))
+	for i, dbWorkspace := range dbWorkspaces {
+		workspaces[i] = r.mapToWorkspaceDomain(&dbWorkspace)
+	}
+
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) AddMember(ctx context.Context, m *workspace.Member) error {
+	dbMember := &database.WorkspaceMember{
+		WorkspaceID: m.WorkspaceID,
+		UserID:      m.UserID,
+		Role:        string(m.Role),
+	}
+
+	if err := r.db.Conn(ctx).Create(dbMember).Error; err != nil {
+		return fmt.Errorf("failed to add workspace member: %w", err)
+	}
+
+	m.JoinedAt = dbMember.CreatedAt
+	return nil
+}
+
+func (r *WorkspaceRepository) FindMember(ctx context.Context, workspaceID, userID string) (*workspace.Member, error) {
+	var dbMember database.WorkspaceMember
+	err := r.db.Conn(ctx).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&dbMember).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, workspace.ErrMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+
+	return r.mapToMemberDomain(&dbMember), nil
+}
+
+func (r *WorkspaceRepository) FindMembers(ctx context.Context, workspaceID string) ([]*workspace.Member, error) {
+	var dbMembers []database.WorkspaceMember
+	err := r.db.Conn(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").
+		Find(&dbMembers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+
+	members := make([]*workspace.Member, len(dbMembers))
+	for i, dbMember := range dbMembers {
+		members[i] = r.mapToMemberDomain(&dbMember)
+	}
+
+	return members, nil
+}
+
+func (r *WorkspaceRepository) FindMembersByWorkspaceIDs(ctx context.Context, workspaceIDs []string) (map[string][]*workspace.Member, error) {
+	members := make(map[string][]*workspace.Member, len(workspaceIDs))
+	if len(workspaceIDs) == 0 {
+		return members, nil
+	}
+
+	var dbMembers []database.WorkspaceMember
+	err := r.db.Conn(ctx).
+		Where("workspace_id IN ?", workspaceIDs).
+		Order("created_at ASC").
+		Find(&dbMembers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+
+	for _, dbMember := range dbMembers {
+		members[dbMember.WorkspaceID] = append(members[dbMember.WorkspaceID], r.mapToMemberDomain(&dbMember))
+	}
+
+	return members, nil
+}
+
+func (r *WorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID string, role workspace.Role) error {
+	result := r.db.Conn(ctx).
+		Model(&database.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Update("role", string(role))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update workspace member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return workspace.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	result := r.db.Conn(ctx).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Delete(&database.WorkspaceMember{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return workspace.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) SaveInvitation(ctx context.Context, inv *workspace.Invitation) error {
+	dbInvitation := &database.WorkspaceInvitation{
+		ID:          inv.ID,
+		WorkspaceID: inv.WorkspaceID,
+		Token:       inv.Token,
+		Role:        string(inv.Role),
+		InvitedBy:   inv.InvitedBy,
+		ExpiresAt:   inv.ExpiresAt,
+		CreatedAt:   inv.CreatedAt,
+	}
+
+	if err := r.db.Conn(ctx).Create(dbInvitation).Error; err != nil {
+		return fmt.Errorf("failed to create workspace invitation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) FindInvitationByToken(ctx context.Context, token string) (*workspace.Invitation, error) {
+	var dbInvitation database.WorkspaceInvitation
+	err := r.db.Conn(ctx).Where("token = ?", token).First(&dbInvitation).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, workspace.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace invitation: %w", err)
+	}
+
+	inv := &workspace.Invitation{
+		ID:          dbInvitation.ID,
+		WorkspaceID: dbInvitation.WorkspaceID,
+		Token:       dbInvitation.Token,
+		Role:        workspace.Role(dbInvitation.Role),
+		InvitedBy:   dbInvitation.InvitedBy,
+		ExpiresAt:   dbInvitation.ExpiresAt,
+		AcceptedAt:  dbInvitation.AcceptedAt,
+		CreatedAt:   dbInvitation.CreatedAt,
+	}
+	if dbInvitation.AcceptedBy != nil {
+		inv.AcceptedBy = *dbInvitation.AcceptedBy
+	}
+
+	return inv, nil
+}
+
+func (r *WorkspaceRepository) MarkInvitationAccepted(ctx context.Context, id, userID string) error {
+	result := r.db.Conn(ctx).
+		Model(&database.WorkspaceInvitation{}).
+		Where("id = ? AND accepted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"accepted_by": userID,
+			"accepted_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to accept workspace invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errs.Conflict("workspace invitation already accepted")
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) mapToWorkspaceDomain(dbWorkspace *database.Workspace) *workspace.Workspace {
+	return &workspace.Workspace{
+		ID:        dbWorkspace.ID,
+		Name:      dbWorkspace.Name,
+		OwnerID:   dbWorkspace.OwnerID,
+		CreatedAt: dbWorkspace.CreatedAt,
+		UpdatedAt: dbWorkspace.UpdatedAt,
+	}
+}
+
+func (r *WorkspaceRepository) mapToMemberDomain(dbMember *database.WorkspaceMember) *workspace.Member {
+	return &workspace.Member{
+		WorkspaceID: dbMember.WorkspaceID,
+		UserID:      dbMember.UserID,
+		Role:        workspace.Role(dbMember.Role),
+		JoinedAt:    dbMember.CreatedAt,
+	}
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
+	"github.com/motoya-k/tsundoc/internal/infra/database"
+)
+
+func TestWorkspaceRepository_MembershipScopesWorkspaces(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWorkspaceRepository(db)
+	ctx := context.Background()
+
+	ws := workspace.NewWorkspace("user-123", "Research")
+	require.NoError(t, repo.Save(ctx, ws))
+	require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: ws.ID, UserID: "user-123", Role: workspace.RoleOwner}))
+
+	found, err := repo.FindByID(ctx, ws.ID, "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, "Research", found.Name)
+
+	// Non-members cannot see the workspace
+	_, err = repo.FindByID(ctx, ws.ID, "user-456")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "workspace not found")
+
+	workspaces, err := repo.FindByUserID(ctx, "user-456")
+	require.NoError(t, err)
+	assert.Empty(t, workspaces)
+
+	require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: ws.ID, UserID: "user-456", Role: workspace.RoleViewer}))
+
+	workspaces, err = repo.FindByUserID(ctx, "user-456")
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+	assert.Equal(t, ws.ID, workspaces[0].ID)
+}
+
+func TestWorkspaceRepository_Members(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWorkspaceRepository(db)
+	ctx := context.Background()
+
+	ws := workspace.NewWorkspace("user-123", "Research")
+	require.NoError(t, repo.Save(ctx, ws))
+	require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: ws.ID, UserID: "user-123", Role: workspace.RoleOwner}))
+	require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: ws.ID, UserID: "user-456", Role: workspace.RoleViewer}))
+
+	err := repo.UpdateMemberRole(ctx, ws.ID, "user-456", workspace.RoleEditor)
+	require.NoError(t, err)
+
+	member, err := repo.FindMember(ctx, ws.ID, "user-456")
+	require.NoError(t, err)
+	assert.Equal(t, workspace.RoleEditor, member.Role)
+
+	require.NoError(t, repo.RemoveMember(ctx, ws.ID, "user-456"))
+
+	members, err := repo.FindMembers(ctx, ws.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "user-123", members[0].UserID)
+
+	err = repo.RemoveMember(ctx, ws.ID, "user-456")
+	assert.Error(t, err)
+}
+
+func TestWorkspaceRepository_FindMembersByWorkspaceIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWorkspaceRepository(db)
+	ctx := context.Background()
+
+	research := workspace.NewWorkspace("user-123", "Research")
+	reading := workspace.NewWorkspace("user-456", "Reading")
+	require.NoError(t, repo.Save(ctx, research))
+	require.NoError(t, repo.Save(ctx, reading))
+	require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: research.ID, UserID: "user-123", Role: workspace.RoleOwner}))
+	require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: research.ID, UserID: "user-456", Role: workspace.RoleViewer}))
+	require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: reading.ID, UserID: "user-456", Role: workspace.RoleOwner}))
+
+	members, err := repo.FindMembersByWorkspaceIDs(ctx, []string{research.ID, reading.ID, "ws-unknown"})
+	require.NoError(t, err)
+	assert.Len(t, members[research.ID], 2)
+	require.Len(t, members[reading.ID], 1)
+	assert.Equal(t, "user-456", members[reading.ID][0].UserID)
+	assert.Empty(t, members["ws-unknown"])
+}
+
+func TestWorkspaceRepository_Invitations(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWorkspaceRepository(db)
+	ctx := context.Background()
+	createTestWorkspace(t, db, "ws-123")
+
+	inv, err := workspace.NewInvitation("ws-123", "user-123", workspace.RoleEditor)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveInvitation(ctx, inv))
+
+	found, err := repo.FindInvitationByToken(ctx, inv.Token)
+	require.NoError(t, err)
+	assert.Equal(t, workspace.RoleEditor, found.Role)
+	assert.True(t, found.IsPending(time.Now()))
+
+	require.NoError(t, repo.MarkInvitationAccepted(ctx, inv.ID, "user-456"))
+
+	found, err = repo.FindInvitationByToken(ctx, inv.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-456", found.AcceptedBy)
+	assert.False(t, found.IsPending(time.Now()))
+
+	// An invitation can only be used once
+	err = repo.MarkInvitationAccepted(ctx, inv.ID, "user-789")
+	assert.Error(t, err)
+}
+
+func TestWorkspaceRepository_RollsBackInTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWorkspaceRepository(db)
+	ctx := context.Background()
+
+	t.Run("failed owner insert discards the workspace", func(t *testing.T) {
+		ws := workspace.NewWorkspace("user-123", "Research")
+		failure := errors.New("failed to add owner")
+
+		err := db.RunInTransaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, repo.Save(ctx, ws))
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		workspaces, err := repo.FindByUserID(ctx, "user-123")
+		require.NoError(t, err)
+		assert.Empty(t, workspaces)
+
+		var count int64
+		require.NoError(t, db.Model(&database.Workspace{}).Where("id = ?", ws.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("failed join keeps the invitation pending", func(t *testing.T) {
+		createTestWorkspace(t, db, "ws-123")
+		require.NoError(t, repo.AddMember(ctx, &workspace.Member{WorkspaceID: "ws-123", UserID: "user-456", Role: workspace.RoleViewer}))
+
+		inv, err := workspace.NewInvitation("ws-123", "user-123", workspace.RoleEditor)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveInvitation(ctx, inv))
+
+		err = db.RunInTransaction(ctx, func(ctx context.Context) error {
+			if err := repo.MarkInvitationAccepted(ctx, inv.ID, "user-456"); err != nil {
+				return err
+			}
+			// user-456 is already a member, so the insert violates the primary key
+			return repo.AddMember(ctx, &workspace.Member{WorkspaceID: "ws-123", UserID: "user-456", Role: inv.Role})
+		})
+		require.Error(t, err)
+
+		found, err := repo.FindInvitationByToken(ctx, inv.Token)
+		require.NoError(t, err)
+		assert.True(t, found.IsPending(time.Now()))
+		assert.Empty(t, found.AcceptedBy)
+	})
+}
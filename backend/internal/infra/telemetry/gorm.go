@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	gormSpanKey  = "telemetry:span"
+	gormStartKey = "telemetry:start"
+)
+
+// GORMPlugin traces and times every statement run through GORM
+type GORMPlugin struct {
+	metrics *Metrics
+}
+
+// NewGORMPlugin returns a plugin to install with db.Use
+func NewGORMPlugin(m *Metrics) *GORMPlugin {
+	return &GORMPlugin{metrics: m}
+}
+
+func (p *GORMPlugin) Name() string {
+	return "telemetry"
+}
+
+func (p *GORMPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(string, func(*gorm.DB)) error
+		after     func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+
+	for _, proc := range processors {
+		if err := proc.before("telemetry:before_"+proc.operation, p.before(proc.operation)); err != nil {
+			return err
+		}
+		if err := proc.after("telemetry:after_"+proc.operation, p.after(proc.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *GORMPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := Tracer().Start(db.Statement.Context, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", db.Dialector.Name())),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+		db.InstanceSet(gormStartKey, time.Now())
+	}
+}
+
+func (p *GORMPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		// A missing row is an expected result, not a failed query
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		if v, ok := db.InstanceGet(gormStartKey); ok {
+			p.metrics.ObserveDBQuery(operation, table, err, time.Since(v.(time.Time)))
+		}
+
+		v, ok := db.InstanceGet(gormSpanKey)
+		if !ok {
+			return
+		}
+		span := v.(trace.Span)
+		span.SetAttributes(
+			attribute.String("db.sql.table", table),
+			attribute.String("db.statement", db.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", db.RowsAffected),
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,52 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type note struct {
+	ID    uint
+	Title string
+}
+
+func TestGORMPlugin(t *testing.T) {
+	recorder := recordSpans(t)
+	m := NewMetrics()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&note{}))
+	require.NoError(t, db.Use(NewGORMPlugin(m)))
+
+	require.NoError(t, db.Create(&note{Title: "first"}).Error)
+
+	var found note
+	err = db.First(&found, "title = ?", "missing").Error
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	err = db.Exec("SELECT * FROM no_such_table").Error
+	assert.Error(t, err)
+
+	assert.Equal(t, uint64(1), sampleCount(t, m, "tsundoc_db_query_duration_seconds", map[string]string{
+		"operation": "create", "table": "notes", "outcome": OutcomeSuccess,
+	}))
+	// Not found is an expected result rather than a failure
+	assert.Equal(t, uint64(1), sampleCount(t, m, "tsundoc_db_query_duration_seconds", map[string]string{
+		"operation": "query", "table": "notes", "outcome": OutcomeSuccess,
+	}))
+	assert.Equal(t, uint64(1), sampleCount(t, m, "tsundoc_db_query_duration_seconds", map[string]string{
+		"operation": "raw", "outcome": OutcomeError,
+	}))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "db.create", spans[0].Name())
+	assert.Equal(t, "db.query", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+	m := NewMetrics()
+
+	r := chi.NewRouter()
+	r.Use(Middleware(m))
+	r.Get("/books/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Handle("/metrics", m.Handler())
+
+	for _, path := range []string{"/books/1", "/books/2", "/missing"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Requests are grouped by route pattern, not by path
+	assert.Equal(t, uint64(2), sampleCount(t, m, "tsundoc_http_requests_total", map[string]string{
+		"method": "GET", "route": "/books/{id}", "status": "404",
+	}))
+	assert.Equal(t, uint64(1), sampleCount(t, m, "tsundoc_http_request_duration_seconds", map[string]string{
+		"method": "GET", "route": unmatchedRoute,
+	}))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "GET /books/{id}", spans[0].Name())
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `tsundoc_http_requests_total{method="GET",route="/books/{id}",status="404"} 2`)
+}
@@ -0,0 +1,129 @@
+// Package telemetry provides Prometheus metrics and OpenTelemetry tracing for
+// the HTTP server, GraphQL resolvers, database queries, and AI calls.
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tsundoc"
+
+// Outcome label values
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Metrics holds the collectors exported on /metrics
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests      *prometheus.CounterVec
+	httpDuration      *prometheus.HistogramVec
+	resolverDuration  *prometheus.HistogramVec
+	dbQueryDuration   *prometheus.HistogramVec
+	aiRequests        *prometheus.CounterVec
+	aiRequestDuration *prometheus.HistogramVec
+}
+
+// NewMetrics creates the collectors on a dedicated registry together with the
+// standard Go runtime and process collectors
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern, and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		resolverDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_resolver_duration_seconds",
+			Help:      "GraphQL resolver latency by field and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"field", "outcome"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latency by operation, table, and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table", "outcome"}),
+		aiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "ai_requests_total",
+			Help:      "AI provider calls by operation, provider, and outcome.",
+		}, []string{"operation", "provider", "outcome"}),
+		aiRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "ai_request_duration_seconds",
+			Help:      "AI provider latency by operation, provider, and outcome.",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 30, 60},
+		}, []string{"operation", "provider", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.resolverDuration,
+		m.dbQueryDuration,
+		m.aiRequests,
+		m.aiRequestDuration,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry exposes the underlying registry, mainly for tests
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveHTTPRequest records a served request. route is the chi route
+// pattern, never the raw path, to keep the label set bounded.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveResolver records a GraphQL resolver call such as Query.books
+func (m *Metrics) ObserveResolver(field string, err error, duration time.Duration) {
+	m.resolverDuration.WithLabelValues(field, Outcome(err)).Observe(duration.Seconds())
+}
+
+// ObserveDBQuery records a database statement
+func (m *Metrics) ObserveDBQuery(operation, table string, err error, duration time.Duration) {
+	m.dbQueryDuration.WithLabelValues(operation, table, Outcome(err)).Observe(duration.Seconds())
+}
+
+// ObserveAIRequest records a call to an AI provider
+func (m *Metrics) ObserveAIRequest(operation, provider string, err error, duration time.Duration) {
+	outcome := Outcome(err)
+	m.aiRequests.WithLabelValues(operation, provider, outcome).Inc()
+	m.aiRequestDuration.WithLabelValues(operation, provider, outcome).Observe(duration.Seconds())
+}
+
+// Outcome maps an error to the outcome label value
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
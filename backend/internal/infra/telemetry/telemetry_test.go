@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// sampleCount returns how many observations a counter or histogram with the
+// given labels has received
+func sampleCount(t *testing.T, m *Metrics, name string, labels map[string]string) uint64 {
+	families, err := m.Registry().Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if !hasLabels(metric, labels) {
+				continue
+			}
+			if h := metric.GetHistogram(); h != nil {
+				return h.GetSampleCount()
+			}
+			return uint64(metric.GetCounter().GetValue())
+		}
+	}
+	return 0
+}
+
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, pair := range metric.GetLabel() {
+		if want, ok := labels[pair.GetName()]; ok {
+			if pair.GetValue() != want {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(labels)
+}
@@ -0,0 +1,272 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = 10 * time.Second
+	defaultQueueSize   = 256
+	requestTimeout     = 10 * time.Second
+)
+
+// Dispatcher delivers events to webhooks from background workers, retrying
+// failed deliveries with exponential backoff
+type Dispatcher struct {
+	repo        webhook.Repository
+	client      *http.Client
+	queue       chan webhook.Event
+	maxAttempts int
+	backoff     time.Duration
+
+	// mu guards closed so that Publish never sends on the closed queue
+	mu       sync.RWMutex
+	closed   bool
+	stopping chan struct{}
+	// pending tracks workers and in-flight deliveries for Shutdown
+	pending sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher. Call Start to begin delivering events.
+func NewDispatcher(repo webhook.Repository) *Dispatcher {
+	return &Dispatcher{
+		repo:        repo,
+		client:      newClient(publicOnly),
+		queue:       make(chan webhook.Event, defaultQueueSize),
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		stopping:    make(chan struct{}),
+	}
+}
+
+// newClient returns the HTTP client deliveries are sent with. control, when
+// set, vets every address the client connects to. Redirects are not followed,
+// so that a receiver cannot send deliveries elsewhere.
+func newClient(control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout, Control: control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would be the only address vetted
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicOnly refuses connections to addresses webhooks may not reach. It
+// runs after the host name is resolved, so names pointing at the server's own
+// network are refused too.
+func publicOnly(network, address string, c syscall.RawConn) error {
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook address %s: %w", address, err)
+	}
+	if !webhook.PublicAddress(addr.Addr()) {
+		return fmt.Errorf("webhook address %s is not public", addr.Addr())
+	}
+	return nil
+}
+
+// Start runs the workers until ctx is cancelled or Shutdown has drained the
+// queue
+func (d *Dispatcher) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		d.pending.Add(1)
+		go d.run(ctx)
+	}
+}
+
+// Publish queues an event. Events are dropped when the queue is full so that
+// slow receivers never hold up the request that triggered them, and after
+// Shutdown has been called.
+func (d *Dispatcher) Publish(ctx context.Context, event webhook.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		log.Warn().Str("event", string(event.Type)).Str("event_id", event.ID).Msg("Webhook dispatcher is shutting down, dropping event")
+		return
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		log.Warn().Str("event", string(event.Type)).Str("event_id", event.ID).Msg("Webhook queue is full, dropping event")
+	}
+}
+
+// Shutdown stops accepting events and waits until the queued events have
+// been dispatched and in-flight requests have finished. Deliveries waiting
+// to retry are abandoned. If ctx expires first its error is returned; cancel
+// the context passed to Start to abort the remaining requests.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+		close(d.stopping)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deliver sends an event to a webhook once and records the attempt
+func (d *Dispatcher) Deliver(ctx context.Context, w *webhook.Webhook, event webhook.Event) (*webhook.Delivery, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	return d.attempt(ctx, w, event, body, 1)
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	defer d.pending.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-d.queue:
+			if !ok {
+				return
+			}
+			d.dispatch(ctx, event)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event webhook.Event) {
+	webhooks, err := d.repo.FindByUserID(ctx, event.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("event_id", event.ID).Msg("Failed to find webhooks for event")
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Str("event_id", event.ID).Msg("Failed to encode webhook event")
+		return
+	}
+
+	for _, w := range webhooks {
+		if w.Subscribes(event.Type) {
+			d.pending.Add(1)
+			go func() {
+				defer d.pending.Done()
+				d.deliverWithRetry(ctx, w, event, body)
+			}()
+		}
+	}
+}
+
+func (d *Dispatcher) deliverWithRetry(ctx context.Context, w *webhook.Webhook, event webhook.Event, body []byte) {
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		delivery, err := d.attempt(ctx, w, event, body, attempt)
+		if err != nil {
+			log.Error().Err(err).Str("webhook_id", w.ID).Msg("Failed to record webhook delivery")
+		}
+		if delivery != nil && delivery.Succeeded {
+			return
+		}
+		if attempt == d.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.stopping:
+			log.Warn().Str("webhook_id", w.ID).Str("event_id", event.ID).Int("attempt", attempt).Msg("Shutting down, abandoning webhook retries")
+			return
+		case <-time.After(d.backoff << (attempt - 1)):
+		}
+	}
+
+	log.Warn().Str("webhook_id", w.ID).Str("event_id", event.ID).Msg("Giving up on webhook delivery")
+}
+
+// attempt performs a single HTTP request and saves the outcome to the delivery log
+func (d *Dispatcher) attempt(ctx context.Context, w *webhook.Webhook, event webhook.Event, body []byte, attempt int) (*webhook.Delivery, error) {
+	delivery := &webhook.Delivery{
+		ID:        uuid.New().String(),
+		WebhookID: w.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   string(body),
+		Attempt:   attempt,
+		CreatedAt: time.Now(),
+	}
+
+	statusCode, err := d.send(ctx, w, event, body)
+	delivery.StatusCode = statusCode
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		delivery.Succeeded = true
+	}
+
+	if err := d.repo.SaveDelivery(ctx, delivery); err != nil {
+		return delivery, err
+	}
+
+	return delivery, nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, w *webhook.Webhook, event webhook.Event, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tsundoc-webhooks")
+	req.Header.Set("X-Tsundoc-Event", string(event.Type))
+	req.Header.Set("X-Tsundoc-Delivery", event.ID)
+	req.Header.Set(webhook.SignatureHeader, w.Sign(body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
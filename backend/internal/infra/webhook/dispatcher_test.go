@@ -0,0 +1,280 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+)
+
+// memoryRepository implements webhook.Repository for testing
+type memoryRepository struct {
+	mu         sync.Mutex
+	webhooks   []*webhook.Webhook
+	deliveries []*webhook.Delivery
+}
+
+func (r *memoryRepository) Save(ctx context.Context, w *webhook.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks = append(r.webhooks, w)
+	return nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id, userID string) (*webhook.Webhook, error) {
+	return nil, nil
+}
+
+func (r *memoryRepository) FindByUserID(ctx context.Context, userID string) ([]*webhook.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*webhook.Webhook
+	for _, w := range r.webhooks {
+		if w.UserID == userID {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, id, userID string) error {
+	return nil
+}
+
+func (r *memoryRepository) SaveDelivery(ctx context.Context, d *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func (r *memoryRepository) FindDeliveries(ctx context.Context, webhookID, userID string, limit int) ([]*webhook.Delivery, error) {
+	return nil, nil
+}
+
+func (r *memoryRepository) recorded() []*webhook.Delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*webhook.Delivery(nil), r.deliveries...)
+}
+
+// newTestDispatcher returns a dispatcher that may deliver to the local test
+// servers
+func newTestDispatcher(repo webhook.Repository) *Dispatcher {
+	d := NewDispatcher(repo)
+	d.client = newClient(nil)
+	return d
+}
+
+func TestDispatcher_RetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	var signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		signature = r.Header.Get(webhook.SignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &memoryRepository{}
+	hook, err := webhook.NewWebhook("user-123", server.URL, "s3cret", []webhook.EventType{webhook.EventBookCreated})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), hook))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := newTestDispatcher(repo)
+	d.backoff = time.Millisecond
+	d.Start(ctx, 1)
+
+	// Events the webhook does not subscribe to are not delivered
+	d.Publish(ctx, webhook.NewEvent(webhook.EventBookDeleted, "user-123", nil))
+	d.Publish(ctx, webhook.NewEvent(webhook.EventBookCreated, "user-123", map[string]string{"id": "book-123"}))
+
+	require.Eventually(t, func() bool {
+		return len(repo.recorded()) == 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	deliveries := repo.recorded()
+	assert.False(t, deliveries[0].Succeeded)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].StatusCode)
+	assert.True(t, deliveries[1].Succeeded)
+	assert.Equal(t, 2, deliveries[1].Attempt)
+	assert.Equal(t, webhook.EventBookCreated, deliveries[1].EventType)
+	assert.Equal(t, hook.Sign(body), signature)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := &memoryRepository{}
+	hook, err := webhook.NewWebhook("user-123", server.URL, "", []webhook.EventType{webhook.EventBookUpdated})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), hook))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := newTestDispatcher(repo)
+	d.backoff = time.Millisecond
+	d.maxAttempts = 3
+	d.Start(ctx, 1)
+
+	d.Publish(ctx, webhook.NewEvent(webhook.EventBookUpdated, "user-123", nil))
+
+	require.Eventually(t, func() bool {
+		return len(repo.recorded()) == 3
+	}, 2*time.Second, 5*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, repo.recorded(), 3)
+}
+
+func TestDispatcher_ShutdownDrainsDeliveries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := &memoryRepository{}
+	hook, err := webhook.NewWebhook("user-123", server.URL, "", []webhook.EventType{webhook.EventBookCreated})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), hook))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := newTestDispatcher(repo)
+	d.backoff = time.Hour
+	d.Start(ctx, 1)
+
+	d.Publish(ctx, webhook.NewEvent(webhook.EventBookCreated, "user-123", nil))
+	d.Publish(ctx, webhook.NewEvent(webhook.EventBookCreated, "user-123", nil))
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelShutdown()
+	require.NoError(t, d.Shutdown(shutdownCtx))
+
+	// Both queued events got their in-flight attempt; the failed one is not
+	// retried after the hour-long backoff
+	deliveries := repo.recorded()
+	require.Len(t, deliveries, 2)
+
+	// Events published after shutdown are dropped
+	d.Publish(ctx, webhook.NewEvent(webhook.EventBookCreated, "user-123", nil))
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, repo.recorded(), 2)
+}
+
+func TestDispatcher_ShutdownHonoursDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	repo := &memoryRepository{}
+	hook, err := webhook.NewWebhook("user-123", server.URL, "", []webhook.EventType{webhook.EventBookCreated})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), hook))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := newTestDispatcher(repo)
+	d.Start(ctx, 1)
+	d.Publish(ctx, webhook.NewEvent(webhook.EventBookCreated, "user-123", nil))
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShutdown()
+	assert.ErrorIs(t, d.Shutdown(shutdownCtx), context.DeadlineExceeded)
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, string(webhook.EventTest), r.Header.Get("X-Tsundoc-Event"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &memoryRepository{}
+	hook, err := webhook.NewWebhook("user-123", server.URL, "", nil)
+	require.NoError(t, err)
+
+	delivery, err := newTestDispatcher(repo).Deliver(context.Background(), hook, webhook.NewEvent(webhook.EventTest, "user-123", nil))
+
+	require.NoError(t, err)
+	assert.True(t, delivery.Succeeded)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	assert.Len(t, repo.recorded(), 1)
+}
+
+func TestDispatcher_RefusesLocalAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &memoryRepository{}
+	hook, err := webhook.NewWebhook("user-123", server.URL, "", nil)
+	require.NoError(t, err)
+
+	delivery, err := NewDispatcher(repo).Deliver(context.Background(), hook, webhook.NewEvent(webhook.EventTest, "user-123", nil))
+
+	require.NoError(t, err)
+	assert.False(t, delivery.Succeeded)
+	assert.Contains(t, delivery.Error, "is not public")
+	assert.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	var redirected int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&redirected, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	repo := &memoryRepository{}
+	hook, err := webhook.NewWebhook("user-123", server.URL, "", nil)
+	require.NoError(t, err)
+
+	delivery, err := newTestDispatcher(repo).Deliver(context.Background(), hook, webhook.NewEvent(webhook.EventTest, "user-123", nil))
+
+	require.NoError(t, err)
+	assert.False(t, delivery.Succeeded)
+	assert.Equal(t, http.StatusTemporaryRedirect, delivery.StatusCode)
+	assert.Zero(t, atomic.LoadInt32(&redirected))
+}
@@ -0,0 +1,125 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	errPersistedQueryNotFound = "PERSISTED_QUERY_NOT_FOUND"
+	errQueryNotAllowed        = "QUERY_NOT_ALLOWED"
+)
+
+// Allowlist is a gqlgen extension that only executes registered documents.
+// Clients either send the SHA-256 hash of a document in the persistedQuery
+// extension, as with automatic persisted queries, or the full text of a
+// registered document.
+type Allowlist struct {
+	byHash  map[string]string
+	allowed map[string]bool
+}
+
+var (
+	_ gqlgen.HandlerExtension          = (*Allowlist)(nil)
+	_ gqlgen.OperationParameterMutator = (*Allowlist)(nil)
+)
+
+// NewAllowlist builds an allowlist from documents keyed by the hex SHA-256
+// hash of their text. A "sha256:" prefix on the keys is accepted.
+func NewAllowlist(documents map[string]string) (*Allowlist, error) {
+	a := &Allowlist{
+		byHash:  make(map[string]string, len(documents)),
+		allowed: make(map[string]bool, len(documents)),
+	}
+	for key, document := range documents {
+		hash := strings.TrimPrefix(key, "sha256:")
+		if hash != sha256Hex(document) {
+			return nil, fmt.Errorf("document %s does not match its hash", key)
+		}
+		a.byHash[hash] = document
+		a.allowed[normalizeDocument(document)] = true
+	}
+	return a, nil
+}
+
+// LoadAllowlist reads the persisted-documents.json manifest written by the
+// graphql-codegen client preset
+func LoadAllowlist(path string) (*Allowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowlist: %w", err)
+	}
+
+	var documents map[string]string
+	if err := json.Unmarshal(data, &documents); err != nil {
+		return nil, fmt.Errorf("failed to parse allowlist %s: %w", path, err)
+	}
+	return NewAllowlist(documents)
+}
+
+// Len returns the number of registered documents
+func (a *Allowlist) Len() int {
+	return len(a.byHash)
+}
+
+func (a *Allowlist) ExtensionName() string {
+	return "Allowlist"
+}
+
+func (a *Allowlist) Validate(gqlgen.ExecutableSchema) error {
+	return nil
+}
+
+func (a *Allowlist) MutateOperationParameters(ctx context.Context, params *gqlgen.RawParams) *gqlerror.Error {
+	if params.Query != "" {
+		if !a.allowed[normalizeDocument(params.Query)] {
+			err := gqlerror.Errorf("query is not in the allowlist")
+			errcode.Set(err, errQueryNotAllowed)
+			return err
+		}
+		return nil
+	}
+
+	hash := persistedQueryHash(params.Extensions)
+	if hash == "" {
+		return nil
+	}
+	document, ok := a.byHash[hash]
+	if !ok {
+		err := gqlerror.Errorf("PersistedQueryNotFound")
+		errcode.Set(err, errPersistedQueryNotFound)
+		return err
+	}
+	params.Query = document
+	return nil
+}
+
+// persistedQueryHash extracts extensions.persistedQuery.sha256Hash
+func persistedQueryHash(extensions map[string]interface{}) string {
+	pq, ok := extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	hash, _ := pq["sha256Hash"].(string)
+	return strings.TrimPrefix(hash, "sha256:")
+}
+
+// normalizeDocument collapses whitespace so that documents printed with
+// different indentation compare equal
+func normalizeDocument(document string) string {
+	return strings.Join(strings.Fields(document), " ")
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/rs/zerolog/log"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// Error codes set in extensions.code
+const (
+	CodeNotFound      = "NOT_FOUND"
+	CodeValidation    = "BAD_USER_INPUT"
+	CodeUnauthorized  = "UNAUTHORIZED"
+	CodeConflict      = "CONFLICT"
+	CodeQuotaExceeded = "QUOTA_EXCEEDED"
+	CodeAIUnavailable = "AI_UNAVAILABLE"
+	CodeInternal      = "INTERNAL"
+)
+
+var errorCodes = []struct {
+	kind error
+	code string
+}{
+	{errs.ErrNotFound, CodeNotFound},
+	{errs.ErrValidation, CodeValidation},
+	{errs.ErrUnauthorized, CodeUnauthorized},
+	{errs.ErrConflict, CodeConflict},
+	{errs.ErrQuotaExceeded, CodeQuotaExceeded},
+	{errs.ErrAIUnavailable, CodeAIUnavailable},
+}
+
+// NewErrorPresenter sets extensions.code on errors returned by resolvers.
+// Classified domain errors show their own message, validation errors name
+// the invalid field in extensions.field, and version conflicts carry the
+// stored book in extensions.current. Unclassified errors are logged
+// and, unless showInternal is set, replaced by a generic message so that
+// database and provider details never reach clients.
+func NewErrorPresenter(showInternal bool) gqlgen.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		gqlErr := gqlgen.DefaultErrorPresenter(ctx, err)
+
+		// Errors raised by gqlgen itself, such as parse and validation
+		// failures, already describe the request
+		if gqlErr.Err == nil {
+			return gqlErr
+		}
+		var inner *gqlerror.Error
+		if errors.As(gqlErr.Err, &inner) && inner.Extensions["code"] != nil {
+			return gqlErr
+		}
+
+		if e, ok := errs.As(gqlErr.Err); ok {
+			for _, c := range errorCodes {
+				if errors.Is(e.Kind, c.kind) {
+					gqlErr.Message = e.Message
+					errcode.Set(gqlErr, c.code)
+					if e.Field != "" {
+						gqlErr.Extensions["field"] = e.Field
+					}
+					var conflict *book.ConflictError
+					if errors.As(gqlErr.Err, &conflict) {
+						gqlErr.Extensions["current"] = bookExtension(conflict.Current)
+					}
+					if e.Err != nil {
+						log.Warn().Err(gqlErr.Err).Str("path", gqlErr.Path.String()).Msg("GraphQL error")
+					}
+					return gqlErr
+				}
+			}
+		}
+
+		log.Error().Err(gqlErr.Err).Str("path", gqlErr.Path.String()).Msg("GraphQL internal error")
+		if !showInternal {
+			gqlErr.Message = "internal server error"
+		}
+		errcode.Set(gqlErr, CodeInternal)
+		return gqlErr
+	}
+}
+
+// bookExtension shapes a book like the Book type of the schema
+func bookExtension(b *book.Book) map[string]interface{} {
+	tags := b.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	var workspaceID interface{}
+	if b.WorkspaceID != "" {
+		workspaceID = b.WorkspaceID
+	}
+
+	return map[string]interface{}{
+		"id":          b.ID,
+		"title":       b.Title,
+		"tags":        tags,
+		"content":     b.Content,
+		"workspaceId": workspaceID,
+		"version":     b.Version,
+		"createdAt":   b.CreatedAt.Format(time.RFC3339Nano),
+		"updatedAt":   b.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
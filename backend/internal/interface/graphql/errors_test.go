@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+func TestErrorPresenter_DomainErrors(t *testing.T) {
+	present := NewErrorPresenter(false)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		err     error
+		code    string
+		message string
+	}{
+		{"not found", fmt.Errorf("failed to get book: %w", book.ErrNotFound), CodeNotFound, "book not found"},
+		{"unauthorized", errs.Unauthorized("only workspace owners can manage members"), CodeUnauthorized, "only workspace owners can manage members"},
+		{"conflict", errs.Conflict("already a member of this workspace"), CodeConflict, "already a member of this workspace"},
+		{"quota exceeded", errs.QuotaExceeded("failed to generate title", errors.New("429 rate limit")), CodeQuotaExceeded, "failed to generate title"},
+		{"AI unavailable", errs.AIUnavailable("failed to generate title", errors.New("dial tcp: i/o timeout")), CodeAIUnavailable, "failed to generate title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gqlErr := present(ctx, tt.err)
+			assert.Equal(t, tt.code, gqlErr.Extensions["code"])
+			assert.Equal(t, tt.message, gqlErr.Message)
+		})
+	}
+}
+
+func TestErrorPresenter_ValidationField(t *testing.T) {
+	gqlErr := NewErrorPresenter(false)(context.Background(), errs.Validation("content", "content is required"))
+
+	assert.Equal(t, CodeValidation, gqlErr.Extensions["code"])
+	assert.Equal(t, "content", gqlErr.Extensions["field"])
+	assert.Equal(t, "content is required", gqlErr.Message)
+}
+
+func TestErrorPresenter_Conflict(t *testing.T) {
+	current := &book.Book{ID: "book-123", Title: "Theirs", Content: "Their edit", Version: 4}
+	err := fmt.Errorf("failed to update book: %w", &book.ConflictError{Current: current})
+
+	gqlErr := NewErrorPresenter(false)(context.Background(), err)
+
+	assert.Equal(t, CodeConflict, gqlErr.Extensions["code"])
+	assert.Equal(t, "book was modified by someone else", gqlErr.Message)
+	stored, ok := gqlErr.Extensions["current"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "Their edit", stored["content"])
+	assert.Equal(t, 4, stored["version"])
+}
+
+func TestErrorPresenter_InternalErrors(t *testing.T) {
+	err := fmt.Errorf("failed to get books: %w", errors.New("pq: password authentication failed"))
+
+	gqlErr := NewErrorPresenter(false)(context.Background(), err)
+	assert.Equal(t, CodeInternal, gqlErr.Extensions["code"])
+	assert.Equal(t, "internal server error", gqlErr.Message)
+
+	// Development keeps the details
+	gqlErr = NewErrorPresenter(true)(context.Background(), err)
+	assert.Equal(t, CodeInternal, gqlErr.Extensions["code"])
+	assert.Contains(t, gqlErr.Message, "password authentication failed")
+}
+
+func TestErrorPresenter_KeepsRequestErrors(t *testing.T) {
+	validation := gqlerror.Errorf("Cannot query field \"books\" on type \"Query\".")
+	validation.Extensions = map[string]interface{}{"code": "GRAPHQL_VALIDATION_FAILED"}
+
+	gqlErr := NewErrorPresenter(false)(context.Background(), validation)
+	assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", gqlErr.Extensions["code"])
+	assert.Equal(t, validation.Message, gqlErr.Message)
+}
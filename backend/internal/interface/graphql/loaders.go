@@ -0,0 +1,168 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/graph-gophers/dataloader/v7"
+
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/link"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
+	"github.com/motoya-k/tsundoc/internal/middleware"
+)
+
+// loaderWait is how long a loader collects keys before running its batch
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// loaderUserID returns the ID of the authenticated user. Loaders run below
+// resolvers that already required one, and the use cases reject an empty ID.
+func loaderUserID(ctx context.Context) string {
+	userID, _ := middleware.GetUserID(ctx)
+	return userID
+}
+
+// Loaders batch and cache the lookups made while resolving one operation, so
+// that resolving a field for every item in a list costs one query rather than
+// one per item. Results are cached for the acting user, so loaders must never
+// be shared between operations.
+type Loaders struct {
+	Books            *dataloader.Loader[string, *book.Book]
+	WorkspaceMembers *dataloader.Loader[string, []*workspace.Member]
+	Annotations      *dataloader.Loader[string, []*annotation.Annotation]
+	OutgoingLinks    *dataloader.Loader[string, []*link.Link]
+	Backlinks        *dataloader.Loader[string, []*link.Link]
+	ReadingStates    *dataloader.Loader[string, *reading.State]
+}
+
+func NewLoaders(r *Resolver) *Loaders {
+	return &Loaders{
+		Books: dataloader.NewBatchedLoader(r.batchBooks,
+			dataloader.WithWait[string, *book.Book](loaderWait)),
+		WorkspaceMembers: dataloader.NewBatchedLoader(r.batchWorkspaceMembers,
+			dataloader.WithWait[string, []*workspace.Member](loaderWait)),
+		Annotations: dataloader.NewBatchedLoader(r.batchAnnotations,
+			dataloader.WithWait[string, []*annotation.Annotation](loaderWait)),
+		OutgoingLinks: dataloader.NewBatchedLoader(r.batchOutgoingLinks,
+			dataloader.WithWait[string, []*link.Link](loaderWait)),
+		Backlinks: dataloader.NewBatchedLoader(r.batchBacklinks,
+			dataloader.WithWait[string, []*link.Link](loaderWait)),
+		ReadingStates: dataloader.NewBatchedLoader(r.batchReadingStates,
+			dataloader.WithWait[string, *reading.State](loaderWait)),
+	}
+}
+
+// WithLoaders gives each operation its own loaders. It is meant to be
+// installed with AroundOperations.
+func (r *Resolver) WithLoaders(ctx context.Context, next gqlgen.OperationHandler) gqlgen.ResponseHandler {
+	return next(context.WithValue(ctx, loadersKey{}, NewLoaders(r)))
+}
+
+// loaders returns the operation's loaders, or fresh ones when WithLoaders is
+// not installed
+func (r *Resolver) loaders(ctx context.Context) *Loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return l
+	}
+	return NewLoaders(r)
+}
+
+func (r *Resolver) batchBooks(ctx context.Context, ids []string) []*dataloader.Result[*book.Book] {
+	books, err := r.BookUseCase.GetBooks(ctx, ids, loaderUserID(ctx))
+
+	results := make([]*dataloader.Result[*book.Book], len(ids))
+	for i := range ids {
+		switch {
+		case err != nil:
+			results[i] = &dataloader.Result[*book.Book]{Error: err}
+		case books[i] == nil:
+			results[i] = &dataloader.Result[*book.Book]{Error: book.ErrNotFound}
+		default:
+			results[i] = &dataloader.Result[*book.Book]{Data: books[i]}
+		}
+	}
+	return results
+}
+
+func (r *Resolver) batchWorkspaceMembers(ctx context.Context, workspaceIDs []string) []*dataloader.Result[[]*workspace.Member] {
+	members, err := r.WorkspaceUseCase.GetMembersOfWorkspaces(ctx, workspaceIDs, loaderUserID(ctx))
+
+	results := make([]*dataloader.Result[[]*workspace.Member], len(workspaceIDs))
+	for i := range workspaceIDs {
+		switch {
+		case err != nil:
+			results[i] = &dataloader.Result[[]*workspace.Member]{Error: err}
+		case members[i] == nil:
+			results[i] = &dataloader.Result[[]*workspace.Member]{Error: workspace.ErrMemberNotFound}
+		default:
+			results[i] = &dataloader.Result[[]*workspace.Member]{Data: members[i]}
+		}
+	}
+	return results
+}
+
+// batchAnnotations loads the current user's annotations by book ID. Books
+// without annotations get an empty list.
+func (r *Resolver) batchAnnotations(ctx context.Context, bookIDs []string) []*dataloader.Result[[]*annotation.Annotation] {
+	annotations, err := r.AnnotationUseCase.GetAnnotationsOfBooks(ctx, bookIDs, loaderUserID(ctx))
+
+	results := make([]*dataloader.Result[[]*annotation.Annotation], len(bookIDs))
+	for i, id := range bookIDs {
+		switch {
+		case err != nil:
+			results[i] = &dataloader.Result[[]*annotation.Annotation]{Error: err}
+		case annotations[id] == nil:
+			results[i] = &dataloader.Result[[]*annotation.Annotation]{Data: []*annotation.Annotation{}}
+		default:
+			results[i] = &dataloader.Result[[]*annotation.Annotation]{Data: annotations[id]}
+		}
+	}
+	return results
+}
+
+// batchOutgoingLinks loads the links written in books by book ID
+func (r *Resolver) batchOutgoingLinks(ctx context.Context, bookIDs []string) []*dataloader.Result[[]*link.Link] {
+	links, err := r.LinkUseCase.GetOutgoingLinksOfBooks(ctx, bookIDs, loaderUserID(ctx))
+	return linkResults(bookIDs, links, err)
+}
+
+// batchBacklinks loads the links pointing at books by book ID
+func (r *Resolver) batchBacklinks(ctx context.Context, bookIDs []string) []*dataloader.Result[[]*link.Link] {
+	links, err := r.LinkUseCase.GetBacklinksOfBooks(ctx, bookIDs, loaderUserID(ctx))
+	return linkResults(bookIDs, links, err)
+}
+
+func linkResults(bookIDs []string, links map[string][]*link.Link, err error) []*dataloader.Result[[]*link.Link] {
+	results := make([]*dataloader.Result[[]*link.Link], len(bookIDs))
+	for i, id := range bookIDs {
+		switch {
+		case err != nil:
+			results[i] = &dataloader.Result[[]*link.Link]{Error: err}
+		case links[id] == nil:
+			results[i] = &dataloader.Result[[]*link.Link]{Data: []*link.Link{}}
+		default:
+			results[i] = &dataloader.Result[[]*link.Link]{Data: links[id]}
+		}
+	}
+	return results
+}
+
+// batchReadingStates loads the current user's reading states by book ID
+func (r *Resolver) batchReadingStates(ctx context.Context, bookIDs []string) []*dataloader.Result[*reading.State] {
+	states, err := r.ReadingUseCase.GetStatesOfBooks(ctx, bookIDs, loaderUserID(ctx))
+
+	results := make([]*dataloader.Result[*reading.State], len(bookIDs))
+	for i, id := range bookIDs {
+		if err != nil {
+			results[i] = &dataloader.Result[*reading.State]{Error: err}
+		} else {
+			results[i] = &dataloader.Result[*reading.State]{Data: states[id]}
+		}
+	}
+	return results
+}
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/transaction"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
+	"github.com/motoya-k/tsundoc/internal/middleware"
+	bookUseCase "github.com/motoya-k/tsundoc/internal/usecase/book"
+	workspaceUseCase "github.com/motoya-k/tsundoc/internal/usecase/workspace"
+)
+
+// stubBookRepository serves FindByIDs from a map and counts the batches.
+// Other methods are left to the embedded nil interface and must not be called.
+type stubBookRepository struct {
+	book.Repository
+	books   map[string]*book.Book
+	batches [][]string
+}
+
+func (r *stubBookRepository) FindByIDs(ctx context.Context, ids []string, userID string) ([]*book.Book, error) {
+	r.batches = append(r.batches, ids)
+	result := make([]*book.Book, len(ids))
+	for i, id := range ids {
+		result[i] = r.books[id]
+	}
+	return result, nil
+}
+
+type stubWorkspaceRepository struct {
+	workspace.Repository
+	members map[string][]*workspace.Member
+	batches [][]string
+}
+
+func (r *stubWorkspaceRepository) FindMembersByWorkspaceIDs(ctx context.Context, workspaceIDs []string) (map[string][]*workspace.Member, error) {
+	r.batches = append(r.batches, workspaceIDs)
+	return r.members, nil
+}
+
+func TestLoaders_Books(t *testing.T) {
+	repo := &stubBookRepository{books: map[string]*book.Book{
+		"book-1": {ID: "book-1", Title: "One"},
+		"book-2": {ID: "book-2", Title: "Two"},
+	}}
+	loaders := NewLoaders(&Resolver{BookUseCase: bookUseCase.NewUseCase(repo, nil, transaction.None, nil, nil, nil, nil)})
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, "user-123")
+
+	thunks := []func() (*book.Book, error){
+		loaders.Books.Load(ctx, "book-2"),
+		loaders.Books.Load(ctx, "missing"),
+		loaders.Books.Load(ctx, "book-1"),
+	}
+
+	b, err := thunks[0]()
+	require.NoError(t, err)
+	assert.Equal(t, "Two", b.Title)
+
+	_, err = thunks[1]()
+	assert.EqualError(t, err, "book not found")
+
+	b, err = thunks[2]()
+	require.NoError(t, err)
+	assert.Equal(t, "One", b.Title)
+
+	require.Len(t, repo.batches, 1)
+	assert.ElementsMatch(t, []string{"book-1", "book-2", "missing"}, repo.batches[0])
+
+	// Loaded books are cached for the rest of the operation
+	_, err = loaders.Books.Load(ctx, "book-1")()
+	require.NoError(t, err)
+	assert.Len(t, repo.batches, 1)
+}
+
+func TestLoaders_WorkspaceMembers(t *testing.T) {
+	repo := &stubWorkspaceRepository{members: map[string][]*workspace.Member{
+		"ws-1": {{WorkspaceID: "ws-1", UserID: "user-123", Role: workspace.RoleOwner}},
+		"ws-2": {{WorkspaceID: "ws-2", UserID: "other-user", Role: workspace.RoleOwner}},
+	}}
+	loaders := NewLoaders(&Resolver{WorkspaceUseCase: workspaceUseCase.NewUseCase(repo, transaction.None)})
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, "user-123")
+
+	own := loaders.WorkspaceMembers.Load(ctx, "ws-1")
+	other := loaders.WorkspaceMembers.Load(ctx, "ws-2")
+
+	members, err := own()
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+
+	// Members of a workspace the user does not belong to are not visible
+	_, err = other()
+	assert.EqualError(t, err, "workspace member not found")
+
+	assert.Len(t, repo.batches, 1)
+}
+
+func TestLoaders_RequireUser(t *testing.T) {
+	repo := &stubBookRepository{books: map[string]*book.Book{"book-1": {ID: "book-1"}}}
+	loaders := NewLoaders(&Resolver{BookUseCase: bookUseCase.NewUseCase(repo, nil, transaction.None, nil, nil, nil, nil)})
+
+	_, err := loaders.Books.Load(context.Background(), "book-1")()
+	assert.ErrorIs(t, err, errs.ErrUnauthorized)
+	assert.Empty(t, repo.batches)
+}
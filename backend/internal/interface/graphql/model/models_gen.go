@@ -2,8 +2,549 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+)
+
+type CreatedPersonalAccessToken struct {
+	// The plaintext token. It is only returned once, when the token is created.
+	Token               string             `json:"token"`
+	PersonalAccessToken *accesstoken.Token `json:"personalAccessToken"`
+}
+
 type Mutation struct {
 }
 
 type Query struct {
 }
+
+type AnnotationColor string
+
+const (
+	AnnotationColorYellow AnnotationColor = "YELLOW"
+	AnnotationColorGreen  AnnotationColor = "GREEN"
+	AnnotationColorBlue   AnnotationColor = "BLUE"
+	AnnotationColorPink   AnnotationColor = "PINK"
+	AnnotationColorPurple AnnotationColor = "PURPLE"
+)
+
+var AllAnnotationColor = []AnnotationColor{
+	AnnotationColorYellow,
+	AnnotationColorGreen,
+	AnnotationColorBlue,
+	AnnotationColorPink,
+	AnnotationColorPurple,
+}
+
+func (e AnnotationColor) IsValid() bool {
+	switch e {
+	case AnnotationColorYellow, AnnotationColorGreen, AnnotationColorBlue, AnnotationColorPink, AnnotationColorPurple:
+		return true
+	}
+	return false
+}
+
+func (e AnnotationColor) String() string {
+	return string(e)
+}
+
+func (e *AnnotationColor) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AnnotationColor(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AnnotationColor", str)
+	}
+	return nil
+}
+
+func (e AnnotationColor) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AnnotationColor) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AnnotationColor) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type BacklogSort string
+
+const (
+	// Books that have waited longest first, quickest reads first among books added the same day
+	BacklogSortOldest BacklogSort = "OLDEST"
+	// Quickest reads first, oldest first among books of the same length
+	BacklogSortShortest BacklogSort = "SHORTEST"
+)
+
+var AllBacklogSort = []BacklogSort{
+	BacklogSortOldest,
+	BacklogSortShortest,
+}
+
+func (e BacklogSort) IsValid() bool {
+	switch e {
+	case BacklogSortOldest, BacklogSortShortest:
+		return true
+	}
+	return false
+}
+
+func (e BacklogSort) String() string {
+	return string(e)
+}
+
+func (e *BacklogSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BacklogSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BacklogSort", str)
+	}
+	return nil
+}
+
+func (e BacklogSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BacklogSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BacklogSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DigestFrequency string
+
+const (
+	// Every morning
+	DigestFrequencyDaily DigestFrequency = "DAILY"
+	// Monday mornings
+	DigestFrequencyWeekly DigestFrequency = "WEEKLY"
+)
+
+var AllDigestFrequency = []DigestFrequency{
+	DigestFrequencyDaily,
+	DigestFrequencyWeekly,
+}
+
+func (e DigestFrequency) IsValid() bool {
+	switch e {
+	case DigestFrequencyDaily, DigestFrequencyWeekly:
+		return true
+	}
+	return false
+}
+
+func (e DigestFrequency) String() string {
+	return string(e)
+}
+
+func (e *DigestFrequency) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DigestFrequency(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DigestFrequency", str)
+	}
+	return nil
+}
+
+func (e DigestFrequency) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DigestFrequency) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DigestFrequency) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReadingStatus string
+
+const (
+	ReadingStatusUnread    ReadingStatus = "UNREAD"
+	ReadingStatusReading   ReadingStatus = "READING"
+	ReadingStatusRead      ReadingStatus = "READ"
+	ReadingStatusAbandoned ReadingStatus = "ABANDONED"
+)
+
+var AllReadingStatus = []ReadingStatus{
+	ReadingStatusUnread,
+	ReadingStatusReading,
+	ReadingStatusRead,
+	ReadingStatusAbandoned,
+}
+
+func (e ReadingStatus) IsValid() bool {
+	switch e {
+	case ReadingStatusUnread, ReadingStatusReading, ReadingStatusRead, ReadingStatusAbandoned:
+		return true
+	}
+	return false
+}
+
+func (e ReadingStatus) String() string {
+	return string(e)
+}
+
+func (e *ReadingStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReadingStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReadingStatus", str)
+	}
+	return nil
+}
+
+func (e ReadingStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReadingStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReadingStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type StatsInterval string
+
+const (
+	StatsIntervalDay StatsInterval = "DAY"
+	// Weeks start on Monday
+	StatsIntervalWeek StatsInterval = "WEEK"
+)
+
+var AllStatsInterval = []StatsInterval{
+	StatsIntervalDay,
+	StatsIntervalWeek,
+}
+
+func (e StatsInterval) IsValid() bool {
+	switch e {
+	case StatsIntervalDay, StatsIntervalWeek:
+		return true
+	}
+	return false
+}
+
+func (e StatsInterval) String() string {
+	return string(e)
+}
+
+func (e *StatsInterval) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StatsInterval(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StatsInterval", str)
+	}
+	return nil
+}
+
+func (e StatsInterval) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *StatsInterval) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e StatsInterval) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type StatsRange string
+
+const (
+	// The last 7 days, by day
+	StatsRangeWeek StatsRange = "WEEK"
+	// The last 30 days, by day
+	StatsRangeMonth StatsRange = "MONTH"
+	// The last 13 weeks, by week
+	StatsRangeQuarter StatsRange = "QUARTER"
+	// The last 52 weeks, by week
+	StatsRangeYear StatsRange = "YEAR"
+)
+
+var AllStatsRange = []StatsRange{
+	StatsRangeWeek,
+	StatsRangeMonth,
+	StatsRangeQuarter,
+	StatsRangeYear,
+}
+
+func (e StatsRange) IsValid() bool {
+	switch e {
+	case StatsRangeWeek, StatsRangeMonth, StatsRangeQuarter, StatsRangeYear:
+		return true
+	}
+	return false
+}
+
+func (e StatsRange) String() string {
+	return string(e)
+}
+
+func (e *StatsRange) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StatsRange(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StatsRange", str)
+	}
+	return nil
+}
+
+func (e StatsRange) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *StatsRange) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e StatsRange) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TokenScope string
+
+const (
+	TokenScopeRead  TokenScope = "READ"
+	TokenScopeWrite TokenScope = "WRITE"
+	TokenScopeAi    TokenScope = "AI"
+)
+
+var AllTokenScope = []TokenScope{
+	TokenScopeRead,
+	TokenScopeWrite,
+	TokenScopeAi,
+}
+
+func (e TokenScope) IsValid() bool {
+	switch e {
+	case TokenScopeRead, TokenScopeWrite, TokenScopeAi:
+		return true
+	}
+	return false
+}
+
+func (e TokenScope) String() string {
+	return string(e)
+}
+
+func (e *TokenScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TokenScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TokenScope", str)
+	}
+	return nil
+}
+
+func (e TokenScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TokenScope) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TokenScope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventBookCreated WebhookEvent = "BOOK_CREATED"
+	WebhookEventBookUpdated WebhookEvent = "BOOK_UPDATED"
+	WebhookEventBookDeleted WebhookEvent = "BOOK_DELETED"
+	WebhookEventBookMerged  WebhookEvent = "BOOK_MERGED"
+)
+
+var AllWebhookEvent = []WebhookEvent{
+	WebhookEventBookCreated,
+	WebhookEventBookUpdated,
+	WebhookEventBookDeleted,
+	WebhookEventBookMerged,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventBookCreated, WebhookEventBookUpdated, WebhookEventBookDeleted, WebhookEventBookMerged:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEvent) String() string {
+	return string(e)
+}
+
+func (e *WebhookEvent) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEvent(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEvent", str)
+	}
+	return nil
+}
+
+func (e WebhookEvent) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookEvent) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookEvent) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "OWNER"
+	WorkspaceRoleEditor WorkspaceRole = "EDITOR"
+	WorkspaceRoleViewer WorkspaceRole = "VIEWER"
+)
+
+var AllWorkspaceRole = []WorkspaceRole{
+	WorkspaceRoleOwner,
+	WorkspaceRoleEditor,
+	WorkspaceRoleViewer,
+}
+
+func (e WorkspaceRole) IsValid() bool {
+	switch e {
+	case WorkspaceRoleOwner, WorkspaceRoleEditor, WorkspaceRoleViewer:
+		return true
+	}
+	return false
+}
+
+func (e WorkspaceRole) String() string {
+	return string(e)
+}
+
+func (e *WorkspaceRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WorkspaceRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WorkspaceRole", str)
+	}
+	return nil
+}
+
+func (e WorkspaceRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WorkspaceRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WorkspaceRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -5,9 +5,129 @@ package graphql
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 import (
+	"strings"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/domain/stats"
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
+	"github.com/motoya-k/tsundoc/internal/interface/graphql/model"
+	accessTokenUseCase "github.com/motoya-k/tsundoc/internal/usecase/accesstoken"
+	annotationUseCase "github.com/motoya-k/tsundoc/internal/usecase/annotation"
 	bookUseCase "github.com/motoya-k/tsundoc/internal/usecase/book"
+	digestUseCase "github.com/motoya-k/tsundoc/internal/usecase/digest"
+	linkUseCase "github.com/motoya-k/tsundoc/internal/usecase/link"
+	readingUseCase "github.com/motoya-k/tsundoc/internal/usecase/reading"
+	searchUseCase "github.com/motoya-k/tsundoc/internal/usecase/search"
+	shareUseCase "github.com/motoya-k/tsundoc/internal/usecase/share"
+	statsUseCase "github.com/motoya-k/tsundoc/internal/usecase/stats"
+	tagUseCase "github.com/motoya-k/tsundoc/internal/usecase/tag"
+	webhookUseCase "github.com/motoya-k/tsundoc/internal/usecase/webhook"
+	workspaceUseCase "github.com/motoya-k/tsundoc/internal/usecase/workspace"
 )
 
 type Resolver struct{
-	BookUseCase *bookUseCase.UseCase
-}
\ No newline at end of file
+	BookUseCase        *bookUseCase.UseCase
+	ShareUseCase       *shareUseCase.UseCase
+	WorkspaceUseCase   *workspaceUseCase.UseCase
+	AccessTokenUseCase *accessTokenUseCase.UseCase
+	WebhookUseCase     *webhookUseCase.UseCase
+	AnnotationUseCase  *annotationUseCase.UseCase
+	LinkUseCase        *linkUseCase.UseCase
+	ReadingUseCase     *readingUseCase.UseCase
+	StatsUseCase       *statsUseCase.UseCase
+	DigestUseCase      *digestUseCase.UseCase
+	TagUseCase         *tagUseCase.UseCase
+	SearchUseCase      *searchUseCase.UseCase
+}
+
+func toDomainRole(role model.WorkspaceRole) workspace.Role {
+	return workspace.Role(strings.ToLower(string(role)))
+}
+
+func toModelRole(role workspace.Role) model.WorkspaceRole {
+	return model.WorkspaceRole(strings.ToUpper(string(role)))
+}
+
+func toDomainColor(color model.AnnotationColor) annotation.Color {
+	return annotation.Color(strings.ToLower(string(color)))
+}
+
+func toModelColor(color annotation.Color) model.AnnotationColor {
+	return model.AnnotationColor(strings.ToUpper(string(color)))
+}
+
+func toDomainStatus(status model.ReadingStatus) reading.Status {
+	return reading.Status(strings.ToLower(string(status)))
+}
+
+func toDomainStatuses(statuses []model.ReadingStatus) []reading.Status {
+	result := make([]reading.Status, len(statuses))
+	for i, status := range statuses {
+		result[i] = toDomainStatus(status)
+	}
+	return result
+}
+
+func toModelStatus(status reading.Status) model.ReadingStatus {
+	return model.ReadingStatus(strings.ToUpper(string(status)))
+}
+
+func toDomainBacklogSort(sort model.BacklogSort) reading.BacklogSort {
+	return reading.BacklogSort(strings.ToLower(string(sort)))
+}
+
+func toDomainRange(rng model.StatsRange) stats.Range {
+	return stats.Range(strings.ToLower(string(rng)))
+}
+
+func toModelRange(rng stats.Range) model.StatsRange {
+	return model.StatsRange(strings.ToUpper(string(rng)))
+}
+
+func toModelInterval(interval stats.Interval) model.StatsInterval {
+	return model.StatsInterval(strings.ToUpper(string(interval)))
+}
+
+func toDomainFrequency(frequency model.DigestFrequency) digest.Frequency {
+	return digest.Frequency(strings.ToLower(string(frequency)))
+}
+
+func toModelFrequency(frequency digest.Frequency) model.DigestFrequency {
+	return model.DigestFrequency(strings.ToUpper(string(frequency)))
+}
+
+func toDomainScopes(scopes []model.TokenScope) []accesstoken.Scope {
+	result := make([]accesstoken.Scope, len(scopes))
+	for i, scope := range scopes {
+		result[i] = accesstoken.Scope(strings.ToLower(string(scope)))
+	}
+	return result
+}
+
+func toModelScopes(scopes []accesstoken.Scope) []model.TokenScope {
+	result := make([]model.TokenScope, len(scopes))
+	for i, scope := range scopes {
+		result[i] = model.TokenScope(strings.ToUpper(string(scope)))
+	}
+	return result
+}
+
+func toDomainEvents(events []model.WebhookEvent) []webhook.EventType {
+	result := make([]webhook.EventType, len(events))
+	for i, e := range events {
+		result[i] = webhook.EventType(strings.ReplaceAll(strings.ToLower(string(e)), "_", "."))
+	}
+	return result
+}
+
+func toModelEvents(events []webhook.EventType) []model.WebhookEvent {
+	result := make([]model.WebhookEvent, len(events))
+	for i, e := range events {
+		result[i] = model.WebhookEvent(strings.ReplaceAll(strings.ToUpper(string(e)), ".", "_"))
+	}
+	return result
+}
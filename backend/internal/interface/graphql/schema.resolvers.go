@@ -6,31 +6,178 @@ package graphql
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
 	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+	"github.com/motoya-k/tsundoc/internal/domain/link"
+	"github.com/motoya-k/tsundoc/internal/domain/markdown"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+	"github.com/motoya-k/tsundoc/internal/domain/share"
+	"github.com/motoya-k/tsundoc/internal/domain/stats"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
 	"github.com/motoya-k/tsundoc/internal/interface/graphql/generated"
+	"github.com/motoya-k/tsundoc/internal/interface/graphql/model"
+	"github.com/motoya-k/tsundoc/internal/middleware"
+	linkUseCase "github.com/motoya-k/tsundoc/internal/usecase/link"
 )
 
+// Color is the resolver for the color field.
+func (r *annotationResolver) Color(ctx context.Context, obj *annotation.Annotation) (model.AnnotationColor, error) {
+	return toModelColor(obj.Color), nil
+}
+
+// WorkspaceID is the resolver for the workspaceId field.
+func (r *bookResolver) WorkspaceID(ctx context.Context, obj *book.Book) (*string, error) {
+	if obj.WorkspaceID == "" {
+		return nil, nil
+	}
+	return &obj.WorkspaceID, nil
+}
+
+// Outline is the resolver for the outline field.
+func (r *bookResolver) Outline(ctx context.Context, obj *book.Book) ([]*markdown.Heading, error) {
+	doc, err := r.BookUseCase.RenderContent(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	outline := make([]*markdown.Heading, len(doc.Outline))
+	for i := range doc.Outline {
+		outline[i] = &doc.Outline[i]
+	}
+	return outline, nil
+}
+
+// RenderedHTML is the resolver for the renderedHtml field.
+func (r *bookResolver) RenderedHTML(ctx context.Context, obj *book.Book) (string, error) {
+	doc, err := r.BookUseCase.RenderContent(ctx, obj)
+	if err != nil {
+		return "", err
+	}
+	return doc.HTML, nil
+}
+
+// PlainText is the resolver for the plainText field.
+func (r *bookResolver) PlainText(ctx context.Context, obj *book.Book) (string, error) {
+	doc, err := r.BookUseCase.RenderContent(ctx, obj)
+	if err != nil {
+		return "", err
+	}
+	return doc.PlainText, nil
+}
+
+// Annotations is the resolver for the annotations field.
+func (r *bookResolver) Annotations(ctx context.Context, obj *book.Book) ([]*annotation.Annotation, error) {
+	return r.loaders(ctx).Annotations.Load(ctx, obj.ID)()
+}
+
+// OutgoingLinks is the resolver for the outgoingLinks field.
+func (r *bookResolver) OutgoingLinks(ctx context.Context, obj *book.Book) ([]*link.Link, error) {
+	return r.loaders(ctx).OutgoingLinks.Load(ctx, obj.ID)()
+}
+
+// Backlinks is the resolver for the backlinks field.
+func (r *bookResolver) Backlinks(ctx context.Context, obj *book.Book) ([]*book.Book, error) {
+	links, err := r.loaders(ctx).Backlinks.Load(ctx, obj.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIDs := make([]string, len(links))
+	for i, l := range links {
+		sourceIDs[i] = l.SourceID
+	}
+	books, loadErrs := r.loaders(ctx).Books.LoadMany(ctx, sourceIDs)()
+
+	// A book linking more than once still counts once
+	backlinks := make([]*book.Book, 0, len(books))
+	seen := make(map[string]bool, len(books))
+	for i, b := range books {
+		if loadErrs != nil && loadErrs[i] != nil {
+			if errors.Is(loadErrs[i], book.ErrNotFound) {
+				continue
+			}
+			return nil, loadErrs[i]
+		}
+		if !seen[b.ID] {
+			seen[b.ID] = true
+			backlinks = append(backlinks, b)
+		}
+	}
+	return backlinks, nil
+}
+
+// ReadingState is the resolver for the readingState field.
+func (r *bookResolver) ReadingState(ctx context.Context, obj *book.Book) (*reading.State, error) {
+	return r.loaders(ctx).ReadingStates.Load(ctx, obj.ID)()
+}
+
+// EstimatedReadingMinutes is the resolver for the estimatedReadingMinutes field.
+func (r *bookResolver) EstimatedReadingMinutes(ctx context.Context, obj *book.Book) (int, error) {
+	return reading.EstimateMinutes(obj.Content), nil
+}
+
+// Source is the resolver for the source field.
+func (r *bookLinkResolver) Source(ctx context.Context, obj *link.Link) (*book.Book, error) {
+	return r.loaders(ctx).Books.Load(ctx, obj.SourceID)()
+}
+
+// Book is the resolver for the book field.
+func (r *bookLinkResolver) Book(ctx context.Context, obj *link.Link) (*book.Book, error) {
+	if !obj.Resolved() {
+		return nil, nil
+	}
+
+	b, err := r.loaders(ctx).Books.Load(ctx, obj.TargetID)()
+	if errors.Is(err, book.ErrNotFound) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// Frequency is the resolver for the frequency field.
+func (r *digestSettingsResolver) Frequency(ctx context.Context, obj *digest.Settings) (model.DigestFrequency, error) {
+	return toModelFrequency(obj.Frequency), nil
+}
+
+// Range is the resolver for the range field.
+func (r *libraryStatsResolver) Range(ctx context.Context, obj *stats.LibraryStats) (model.StatsRange, error) {
+	return toModelRange(obj.Range), nil
+}
+
+// Interval is the resolver for the interval field.
+func (r *libraryStatsResolver) Interval(ctx context.Context, obj *stats.LibraryStats) (model.StatsInterval, error) {
+	return toModelInterval(obj.Interval), nil
+}
+
 // SaveBook is the resolver for the saveBook field.
-func (r *mutationResolver) SaveBook(ctx context.Context, content string) (*book.Book, error) {
-	// Temporary: use fixed user ID for testing
-	userID := "test-user-123"
-	// userID, ok := ctx.Value("userID").(string)
-	// if !ok || userID == "" {
-	// 	return nil, fmt.Errorf("unauthorized: user ID not found in context")
-	// }
+func (r *mutationResolver) SaveBook(ctx context.Context, content string, workspaceID *string) (*book.Book, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return r.BookUseCase.SaveBook(ctx, userID, "", "", "", content, "", []string{})
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+
+	return r.BookUseCase.SaveBook(ctx, userID, workspaceIDValue, "", "", "", content, "", []string{})
 }
 
 // UpdateBook is the resolver for the updateBook field.
-func (r *mutationResolver) UpdateBook(ctx context.Context, id string, title *string, tags []string) (*book.Book, error) {
-	// Temporary: use fixed user ID for testing
-	userID := "test-user-123"
-	// userID, ok := ctx.Value("userID").(string)
-	// if !ok || userID == "" {
-	// 	return nil, fmt.Errorf("unauthorized: user ID not found in context")
-	// }
+func (r *mutationResolver) UpdateBook(ctx context.Context, id string, title *string, tags []string, expectedVersion *int) (*book.Book, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	titleValue := ""
 	if title != nil {
@@ -42,55 +189,739 @@ func (r *mutationResolver) UpdateBook(ctx context.Context, id string, title *str
 		return nil, err
 	}
 
-	return r.BookUseCase.UpdateBook(ctx, id, userID, titleValue, book.Author, book.Description, book.Content, book.URL, tags)
+	return r.BookUseCase.UpdateBook(ctx, id, userID, titleValue, book.Author, book.Description, book.Content, book.URL, tags, expectedVersion)
 }
 
 // MergeBooks is the resolver for the mergeBooks field.
-func (r *mutationResolver) MergeBooks(ctx context.Context, bookIds []string) (*book.Book, error) {
-	// Temporary: use fixed user ID for testing
-	userID := "test-user-123"
-	// userID, ok := ctx.Value("userID").(string)
-	// if !ok || userID == "" {
-	// 	return nil, fmt.Errorf("unauthorized: user ID not found in context")
-	// }
+func (r *mutationResolver) MergeBooks(ctx context.Context, bookIds []string, expectedVersions []int) (*book.Book, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.BookUseCase.MergeBooks(ctx, userID, bookIds, expectedVersions)
+}
+
+// CreateShareLink is the resolver for the createShareLink field.
+func (r *mutationResolver) CreateShareLink(ctx context.Context, bookID string, expiresAt *time.Time, password *string) (*share.Link, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordValue := ""
+	if password != nil {
+		passwordValue = *password
+	}
+
+	return r.ShareUseCase.CreateShareLink(ctx, userID, bookID, expiresAt, passwordValue)
+}
+
+// RevokeShareLink is the resolver for the revokeShareLink field.
+func (r *mutationResolver) RevokeShareLink(ctx context.Context, id string) (bool, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.ShareUseCase.RevokeShareLink(ctx, id, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	return r.BookUseCase.MergeBooks(ctx, userID, bookIds)
+// CreateAnnotation is the resolver for the createAnnotation field.
+func (r *mutationResolver) CreateAnnotation(ctx context.Context, bookID string, start int, end int, color *model.AnnotationColor, note *string) (*annotation.Annotation, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var colorValue annotation.Color
+	if color != nil {
+		colorValue = toDomainColor(*color)
+	}
+	noteValue := ""
+	if note != nil {
+		noteValue = *note
+	}
+
+	return r.AnnotationUseCase.CreateAnnotation(ctx, userID, bookID, start, end, colorValue, noteValue)
+}
+
+// UpdateAnnotation is the resolver for the updateAnnotation field.
+func (r *mutationResolver) UpdateAnnotation(ctx context.Context, id string, color *model.AnnotationColor, note *string) (*annotation.Annotation, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var colorValue *annotation.Color
+	if color != nil {
+		c := toDomainColor(*color)
+		colorValue = &c
+	}
+
+	return r.AnnotationUseCase.UpdateAnnotation(ctx, id, userID, colorValue, note)
+}
+
+// DeleteAnnotation is the resolver for the deleteAnnotation field.
+func (r *mutationResolver) DeleteAnnotation(ctx context.Context, id string) (bool, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.AnnotationUseCase.DeleteAnnotation(ctx, id, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetReadingStatus is the resolver for the setReadingStatus field.
+func (r *mutationResolver) SetReadingStatus(ctx context.Context, bookID string, status model.ReadingStatus, progress *int) (*book.Book, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := r.ReadingUseCase.SetReadingStatus(ctx, bookID, userID, toDomainStatus(status), progress)
+	if err != nil {
+		return nil, err
+	}
+	r.loaders(ctx).ReadingStates.Prime(ctx, bookID, state)
+
+	return r.BookUseCase.GetBook(ctx, bookID, userID)
+}
+
+// UpdateDigestSettings is the resolver for the updateDigestSettings field.
+func (r *mutationResolver) UpdateDigestSettings(ctx context.Context, enabled *bool, email *string, frequency *model.DigestFrequency, timezone *string) (*digest.Settings, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var frequencyValue *digest.Frequency
+	if frequency != nil {
+		f := toDomainFrequency(*frequency)
+		frequencyValue = &f
+	}
+
+	return r.DigestUseCase.UpdateSettings(ctx, userID, enabled, email, frequencyValue, timezone)
+}
+
+// SetTagSynonym is the resolver for the setTagSynonym field.
+func (r *mutationResolver) SetTagSynonym(ctx context.Context, alias string, tag string) (*tag.Synonym, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.TagUseCase.SetSynonym(ctx, userID, alias, tag)
+}
+
+// DeleteTagSynonym is the resolver for the deleteTagSynonym field.
+func (r *mutationResolver) DeleteTagSynonym(ctx context.Context, alias string) (bool, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.TagUseCase.DeleteSynonym(ctx, userID, alias); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateSavedSearch is the resolver for the createSavedSearch field.
+func (r *mutationResolver) CreateSavedSearch(ctx context.Context, name string, query string, workspaceID *string, pinned *bool, subscribed *bool) (*search.SavedSearch, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+	pinnedValue := pinned != nil && *pinned
+	subscribedValue := subscribed != nil && *subscribed
+
+	return r.SearchUseCase.CreateSavedSearch(ctx, userID, workspaceIDValue, name, query, pinnedValue, subscribedValue)
+}
+
+// UpdateSavedSearch is the resolver for the updateSavedSearch field.
+func (r *mutationResolver) UpdateSavedSearch(ctx context.Context, id string, name *string, query *string, pinned *bool, subscribed *bool) (*search.SavedSearch, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.SearchUseCase.UpdateSavedSearch(ctx, id, userID, name, query, pinned, subscribed)
+}
+
+// DeleteSavedSearch is the resolver for the deleteSavedSearch field.
+func (r *mutationResolver) DeleteSavedSearch(ctx context.Context, id string) (bool, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.SearchUseCase.DeleteSavedSearch(ctx, id, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateWorkspace is the resolver for the createWorkspace field.
+func (r *mutationResolver) CreateWorkspace(ctx context.Context, name string) (*workspace.Workspace, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WorkspaceUseCase.CreateWorkspace(ctx, userID, name)
+}
+
+// InviteToWorkspace is the resolver for the inviteToWorkspace field.
+func (r *mutationResolver) InviteToWorkspace(ctx context.Context, workspaceID string, role model.WorkspaceRole) (*workspace.Invitation, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WorkspaceUseCase.CreateInvitation(ctx, workspaceID, userID, toDomainRole(role))
+}
+
+// AcceptWorkspaceInvitation is the resolver for the acceptWorkspaceInvitation field.
+func (r *mutationResolver) AcceptWorkspaceInvitation(ctx context.Context, token string) (*workspace.Workspace, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WorkspaceUseCase.AcceptInvitation(ctx, token, userID)
+}
+
+// UpdateWorkspaceMemberRole is the resolver for the updateWorkspaceMemberRole field.
+func (r *mutationResolver) UpdateWorkspaceMemberRole(ctx context.Context, workspaceID string, userID string, role model.WorkspaceRole) (*workspace.Member, error) {
+	currentUser, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WorkspaceUseCase.UpdateMemberRole(ctx, workspaceID, currentUser, userID, toDomainRole(role))
+}
+
+// RemoveWorkspaceMember is the resolver for the removeWorkspaceMember field.
+func (r *mutationResolver) RemoveWorkspaceMember(ctx context.Context, workspaceID string, userID string) (bool, error) {
+	currentUser, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.WorkspaceUseCase.RemoveMember(ctx, workspaceID, currentUser, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreatePersonalAccessToken is the resolver for the createPersonalAccessToken field.
+func (r *mutationResolver) CreatePersonalAccessToken(ctx context.Context, name string, scopes []model.TokenScope, expiresAt *time.Time) (*model.CreatedPersonalAccessToken, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, secret, err := r.AccessTokenUseCase.CreateToken(ctx, userID, name, toDomainScopes(scopes), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CreatedPersonalAccessToken{
+		Token:               secret,
+		PersonalAccessToken: token,
+	}, nil
+}
+
+// RevokePersonalAccessToken is the resolver for the revokePersonalAccessToken field.
+func (r *mutationResolver) RevokePersonalAccessToken(ctx context.Context, id string) (bool, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.AccessTokenUseCase.RevokeToken(ctx, id, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, url string, events []model.WebhookEvent, secret *string) (*webhook.Webhook, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secretValue := ""
+	if secret != nil {
+		secretValue = *secret
+	}
+
+	return r.WebhookUseCase.CreateWebhook(ctx, userID, url, secretValue, toDomainEvents(events))
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.WebhookUseCase.DeleteWebhook(ctx, id, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TestWebhook is the resolver for the testWebhook field.
+func (r *mutationResolver) TestWebhook(ctx context.Context, id string) (*webhook.Delivery, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WebhookUseCase.TestWebhook(ctx, id, userID)
+}
+
+// Scopes is the resolver for the scopes field.
+func (r *personalAccessTokenResolver) Scopes(ctx context.Context, obj *accesstoken.Token) ([]model.TokenScope, error) {
+	return toModelScopes(obj.Scopes), nil
 }
 
 // Book is the resolver for the book field.
 func (r *queryResolver) Book(ctx context.Context, id string) (*book.Book, error) {
-	// Temporary: use fixed user ID for testing
-	userID := "test-user-123"
-	// userID, ok := ctx.Value("userID").(string)
-	// if !ok || userID == "" {
-	// 	return nil, fmt.Errorf("unauthorized: user ID not found in context")
-	// }
-
-	return r.BookUseCase.GetBook(ctx, id, userID)
+	return r.loaders(ctx).Books.Load(ctx, id)()
 }
 
 // MyBooks is the resolver for the myBooks field.
-func (r *queryResolver) MyBooks(ctx context.Context, keyword *string) ([]*book.Book, error) {
-	// Temporary: use fixed user ID for testing
-	userID := "test-user-123"
-	// userID, ok := ctx.Value("userID").(string)
-	// if !ok || userID == "" {
-	// 	return nil, fmt.Errorf("unauthorized: user ID not found in context")
-	// }
+func (r *queryResolver) MyBooks(ctx context.Context, keyword *string, workspaceID *string, status []model.ReadingStatus, tag *string) ([]*book.Book, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	keywordValue := ""
 	if keyword != nil {
 		keywordValue = *keyword
 	}
 
-	return r.BookUseCase.GetMyBooks(ctx, userID, keywordValue)
+	var books []*book.Book
+	if workspaceID != nil {
+		books, err = r.BookUseCase.GetWorkspaceBooks(ctx, *workspaceID, userID, keywordValue)
+	} else {
+		books, err = r.BookUseCase.GetMyBooks(ctx, userID, keywordValue)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tag != nil {
+		books, err = r.TagUseCase.FilterByTag(ctx, books, userID, *tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.ReadingUseCase.FilterByStatus(ctx, books, userID, toDomainStatuses(status))
+}
+
+// Backlog is the resolver for the backlog field.
+func (r *queryResolver) Backlog(ctx context.Context, workspaceID *string, sort *model.BacklogSort, limit *int) ([]*book.Book, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+	var sortValue reading.BacklogSort
+	if sort != nil {
+		sortValue = toDomainBacklogSort(*sort)
+	}
+	limitValue := 0
+	if limit != nil {
+		limitValue = *limit
+	}
+
+	return r.ReadingUseCase.GetBacklog(ctx, userID, workspaceIDValue, sortValue, limitValue)
+}
+
+// UnresolvedLinks is the resolver for the unresolvedLinks field.
+func (r *queryResolver) UnresolvedLinks(ctx context.Context, workspaceID *string) ([]*link.Link, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+
+	return r.LinkUseCase.GetUnresolvedLinks(ctx, userID, workspaceIDValue)
+}
+
+// KnowledgeGraph is the resolver for the knowledgeGraph field.
+func (r *queryResolver) KnowledgeGraph(ctx context.Context, tag *string, depth *int, workspaceID *string) (*link.Graph, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tagValue := ""
+	if tag != nil {
+		// The graph starts from the tag that tag stands for
+		var err error
+		tagValue, err = r.TagUseCase.ResolveTag(ctx, userID, *tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+	depthValue := linkUseCase.DefaultGraphDepth
+	if depth != nil {
+		depthValue = *depth
+	}
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+
+	return r.LinkUseCase.GetKnowledgeGraph(ctx, userID, workspaceIDValue, tagValue, depthValue)
+}
+
+// LibraryStats is the resolver for the libraryStats field.
+func (r *queryResolver) LibraryStats(ctx context.Context, rangeArg *model.StatsRange, workspaceID *string) (*stats.LibraryStats, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rangeValue stats.Range
+	if rangeArg != nil {
+		rangeValue = toDomainRange(*rangeArg)
+	}
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+
+	return r.StatsUseCase.GetLibraryStats(ctx, userID, workspaceIDValue, rangeValue)
+}
+
+// TagTree is the resolver for the tagTree field.
+func (r *queryResolver) TagTree(ctx context.Context, workspaceID *string) ([]*tag.Node, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+
+	return r.TagUseCase.GetTagTree(ctx, userID, workspaceIDValue)
+}
+
+// TagSynonyms is the resolver for the tagSynonyms field.
+func (r *queryResolver) TagSynonyms(ctx context.Context) ([]*tag.Synonym, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.TagUseCase.GetSynonyms(ctx, userID)
+}
+
+// SearchBooks is the resolver for the searchBooks field.
+func (r *queryResolver) SearchBooks(ctx context.Context, query string, workspaceID *string, limit *int) ([]*book.Book, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceIDValue := ""
+	if workspaceID != nil {
+		workspaceIDValue = *workspaceID
+	}
+	limitValue := 0
+	if limit != nil {
+		limitValue = *limit
+	}
+
+	return r.SearchUseCase.SearchBooks(ctx, userID, workspaceIDValue, query, limitValue)
+}
+
+// SavedSearches is the resolver for the savedSearches field.
+func (r *queryResolver) SavedSearches(ctx context.Context) ([]*search.SavedSearch, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.SearchUseCase.GetSavedSearches(ctx, userID)
+}
+
+// SavedSearch is the resolver for the savedSearch field.
+func (r *queryResolver) SavedSearch(ctx context.Context, id string) (*search.SavedSearch, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.SearchUseCase.GetSavedSearch(ctx, id, userID)
+}
+
+// DigestSettings is the resolver for the digestSettings field.
+func (r *queryResolver) DigestSettings(ctx context.Context) (*digest.Settings, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.DigestUseCase.GetSettings(ctx, userID)
+}
+
+// PreviewDigest is the resolver for the previewDigest field.
+func (r *queryResolver) PreviewDigest(ctx context.Context) (*digest.Preview, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.DigestUseCase.PreviewDigest(ctx, userID)
+}
+
+// ShareLinks is the resolver for the shareLinks field.
+func (r *queryResolver) ShareLinks(ctx context.Context, bookID string) ([]*share.Link, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ShareUseCase.GetShareLinks(ctx, bookID, userID)
+}
+
+// Workspaces is the resolver for the workspaces field.
+func (r *queryResolver) Workspaces(ctx context.Context) ([]*workspace.Workspace, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WorkspaceUseCase.GetMyWorkspaces(ctx, userID)
+}
+
+// Workspace is the resolver for the workspace field.
+func (r *queryResolver) Workspace(ctx context.Context, id string) (*workspace.Workspace, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WorkspaceUseCase.GetWorkspace(ctx, id, userID)
+}
+
+// PersonalAccessTokens is the resolver for the personalAccessTokens field.
+func (r *queryResolver) PersonalAccessTokens(ctx context.Context) ([]*accesstoken.Token, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.AccessTokenUseCase.GetMyTokens(ctx, userID)
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*webhook.Webhook, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WebhookUseCase.GetMyWebhooks(ctx, userID)
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID string, limit *int) ([]*webhook.Delivery, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limitValue := 0
+	if limit != nil {
+		limitValue = *limit
+	}
+
+	return r.WebhookUseCase.GetDeliveries(ctx, webhookID, userID, limitValue)
+}
+
+// Status is the resolver for the status field.
+func (r *readingStateResolver) Status(ctx context.Context, obj *reading.State) (model.ReadingStatus, error) {
+	return toModelStatus(obj.Status), nil
+}
+
+// WorkspaceID is the resolver for the workspaceId field.
+func (r *savedSearchResolver) WorkspaceID(ctx context.Context, obj *search.SavedSearch) (*string, error) {
+	if obj.WorkspaceID == "" {
+		return nil, nil
+	}
+	return &obj.WorkspaceID, nil
+}
+
+// Books is the resolver for the books field.
+func (r *savedSearchResolver) Books(ctx context.Context, obj *search.SavedSearch, limit *int) ([]*book.Book, error) {
+	limitValue := 0
+	if limit != nil {
+		limitValue = *limit
+	}
+
+	return r.SearchUseCase.SavedSearchBooks(ctx, obj, limitValue)
+}
+
+// Events is the resolver for the events field.
+func (r *webhookResolver) Events(ctx context.Context, obj *webhook.Webhook) ([]model.WebhookEvent, error) {
+	return toModelEvents(obj.Events), nil
+}
+
+// EventType is the resolver for the eventType field.
+func (r *webhookDeliveryResolver) EventType(ctx context.Context, obj *webhook.Delivery) (string, error) {
+	return string(obj.EventType), nil
+}
+
+// StatusCode is the resolver for the statusCode field.
+func (r *webhookDeliveryResolver) StatusCode(ctx context.Context, obj *webhook.Delivery) (*int, error) {
+	// Zero means no response was received
+	if obj.StatusCode == 0 {
+		return nil, nil
+	}
+	return &obj.StatusCode, nil
+}
+
+// Error is the resolver for the error field.
+func (r *webhookDeliveryResolver) Error(ctx context.Context, obj *webhook.Delivery) (*string, error) {
+	if obj.Error == "" {
+		return nil, nil
+	}
+	return &obj.Error, nil
+}
+
+// Role is the resolver for the role field.
+func (r *workspaceResolver) Role(ctx context.Context, obj *workspace.Workspace) (model.WorkspaceRole, error) {
+	userID, err := middleware.RequireUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	members, err := r.loaders(ctx).WorkspaceMembers.Load(ctx, obj.ID)()
+	if err != nil {
+		return "", err
+	}
+	for _, member := range members {
+		if member.UserID == userID {
+			return toModelRole(member.Role), nil
+		}
+	}
+	return "", workspace.ErrMemberNotFound
+}
+
+// Members is the resolver for the members field.
+func (r *workspaceResolver) Members(ctx context.Context, obj *workspace.Workspace) ([]*workspace.Member, error) {
+	return r.loaders(ctx).WorkspaceMembers.Load(ctx, obj.ID)()
+}
+
+// Role is the resolver for the role field.
+func (r *workspaceInvitationResolver) Role(ctx context.Context, obj *workspace.Invitation) (model.WorkspaceRole, error) {
+	return toModelRole(obj.Role), nil
+}
+
+// Role is the resolver for the role field.
+func (r *workspaceMemberResolver) Role(ctx context.Context, obj *workspace.Member) (model.WorkspaceRole, error) {
+	return toModelRole(obj.Role), nil
 }
 
+// Annotation returns generated.AnnotationResolver implementation.
+func (r *Resolver) Annotation() generated.AnnotationResolver { return &annotationResolver{r} }
+
+// Book returns generated.BookResolver implementation.
+func (r *Resolver) Book() generated.BookResolver { return &bookResolver{r} }
+
+// BookLink returns generated.BookLinkResolver implementation.
+func (r *Resolver) BookLink() generated.BookLinkResolver { return &bookLinkResolver{r} }
+
+// DigestSettings returns generated.DigestSettingsResolver implementation.
+func (r *Resolver) DigestSettings() generated.DigestSettingsResolver {
+	return &digestSettingsResolver{r}
+}
+
+// LibraryStats returns generated.LibraryStatsResolver implementation.
+func (r *Resolver) LibraryStats() generated.LibraryStatsResolver { return &libraryStatsResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// PersonalAccessToken returns generated.PersonalAccessTokenResolver implementation.
+func (r *Resolver) PersonalAccessToken() generated.PersonalAccessTokenResolver {
+	return &personalAccessTokenResolver{r}
+}
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// ReadingState returns generated.ReadingStateResolver implementation.
+func (r *Resolver) ReadingState() generated.ReadingStateResolver { return &readingStateResolver{r} }
+
+// SavedSearch returns generated.SavedSearchResolver implementation.
+func (r *Resolver) SavedSearch() generated.SavedSearchResolver { return &savedSearchResolver{r} }
+
+// Webhook returns generated.WebhookResolver implementation.
+func (r *Resolver) Webhook() generated.WebhookResolver { return &webhookResolver{r} }
+
+// WebhookDelivery returns generated.WebhookDeliveryResolver implementation.
+func (r *Resolver) WebhookDelivery() generated.WebhookDeliveryResolver {
+	return &webhookDeliveryResolver{r}
+}
+
+// Workspace returns generated.WorkspaceResolver implementation.
+func (r *Resolver) Workspace() generated.WorkspaceResolver { return &workspaceResolver{r} }
+
+// WorkspaceInvitation returns generated.WorkspaceInvitationResolver implementation.
+func (r *Resolver) WorkspaceInvitation() generated.WorkspaceInvitationResolver {
+	return &workspaceInvitationResolver{r}
+}
+
+// WorkspaceMember returns generated.WorkspaceMemberResolver implementation.
+func (r *Resolver) WorkspaceMember() generated.WorkspaceMemberResolver {
+	return &workspaceMemberResolver{r}
+}
+
+type annotationResolver struct{ *Resolver }
+type bookResolver struct{ *Resolver }
+type bookLinkResolver struct{ *Resolver }
+type digestSettingsResolver struct{ *Resolver }
+type libraryStatsResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type personalAccessTokenResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type readingStateResolver struct{ *Resolver }
+type savedSearchResolver struct{ *Resolver }
+type webhookResolver struct{ *Resolver }
+type webhookDeliveryResolver struct{ *Resolver }
+type workspaceResolver struct{ *Resolver }
+type workspaceInvitationResolver struct{ *Resolver }
+type workspaceMemberResolver struct{ *Resolver }
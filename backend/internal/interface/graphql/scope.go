@@ -0,0 +1,33 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+)
+
+// RequireTokenScopes rejects operations made with a personal access token that
+// lacks the scope for the operation type: queries need read and mutations need write.
+// It is meant to be installed with AroundOperations.
+func RequireTokenScopes(ctx context.Context, next gqlgen.OperationHandler) gqlgen.ResponseHandler {
+	scope := accesstoken.ScopeRead
+	if op := gqlgen.GetOperationContext(ctx).Operation; op != nil && op.Operation != ast.Query {
+		scope = accesstoken.ScopeWrite
+	}
+
+	if !accesstoken.Allows(ctx, scope) {
+		return gqlgen.OneShot(&gqlgen.Response{
+			Errors: gqlerror.List{{
+				Message:    fmt.Sprintf("token lacks the %s scope", scope),
+				Extensions: map[string]interface{}{"code": "FORBIDDEN"},
+			}},
+		})
+	}
+
+	return next(ctx)
+}
@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ServerOptions controls which operations the GraphQL endpoint accepts
+type ServerOptions struct {
+	// MaxDepth limits how deeply selections may nest; 0 disables the limit
+	MaxDepth int
+	// MaxComplexity limits the estimated cost of an operation; 0 disables the limit
+	MaxComplexity int
+	// APQCacheSize is the number of automatic persisted queries kept in
+	// memory; 0 disables automatic persisted queries
+	APQCacheSize int
+	// Allowlist, when set, only lets registered documents run. Automatic
+	// persisted queries are disabled since they would let clients register
+	// arbitrary queries.
+	Allowlist *Allowlist
+	// Introspection enables the __schema and __type queries
+	Introspection bool
+}
+
+// NewServer builds the GraphQL handler with the same transports and caches as
+// handler.NewDefaultServer, applying the limits in opts
+func NewServer(es gqlgen.ExecutableSchema, opts ServerOptions) *handler.Server {
+	srv := handler.New(es)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	if opts.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	if opts.Allowlist != nil {
+		srv.Use(opts.Allowlist)
+	} else if opts.APQCacheSize > 0 {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](opts.APQCacheSize),
+		})
+	}
+	if opts.MaxDepth > 0 {
+		srv.Use(DepthLimit{Max: opts.MaxDepth})
+	}
+	if opts.MaxComplexity > 0 {
+		srv.Use(extension.FixedComplexityLimit(opts.MaxComplexity))
+	}
+
+	return srv
+}
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// DepthLimit rejects operations whose selections nest deeper than Max.
+// Introspection fields are not counted so that tools can still load the
+// schema when introspection is enabled.
+type DepthLimit struct {
+	Max int
+}
+
+var (
+	_ gqlgen.HandlerExtension        = DepthLimit{}
+	_ gqlgen.OperationContextMutator = DepthLimit{}
+)
+
+func (d DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d DepthLimit) Validate(gqlgen.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, rc *gqlgen.OperationContext) *gqlerror.Error {
+	if rc.Operation == nil {
+		return nil
+	}
+	depth := selectionDepth(rc.Operation.SelectionSet)
+	if depth > d.Max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Max)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns the number of nested fields in the deepest branch of
+// set. Fragment cycles are rejected by validation before this runs.
+func selectionDepth(set ast.SelectionSet) int {
+	deepest := 0
+	for _, selection := range set {
+		depth := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		deepest = max(deepest, depth)
+	}
+	return deepest
+}
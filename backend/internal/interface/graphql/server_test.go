@@ -0,0 +1,143 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/interface/graphql/generated"
+)
+
+type graphqlResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// execute posts body to a server built with opts. Every operation used here
+// is either rejected before execution or only selects __typename, so the
+// resolver needs no use cases.
+func execute(t *testing.T, opts ServerOptions, body string) graphqlResponse {
+	srv := NewServer(generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}}), opts)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var resp graphqlResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+	return resp
+}
+
+func queryBody(query string) string {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	return string(body)
+}
+
+func errorCode(resp graphqlResponse) string {
+	if len(resp.Errors) == 0 {
+		return ""
+	}
+	code, _ := resp.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+func TestNewServer_DepthLimit(t *testing.T) {
+	opts := ServerOptions{MaxDepth: 2}
+
+	resp := execute(t, opts, queryBody(`{ workspaces { members { userId } } }`))
+	assert.Equal(t, errDepthLimit, errorCode(resp))
+	assert.Contains(t, resp.Errors[0].Message, "depth 3")
+
+	// Depth through fragments is counted too
+	resp = execute(t, opts, queryBody(`
+		query { workspaces { ...W } }
+		fragment W on Workspace { members { role } }
+	`))
+	assert.Equal(t, errDepthLimit, errorCode(resp))
+
+	resp = execute(t, opts, queryBody(`{ __typename }`))
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, "Query", resp.Data["__typename"])
+}
+
+func TestNewServer_ComplexityLimit(t *testing.T) {
+	resp := execute(t, ServerOptions{MaxComplexity: 2}, queryBody(`{ myBooks { id title tags } }`))
+	assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", errorCode(resp))
+}
+
+func TestNewServer_Introspection(t *testing.T) {
+	const query = `{ __schema { queryType { name } } }`
+
+	resp := execute(t, ServerOptions{}, queryBody(query))
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "introspection disabled")
+
+	// Introspection is not subject to the depth limit
+	resp = execute(t, ServerOptions{Introspection: true, MaxDepth: 1}, queryBody(query))
+	assert.Empty(t, resp.Errors)
+}
+
+func TestNewServer_AutomaticPersistedQueries(t *testing.T) {
+	const query = `{ __typename }`
+	hash := sha256Hex(query)
+	opts := ServerOptions{APQCacheSize: 10}
+	srv := NewServer(generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}}), opts)
+
+	post := func(body string) graphqlResponse {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		var resp graphqlResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+	hashOnly := `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}}`
+
+	resp := post(hashOnly)
+	assert.Equal(t, errPersistedQueryNotFound, errorCode(resp))
+
+	resp = post(`{"query":"{ __typename }","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}}`)
+	assert.Empty(t, resp.Errors)
+
+	resp = post(hashOnly)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, "Query", resp.Data["__typename"])
+}
+
+func TestNewServer_Allowlist(t *testing.T) {
+	const registered = "query Typename {\n  __typename\n}"
+	allowlist, err := NewAllowlist(map[string]string{"sha256:" + sha256Hex(registered): registered})
+	require.NoError(t, err)
+	opts := ServerOptions{Allowlist: allowlist, APQCacheSize: 10}
+
+	// Registered documents run by hash or by text, regardless of indentation
+	resp := execute(t, opts, `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"`+sha256Hex(registered)+`"}}}`)
+	assert.Empty(t, resp.Errors)
+	resp = execute(t, opts, queryBody("query Typename { __typename }"))
+	assert.Empty(t, resp.Errors)
+
+	resp = execute(t, opts, queryBody(`{ myBooks { id } }`))
+	assert.Equal(t, errQueryNotAllowed, errorCode(resp))
+
+	// Automatic persisted queries cannot be used to register new documents
+	other := `{ __typename }`
+	resp = execute(t, opts, `{"query":"{ __typename }","extensions":{"persistedQuery":{"version":1,"sha256Hash":"`+sha256Hex(other)+`"}}}`)
+	assert.Equal(t, errQueryNotAllowed, errorCode(resp))
+	resp = execute(t, opts, `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"`+sha256Hex(other)+`"}}}`)
+	assert.Equal(t, errPersistedQueryNotFound, errorCode(resp))
+}
+
+func TestNewAllowlist_RejectsHashMismatch(t *testing.T) {
+	_, err := NewAllowlist(map[string]string{sha256Hex("{ a }"): "{ b }"})
+	assert.Error(t, err)
+}
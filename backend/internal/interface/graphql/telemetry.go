@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/motoya-k/tsundoc/internal/infra/telemetry"
+)
+
+// Telemetry is a gqlgen handler extension that traces each operation and
+// every field backed by a resolver, and records resolver latency. Trivial
+// struct fields are skipped to keep spans and metric labels meaningful.
+type Telemetry struct {
+	metrics *telemetry.Metrics
+}
+
+var (
+	_ gqlgen.HandlerExtension    = (*Telemetry)(nil)
+	_ gqlgen.ResponseInterceptor = (*Telemetry)(nil)
+	_ gqlgen.FieldInterceptor    = (*Telemetry)(nil)
+)
+
+// NewTelemetry returns the extension to install with srv.Use
+func NewTelemetry(metrics *telemetry.Metrics) *Telemetry {
+	return &Telemetry{metrics: metrics}
+}
+
+func (t *Telemetry) ExtensionName() string {
+	return "Telemetry"
+}
+
+func (t *Telemetry) Validate(gqlgen.ExecutableSchema) error {
+	return nil
+}
+
+func (t *Telemetry) InterceptResponse(ctx context.Context, next gqlgen.ResponseHandler) *gqlgen.Response {
+	name := "graphql.operation"
+	var attrs []attribute.KeyValue
+	if gqlgen.HasOperationContext(ctx) {
+		oc := gqlgen.GetOperationContext(ctx)
+		if oc.Operation != nil {
+			attrs = append(attrs, attribute.String("graphql.operation.type", string(oc.Operation.Operation)))
+		}
+		if oc.OperationName != "" {
+			name = "graphql " + oc.OperationName
+			attrs = append(attrs, attribute.String("graphql.operation.name", oc.OperationName))
+		}
+	}
+
+	ctx, span := telemetry.Tracer().Start(ctx, name)
+	defer span.End()
+	span.SetAttributes(attrs...)
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+func (t *Telemetry) InterceptField(ctx context.Context, next gqlgen.Resolver) (interface{}, error) {
+	fc := gqlgen.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	field := fc.Object + "." + fc.Field.Name
+	ctx, span := telemetry.Tracer().Start(ctx, field)
+	defer span.End()
+	span.SetAttributes(attribute.String("graphql.field.path", fc.Path().String()))
+
+	start := time.Now()
+	res, err := next(ctx)
+	t.metrics.ObserveResolver(field, err, time.Since(start))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
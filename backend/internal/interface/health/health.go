@@ -0,0 +1,121 @@
+// Package health serves the liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// Check reports whether a dependency can serve requests
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Handler serves /livez, which only shows that the process is running, and
+// /readyz, which runs the registered checks. Readiness fails once shutdown
+// begins so that load balancers stop routing new requests while in-flight
+// ones drain.
+type Handler struct {
+	timeout      time.Duration
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+// NewHandler creates a handler whose checks must finish within timeout
+func NewHandler(timeout time.Duration) *Handler {
+	return &Handler{
+		timeout: timeout,
+	}
+}
+
+// AddCheck registers a readiness check. Checks must be added before the
+// handler starts serving.
+func (h *Handler) AddCheck(name string, check Check) {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// Shutdown marks the server as no longer ready
+func (h *Handler) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Routes registers the probes. /health is kept as an alias of /livez for
+// existing deployments.
+func (h *Handler) Routes(r chi.Router) {
+	r.Get("/livez", h.ServeLive)
+	r.Get("/health", h.ServeLive)
+	r.Get("/readyz", h.ServeReady)
+}
+
+func (h *Handler) ServeLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+type readyResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func (h *Handler) ServeReady(w http.ResponseWriter, r *http.Request) {
+	resp := readyResponse{Status: "ok"}
+	status := http.StatusOK
+
+	if h.shuttingDown.Load() {
+		resp.Status = "shutting down"
+		status = http.StatusServiceUnavailable
+	} else {
+		resp.Checks = h.run(r.Context())
+		for _, result := range resp.Checks {
+			if result != "ok" {
+				resp.Status = "unavailable"
+				status = http.StatusServiceUnavailable
+				break
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// run calls the checks concurrently and returns "ok" or the error message
+// for each of them
+func (h *Handler) run(ctx context.Context) map[string]string {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]string, len(h.checks))
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := "ok"
+			if err := c.check(ctx); err != nil {
+				log.Warn().Err(err).Str("check", c.name).Msg("Readiness check failed")
+				result = err.Error()
+			}
+			mu.Lock()
+			results[c.name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
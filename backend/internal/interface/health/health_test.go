@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, h *Handler, path string) (int, readyResponse) {
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp readyResponse
+	if rec.Header().Get("Content-Type") == "application/json" {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	}
+	return rec.Code, resp
+}
+
+func TestHandler_Live(t *testing.T) {
+	h := NewHandler(time.Second)
+	h.AddCheck("database", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	// Liveness does not depend on the checks
+	for _, path := range []string{"/livez", "/health"} {
+		code, _ := serve(t, h, path)
+		assert.Equal(t, http.StatusOK, code, path)
+	}
+}
+
+func TestHandler_Ready(t *testing.T) {
+	t.Run("ready when every check passes", func(t *testing.T) {
+		h := NewHandler(time.Second)
+		h.AddCheck("database", func(ctx context.Context) error { return nil })
+
+		code, resp := serve(t, h, "/readyz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ok", resp.Status)
+		assert.Equal(t, map[string]string{"database": "ok"}, resp.Checks)
+	})
+
+	t.Run("unavailable when a check fails", func(t *testing.T) {
+		h := NewHandler(time.Second)
+		h.AddCheck("database", func(ctx context.Context) error { return errors.New("connection refused") })
+		h.AddCheck("ai", func(ctx context.Context) error { return nil })
+
+		code, resp := serve(t, h, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "unavailable", resp.Status)
+		assert.Equal(t, "connection refused", resp.Checks["database"])
+		assert.Equal(t, "ok", resp.Checks["ai"])
+	})
+
+	t.Run("slow checks time out", func(t *testing.T) {
+		h := NewHandler(10 * time.Millisecond)
+		h.AddCheck("ai", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		code, resp := serve(t, h, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, context.DeadlineExceeded.Error(), resp.Checks["ai"])
+	})
+
+	t.Run("unavailable once shutdown begins", func(t *testing.T) {
+		h := NewHandler(time.Second)
+		h.AddCheck("database", func(ctx context.Context) error { return nil })
+		h.Shutdown()
+
+		code, resp := serve(t, h, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "shutting down", resp.Status)
+
+		code, _ = serve(t, h, "/livez")
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
@@ -0,0 +1,327 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	annotationUseCase "github.com/motoya-k/tsundoc/internal/usecase/annotation"
+	bookUseCase "github.com/motoya-k/tsundoc/internal/usecase/book"
+)
+
+type bookResponse struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Tags        []string `json:"tags"`
+	Content     string   `json:"content"`
+	URL         string   `json:"url,omitempty"`
+	WorkspaceID string   `json:"workspaceId,omitempty"`
+	Version     int      `json:"version"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+	// Annotations are the user's highlights, returned with include=annotations
+	Annotations []annotationResponse `json:"annotations,omitempty"`
+}
+
+type annotationResponse struct {
+	ID        string `json:"id"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	Quote     string `json:"quote"`
+	Color     string `json:"color"`
+	Note      string `json:"note,omitempty"`
+	Detached  bool   `json:"detached,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type createBookRequest struct {
+	Content     string   `json:"content"`
+	Title       string   `json:"title"`
+	Tags        []string `json:"tags"`
+	URL         string   `json:"url"`
+	WorkspaceID string   `json:"workspaceId"`
+}
+
+type updateBookRequest struct {
+	Title   *string  `json:"title"`
+	Tags    []string `json:"tags"`
+	Content *string  `json:"content"`
+	URL     *string  `json:"url"`
+	// ExpectedVersion rejects the update with a conflict when the book has
+	// changed since the client read it
+	ExpectedVersion *int `json:"expectedVersion"`
+}
+
+type mergeBooksRequest struct {
+	BookIDs          []string `json:"bookIds"`
+	ExpectedVersions []int    `json:"expectedVersions"`
+}
+
+type booksResponse struct {
+	Books []bookResponse `json:"books"`
+}
+
+// BookHandler exposes book operations as a JSON API
+type BookHandler struct {
+	bookUC       *bookUseCase.UseCase
+	annotationUC *annotationUseCase.UseCase
+}
+
+func NewBookHandler(bookUC *bookUseCase.UseCase, annotationUC *annotationUseCase.UseCase) *BookHandler {
+	return &BookHandler{
+		bookUC:       bookUC,
+		annotationUC: annotationUC,
+	}
+}
+
+// Routes registers the book routes relative to the API version prefix
+func (h *BookHandler) Routes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(requireScope(accesstoken.ScopeRead))
+		r.Get("/books", h.ListBooks)
+		r.Get("/books/search", h.SearchBooks)
+		r.Get("/books/{id}", h.GetBook)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(requireScope(accesstoken.ScopeWrite))
+		r.Post("/books", h.CreateBook)
+		r.Post("/books/merge", h.MergeBooks)
+		r.Patch("/books/{id}", h.UpdateBook)
+		r.Delete("/books/{id}", h.DeleteBook)
+	})
+}
+
+func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req createBookRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	b, err := h.bookUC.SaveBook(r.Context(), userID, req.WorkspaceID, req.Title, "", "", req.Content, req.URL, req.Tags)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toBookResponse(b))
+}
+
+func (h *BookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
+	h.listBooks(w, r, r.URL.Query().Get("keyword"))
+}
+
+func (h *BookHandler) SearchBooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, newBadRequest("query parameter q is required"))
+		return
+	}
+
+	h.listBooks(w, r, query)
+}
+
+func (h *BookHandler) listBooks(w http.ResponseWriter, r *http.Request, keyword string) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var books []*book.Book
+	var err error
+	if workspaceID := r.URL.Query().Get("workspaceId"); workspaceID != "" {
+		books, err = h.bookUC.GetWorkspaceBooks(r.Context(), workspaceID, userID, keyword)
+	} else {
+		books, err = h.bookUC.GetMyBooks(r.Context(), userID, keyword)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := toBooksResponse(books)
+	if err := h.includeAnnotations(r, userID, resp.Books); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *BookHandler) GetBook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	b, err := h.bookUC.GetBook(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := []bookResponse{toBookResponse(b)}
+	if err := h.includeAnnotations(r, userID, resp); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp[0])
+}
+
+func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateBookRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+
+	// Fields omitted from the request keep their current value
+	b, err := h.bookUC.GetBook(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	title, content, url, tags := b.Title, b.Content, b.URL, b.Tags
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Content != nil {
+		content = *req.Content
+	}
+	if req.URL != nil {
+		url = *req.URL
+	}
+	if req.Tags != nil {
+		tags = req.Tags
+	}
+
+	updated, err := h.bookUC.UpdateBook(r.Context(), id, userID, title, b.Author, b.Description, content, url, tags, req.ExpectedVersion)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toBookResponse(updated))
+}
+
+func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.bookUC.DeleteBook(r.Context(), chi.URLParam(r, "id"), userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BookHandler) MergeBooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req mergeBooksRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	b, err := h.bookUC.MergeBooks(r.Context(), userID, req.BookIDs, req.ExpectedVersions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toBookResponse(b))
+}
+
+// includeAnnotations adds the user's annotations to books when the request
+// asks for them with include=annotations
+func (h *BookHandler) includeAnnotations(r *http.Request, userID string, books []bookResponse) error {
+	if !includes(r, "annotations") || len(books) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(books))
+	for i, b := range books {
+		ids[i] = b.ID
+	}
+
+	byBook, err := h.annotationUC.GetAnnotationsOfBooks(r.Context(), ids, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range books {
+		books[i].Annotations = toAnnotationResponses(byBook[books[i].ID])
+	}
+	return nil
+}
+
+func toBookResponse(b *book.Book) bookResponse {
+	tags := b.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return bookResponse{
+		ID:          b.ID,
+		Title:       b.Title,
+		Tags:        tags,
+		Content:     b.Content,
+		URL:         b.URL,
+		WorkspaceID: b.WorkspaceID,
+		Version:     b.Version,
+		CreatedAt:   b.CreatedAt.UTC().Format(timeFormat),
+		UpdatedAt:   b.UpdatedAt.UTC().Format(timeFormat),
+	}
+}
+
+func toBooksResponse(books []*book.Book) booksResponse {
+	resp := booksResponse{Books: make([]bookResponse, len(books))}
+	for i, b := range books {
+		resp.Books[i] = toBookResponse(b)
+	}
+	return resp
+}
+
+func toAnnotationResponses(annotations []*annotation.Annotation) []annotationResponse {
+	resp := make([]annotationResponse, len(annotations))
+	for i, a := range annotations {
+		resp[i] = annotationResponse{
+			ID:        a.ID,
+			Start:     a.Start,
+			End:       a.End,
+			Quote:     a.Quote,
+			Color:     string(a.Color),
+			Note:      a.Note,
+			Detached:  a.Detached,
+			CreatedAt: a.CreatedAt.UTC().Format(timeFormat),
+			UpdatedAt: a.UpdatedAt.UTC().Format(timeFormat),
+		}
+	}
+	return resp
+}
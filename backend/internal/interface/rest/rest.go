@@ -0,0 +1,190 @@
+// Package rest provides a versioned JSON API for clients that do not speak
+// GraphQL, such as browser extensions, shortcuts and shell scripts.
+package rest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/middleware"
+)
+
+// Prefix is the path under which the current API version is mounted
+const Prefix = "/api/v1"
+
+// maxBodyBytes limits request bodies to protect the server from huge uploads
+const maxBodyBytes = 10 << 20
+
+const timeFormat = time.RFC3339
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Field names the invalid input of a validation error
+	Field string `json:"field,omitempty"`
+	// Current is the stored book when an update conflicts with another edit
+	Current *bookResponse `json:"current,omitempty"`
+}
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+// apiError is an error with a known HTTP status and error code
+type apiError struct {
+	status  int
+	code    string
+	message string
+	field   string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func newBadRequest(message string) error {
+	return &apiError{status: http.StatusBadRequest, code: "BAD_REQUEST", message: message}
+}
+
+// NewRouter returns the REST API router, to be mounted at Prefix
+func NewRouter(books *BookHandler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/openapi.yaml", serveOpenAPI)
+	books.Routes(r)
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &apiError{status: http.StatusNotFound, code: "NOT_FOUND", message: "route not found"})
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &apiError{status: http.StatusMethodNotAllowed, code: "METHOD_NOT_ALLOWED", message: "method not allowed"})
+	})
+
+	return r
+}
+
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(openAPISpec)
+}
+
+// currentUserID returns the ID of the authenticated user. It responds 401 and
+// returns false when the request has none.
+func currentUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, err := middleware.RequireUserID(r.Context())
+	if err != nil {
+		writeError(w, &apiError{status: http.StatusUnauthorized, code: "UNAUTHORIZED", message: err.Error()})
+		return "", false
+	}
+	return userID, true
+}
+
+// includes reports whether the comma-separated include query parameter names
+// the related resource
+func includes(r *http.Request, name string) bool {
+	for _, v := range strings.Split(r.URL.Query().Get("include"), ",") {
+		if strings.TrimSpace(v) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// requireScope rejects requests made with a personal access token lacking the scope
+func requireScope(scope accesstoken.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !accesstoken.Allows(r.Context(), scope) {
+				writeError(w, &apiError{
+					status:  http.StatusForbidden,
+					code:    "FORBIDDEN",
+					message: fmt.Sprintf("token lacks the %s scope", scope),
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		return &apiError{status: http.StatusUnsupportedMediaType, code: "UNSUPPORTED_MEDIA_TYPE", message: "content type must be application/json"}
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return newBadRequest(fmt.Sprintf("invalid request body: %v", err))
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("Failed to encode response")
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		apiErr = classifyError(err)
+	}
+
+	if apiErr.status >= http.StatusInternalServerError {
+		log.Error().Err(err).Msg("REST API error")
+	}
+
+	body := errorBody{Code: apiErr.code, Message: apiErr.message, Field: apiErr.field}
+	var conflict *book.ConflictError
+	if errors.As(err, &conflict) {
+		current := toBookResponse(conflict.Current)
+		body.Current = &current
+	}
+
+	writeJSON(w, apiErr.status, errorResponse{Error: body})
+}
+
+// errorStatuses maps domain error kinds to HTTP statuses and error codes
+var errorStatuses = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{errs.ErrNotFound, http.StatusNotFound, "NOT_FOUND"},
+	{errs.ErrValidation, http.StatusBadRequest, "BAD_REQUEST"},
+	{errs.ErrUnauthorized, http.StatusForbidden, "FORBIDDEN"},
+	{errs.ErrConflict, http.StatusConflict, "CONFLICT"},
+	{errs.ErrQuotaExceeded, http.StatusTooManyRequests, "QUOTA_EXCEEDED"},
+	{errs.ErrAIUnavailable, http.StatusServiceUnavailable, "AI_UNAVAILABLE"},
+}
+
+// classifyError maps use case errors to HTTP errors. Only the message of a
+// classified error is shown; anything else is reported as an internal error.
+func classifyError(err error) *apiError {
+	if e, ok := errs.As(err); ok {
+		for _, s := range errorStatuses {
+			if errors.Is(e.Kind, s.kind) {
+				return &apiError{status: s.status, code: s.code, message: e.Message, field: e.Field}
+			}
+		}
+	}
+	return &apiError{status: http.StatusInternalServerError, code: "INTERNAL", message: "internal server error"}
+}
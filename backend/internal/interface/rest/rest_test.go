@@ -0,0 +1,343 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/book/booktest"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/transaction"
+	"github.com/motoya-k/tsundoc/internal/middleware"
+	annotationUseCase "github.com/motoya-k/tsundoc/internal/usecase/annotation"
+	bookUseCase "github.com/motoya-k/tsundoc/internal/usecase/book"
+)
+
+// MockAnnotationRepository implements annotation.Repository for testing
+type MockAnnotationRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnnotationRepository) Save(ctx context.Context, a *annotation.Annotation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) FindByID(ctx context.Context, id, userID string) (*annotation.Annotation, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string][]*annotation.Annotation, error) {
+	args := m.Called(ctx, bookIDs, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) FindAllByBookID(ctx context.Context, bookID string) ([]*annotation.Annotation, error) {
+	args := m.Called(ctx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) Update(ctx context.Context, a *annotation.Annotation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) UpdateAnchors(ctx context.Context, annotations []*annotation.Annotation) error {
+	args := m.Called(ctx, annotations)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) Delete(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func newTestRouter(repo book.Repository) chi.Router {
+	return NewRouter(NewBookHandler(
+		bookUseCase.NewUseCase(repo, nil, transaction.None, nil, nil, nil, nil),
+		annotationUseCase.NewUseCase(new(MockAnnotationRepository), repo),
+	))
+}
+
+// authenticated returns req as sent by user-123
+func authenticated(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, "user-123"))
+}
+
+// TestOpenAPI_MatchesRoutes keeps the served OpenAPI document in sync with the handlers
+func TestOpenAPI_MatchesRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(openAPISpec, &spec))
+
+	var documented []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	var routed []string
+	err := chi.Walk(newTestRouter(new(booktest.MockRepository)), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routed = append(routed, method+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(documented)
+	sort.Strings(routed)
+	assert.Equal(t, documented, routed)
+}
+
+func TestBookHandler_CreateBook(t *testing.T) {
+	repo := new(booktest.MockRepository)
+	router := newTestRouter(repo)
+
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(b *book.Book) bool {
+		return b.Content == "Some content" && b.Title == "Title"
+	})).Return(nil)
+
+	req := authenticated(httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`{"content":"Some content","title":"Title","tags":["go"]}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var resp bookResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "Title", resp.Title)
+	assert.Equal(t, []string{"go"}, resp.Tags)
+	repo.AssertExpectations(t)
+}
+
+func TestBookHandler_UpdateBook_StaleVersion(t *testing.T) {
+	repo := new(booktest.MockRepository)
+	router := newTestRouter(repo)
+
+	current := &book.Book{ID: "book-123", UserID: "user-123", Content: "Their edit", Version: 3}
+	repo.On("FindByID", mock.Anything, "book-123", "user-123").Return(current, nil)
+
+	req := authenticated(httptest.NewRequest(http.MethodPatch, "/books/book-123", strings.NewReader(`{"content":"My edit","expectedVersion":2}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusConflict, rec.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "CONFLICT", resp.Error.Code)
+	require.NotNil(t, resp.Error.Current)
+	assert.Equal(t, "Their edit", resp.Error.Current.Content)
+	assert.Equal(t, 3, resp.Error.Current.Version)
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestBookHandler_GetBook_IncludeAnnotations(t *testing.T) {
+	repo := new(booktest.MockRepository)
+	annotations := new(MockAnnotationRepository)
+	router := NewRouter(NewBookHandler(
+		bookUseCase.NewUseCase(repo, annotations, transaction.None, nil, nil, nil, nil),
+		annotationUseCase.NewUseCase(annotations, repo),
+	))
+
+	b := &book.Book{ID: "book-123", UserID: "user-123", Content: "Hello, world", Version: 1}
+	a, err := annotation.NewAnnotation("user-123", "book-123", b.Content, 7, 12, annotation.ColorPink, "planet")
+	require.NoError(t, err)
+	repo.On("FindByID", mock.Anything, "book-123", "user-123").Return(b, nil)
+	annotations.On("FindByBookIDs", mock.Anything, []string{"book-123"}, "user-123").
+		Return(map[string][]*annotation.Annotation{"book-123": {a}}, nil)
+
+	get := func(target string) bookResponse {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, authenticated(httptest.NewRequest(http.MethodGet, target, nil)))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp bookResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := get("/books/book-123?include=annotations")
+	require.Len(t, resp.Annotations, 1)
+	assert.Equal(t, "world", resp.Annotations[0].Quote)
+	assert.Equal(t, "pink", resp.Annotations[0].Color)
+	assert.Equal(t, "planet", resp.Annotations[0].Note)
+
+	// Annotations are only loaded on request
+	assert.Empty(t, get("/books/book-123").Annotations)
+	annotations.AssertNumberOfCalls(t, "FindByBookIDs", 1)
+}
+
+func TestBookHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setup        func(repo *booktest.MockRepository)
+		anonymous    bool
+		scopes       []accesstoken.Scope
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "invalid JSON",
+			method:       http.MethodPost,
+			path:         "/books",
+			body:         `{"content":`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "BAD_REQUEST",
+		},
+		{
+			name:         "unknown field",
+			method:       http.MethodPost,
+			path:         "/books",
+			body:         `{"content":"x","color":"red"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "BAD_REQUEST",
+		},
+		{
+			name:         "missing content",
+			method:       http.MethodPost,
+			path:         "/books",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "BAD_REQUEST",
+		},
+		{
+			name:   "book not found",
+			method: http.MethodGet,
+			path:   "/books/missing",
+			setup: func(repo *booktest.MockRepository) {
+				repo.On("FindByID", mock.Anything, "missing", "user-123").Return(nil, book.ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "NOT_FOUND",
+		},
+		{
+			name:         "search without query",
+			method:       http.MethodGet,
+			path:         "/books/search",
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "BAD_REQUEST",
+		},
+		{
+			name:         "unknown route",
+			method:       http.MethodGet,
+			path:         "/shelves",
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "NOT_FOUND",
+		},
+		{
+			name:         "anonymous request",
+			method:       http.MethodGet,
+			path:         "/books",
+			anonymous:    true,
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  "UNAUTHORIZED",
+		},
+		{
+			name:         "token without write scope",
+			method:       http.MethodDelete,
+			path:         "/books/book-123",
+			scopes:       []accesstoken.Scope{accesstoken.ScopeRead},
+			expectedCode: http.StatusForbidden,
+			expectedErr:  "FORBIDDEN",
+		},
+		{
+			name:   "internal errors are hidden",
+			method: http.MethodGet,
+			path:   "/books",
+			setup: func(repo *booktest.MockRepository) {
+				repo.On("FindByUserID", mock.Anything, "user-123", "").Return(nil, errors.New("connection refused"))
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedErr:  "INTERNAL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(booktest.MockRepository)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if !tt.anonymous {
+				req = authenticated(req)
+			}
+			if tt.scopes != nil {
+				req = req.WithContext(accesstoken.WithScopes(req.Context(), tt.scopes))
+			}
+			rec := httptest.NewRecorder()
+			newTestRouter(repo).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+
+			var resp errorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedErr, resp.Error.Code)
+			assert.NotContains(t, resp.Error.Message, "connection refused")
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("failed to get book: %w", book.ErrNotFound), http.StatusNotFound, "NOT_FOUND"},
+		{errs.Validation("content", "content is required"), http.StatusBadRequest, "BAD_REQUEST"},
+		{errs.Unauthorized("not authorized to add books to this workspace"), http.StatusForbidden, "FORBIDDEN"},
+		{errs.Conflict("already a member of this workspace"), http.StatusConflict, "CONFLICT"},
+		{errs.QuotaExceeded("failed to generate tags", errors.New("429")), http.StatusTooManyRequests, "QUOTA_EXCEEDED"},
+		{errs.AIUnavailable("failed to generate tags", errors.New("timeout")), http.StatusServiceUnavailable, "AI_UNAVAILABLE"},
+		{errors.New("book not found"), http.StatusInternalServerError, "INTERNAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			apiErr := classifyError(tt.err)
+			assert.Equal(t, tt.status, apiErr.status)
+			assert.Equal(t, tt.code, apiErr.code)
+		})
+	}
+
+	apiErr := classifyError(fmt.Errorf("failed to save book: %w", errs.Validation("content", "content is required")))
+	assert.Equal(t, "content", apiErr.field)
+	assert.Equal(t, "content is required", apiErr.message)
+}
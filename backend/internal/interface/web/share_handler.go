@@ -0,0 +1,165 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/share"
+	shareUseCase "github.com/motoya-k/tsundoc/internal/usecase/share"
+)
+
+// PasswordHeader carries the password of a protected link for non-HTML variants
+const PasswordHeader = "X-Share-Password"
+
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{if .Book}}{{.Book.Title}}{{else}}Protected book{{end}} - Tsundoc</title>
+<style>
+body { max-width: 48rem; margin: 2rem auto; padding: 0 1rem; font-family: system-ui, sans-serif; line-height: 1.6; color: #1f2937; }
+.content { white-space: pre-wrap; word-wrap: break-word; }
+.tags span { display: inline-block; margin-right: .5rem; padding: 0 .5rem; border-radius: .25rem; background: #f3f4f6; font-size: .875rem; }
+.error { color: #b91c1c; }
+footer { margin-top: 3rem; color: #6b7280; font-size: .875rem; }
+</style>
+</head>
+<body>
+{{if .Book}}
+<article>
+<h1>{{.Book.Title}}</h1>
+{{if .Book.Tags}}<p class="tags">{{range .Book.Tags}}<span>{{.}}</span>{{end}}</p>{{end}}
+<div class="content">{{.Book.Content}}</div>
+</article>
+<footer>Shared from Tsundoc &middot; {{.Book.UpdatedAt.Format "2006-01-02"}}</footer>
+{{else}}
+<form method="post">
+<h1>This book is password protected</h1>
+{{if .WrongPassword}}<p class="error">The password is incorrect.</p>{{end}}
+<input type="password" name="password" autofocus required>
+<button type="submit">View</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+type sharePage struct {
+	Book          *book.Book
+	WrongPassword bool
+}
+
+type sharedBookResponse struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Tags      []string  `json:"tags"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	ViewCount int       `json:"viewCount"`
+}
+
+// ShareHandler serves books through public share links without authentication
+type ShareHandler struct {
+	shareUC *shareUseCase.UseCase
+}
+
+func NewShareHandler(shareUC *shareUseCase.UseCase) *ShareHandler {
+	return &ShareHandler{
+		shareUC: shareUC,
+	}
+}
+
+// Routes registers the public share routes.
+// The format is selected by the token suffix: /s/{token} renders HTML,
+// /s/{token}.md returns raw Markdown and /s/{token}.json returns JSON.
+func (h *ShareHandler) Routes(r chi.Router) {
+	r.Get("/s/{token}", h.ServeShare)
+	r.Post("/s/{token}", h.ServeShare)
+}
+
+func (h *ShareHandler) ServeShare(w http.ResponseWriter, r *http.Request) {
+	token, format := splitFormat(chi.URLParam(r, "token"))
+
+	password := r.Header.Get(PasswordHeader)
+	submitted := false
+	if r.Method == http.MethodPost {
+		password = r.PostFormValue("password")
+		submitted = true
+	}
+
+	setShareHeaders(w)
+
+	b, link, err := h.shareUC.GetSharedBook(r.Context(), token, password)
+	if err != nil {
+		switch {
+		case errors.Is(err, share.ErrPasswordRequired):
+			if format == "html" {
+				w.WriteHeader(http.StatusUnauthorized)
+				h.renderHTML(w, sharePage{WrongPassword: submitted})
+				return
+			}
+			http.Error(w, "password required", http.StatusUnauthorized)
+		case errors.Is(err, share.ErrLinkInactive):
+			http.Error(w, "this link has expired or was revoked", http.StatusGone)
+		default:
+			// Do not reveal whether the token exists
+			log.Debug().Err(err).Msg("Failed to resolve share link")
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	switch format {
+	case "md":
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Write([]byte(b.Content))
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sharedBookResponse{
+			ID:        b.ID,
+			Title:     b.Title,
+			Tags:      b.Tags,
+			Content:   b.Content,
+			CreatedAt: b.CreatedAt,
+			UpdatedAt: b.UpdatedAt,
+			ViewCount: link.ViewCount,
+		})
+	default:
+		h.renderHTML(w, sharePage{Book: b})
+	}
+}
+
+func (h *ShareHandler) renderHTML(w http.ResponseWriter, page sharePage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := shareTemplate.Execute(w, page); err != nil {
+		log.Error().Err(err).Msg("Failed to render shared book")
+	}
+}
+
+func splitFormat(token string) (string, string) {
+	for _, format := range []string{"md", "json"} {
+		if trimmed, ok := strings.CutSuffix(token, "."+format); ok {
+			return trimmed, format
+		}
+	}
+	return token, "html"
+}
+
+func setShareHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Cache-Control", "no-store")
+}
@@ -0,0 +1,221 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/book/booktest"
+	"github.com/motoya-k/tsundoc/internal/domain/share"
+	shareUseCase "github.com/motoya-k/tsundoc/internal/usecase/share"
+)
+
+// stubShareRepository serves links by token. Other methods are left to the
+// embedded nil interface and must not be called.
+type stubShareRepository struct {
+	share.Repository
+	links map[string]*share.Link
+}
+
+func (r *stubShareRepository) FindByToken(ctx context.Context, token string) (*share.Link, error) {
+	if link, ok := r.links[token]; ok {
+		return link, nil
+	}
+	return nil, share.ErrNotFound
+}
+
+func (r *stubShareRepository) IncrementViewCount(ctx context.Context, id string) error {
+	return nil
+}
+
+func newTestRouter(t *testing.T) chi.Router {
+	newLink := func(expiresAt *time.Time) *share.Link {
+		link, err := share.NewLink("user-123", "book-123", expiresAt)
+		require.NoError(t, err)
+		return link
+	}
+
+	public := newLink(nil)
+	protected := newLink(nil)
+	require.NoError(t, protected.SetPassword("secret"))
+	past := time.Now().Add(-time.Hour)
+	expired := newLink(&past)
+	revoked := newLink(nil)
+	revoked.RevokedAt = &past
+
+	shares := &stubShareRepository{links: map[string]*share.Link{
+		"public":    public,
+		"protected": protected,
+		"expired":   expired,
+		"revoked":   revoked,
+	}}
+	books := new(booktest.MockRepository)
+	books.On("FindByID", mock.Anything, "book-123", "user-123").Return(&book.Book{
+		ID:      "book-123",
+		UserID:  "user-123",
+		Title:   "Go concurrency",
+		Content: "# Channels\n\n<script>alert(1)</script>",
+		Tags:    []string{"go"},
+	}, nil)
+
+	r := chi.NewRouter()
+	NewShareHandler(shareUseCase.NewUseCase(shares, books)).Routes(r)
+	return r
+}
+
+func TestShareHandler_ServeShare(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		form         string
+		password     string
+		expectedCode int
+		expectedType string
+		contains     string
+		notContains  string
+	}{
+		{
+			name:         "HTML",
+			method:       http.MethodGet,
+			path:         "/s/public",
+			expectedCode: http.StatusOK,
+			expectedType: "text/html; charset=utf-8",
+			contains:     "<h1>Go concurrency</h1>",
+			notContains:  "<script>",
+		},
+		{
+			name:         "Markdown",
+			method:       http.MethodGet,
+			path:         "/s/public.md",
+			expectedCode: http.StatusOK,
+			expectedType: "text/markdown; charset=utf-8",
+			contains:     "# Channels",
+		},
+		{
+			name:         "JSON",
+			method:       http.MethodGet,
+			path:         "/s/public.json",
+			expectedCode: http.StatusOK,
+			expectedType: "application/json",
+			contains:     `"title":"Go concurrency"`,
+		},
+		{
+			name:         "protected link asks for the password",
+			method:       http.MethodGet,
+			path:         "/s/protected",
+			expectedCode: http.StatusUnauthorized,
+			contains:     `<input type="password" name="password"`,
+			notContains:  "incorrect",
+		},
+		{
+			name:         "password form",
+			method:       http.MethodPost,
+			path:         "/s/protected",
+			form:         "password=secret",
+			expectedCode: http.StatusOK,
+			contains:     "<h1>Go concurrency</h1>",
+		},
+		{
+			name:         "wrong password in the form",
+			method:       http.MethodPost,
+			path:         "/s/protected",
+			form:         "password=guess",
+			expectedCode: http.StatusUnauthorized,
+			contains:     "The password is incorrect.",
+			notContains:  "Go concurrency",
+		},
+		{
+			name:         "password header",
+			method:       http.MethodGet,
+			path:         "/s/protected.json",
+			password:     "secret",
+			expectedCode: http.StatusOK,
+			contains:     `"title":"Go concurrency"`,
+		},
+		{
+			name:         "wrong password header",
+			method:       http.MethodGet,
+			path:         "/s/protected.md",
+			password:     "guess",
+			expectedCode: http.StatusUnauthorized,
+			notContains:  "Channels",
+		},
+		{
+			name:         "expired link",
+			method:       http.MethodGet,
+			path:         "/s/expired",
+			expectedCode: http.StatusGone,
+		},
+		{
+			name:         "revoked link",
+			method:       http.MethodGet,
+			path:         "/s/revoked.json",
+			expectedCode: http.StatusGone,
+		},
+		{
+			name:         "unknown token",
+			method:       http.MethodGet,
+			path:         "/s/missing",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.form))
+			if tt.form != "" {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tt.password != "" {
+				req.Header.Set(PasswordHeader, tt.password)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedType != "" {
+				assert.Equal(t, tt.expectedType, rec.Header().Get("Content-Type"))
+			}
+			if tt.contains != "" {
+				assert.Contains(t, rec.Body.String(), tt.contains)
+			}
+			if tt.notContains != "" {
+				assert.NotContains(t, rec.Body.String(), tt.notContains)
+			}
+
+			// Every response is locked down, including errors
+			assert.Equal(t, "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'", rec.Header().Get("Content-Security-Policy"))
+			assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+			assert.Equal(t, "noindex", rec.Header().Get("X-Robots-Tag"))
+			assert.Equal(t, "no-referrer", rec.Header().Get("Referrer-Policy"))
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestShareHandler_JSONCountsTheView(t *testing.T) {
+	router := newTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/s/public.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp sharedBookResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "book-123", resp.ID)
+	assert.Equal(t, []string{"go"}, resp.Tags)
+	assert.Equal(t, 1, resp.ViewCount)
+}
@@ -8,22 +8,37 @@ import (
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/auth"
 	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
 )
 
 type contextKey string
 
 const UserIDKey contextKey = "userID"
 
+// TokenAuthenticator resolves personal access tokens to their owner
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (*accesstoken.Token, error)
+}
+
 type AuthMiddleware struct {
-	auth *auth.Client
+	auth   *auth.Client
+	tokens TokenAuthenticator
 }
 
-func NewAuthMiddleware(app *firebase.App) (*AuthMiddleware, error) {
-	authClient, err := app.Auth(context.Background())
-	if err != nil {
-		return nil, err
+// NewAuthMiddleware creates the auth middleware. Firebase ID tokens are only
+// verified when app is not nil, and personal access tokens when tokens is not nil.
+func NewAuthMiddleware(app *firebase.App, tokens TokenAuthenticator) (*AuthMiddleware, error) {
+	m := &AuthMiddleware{tokens: tokens}
+	if app != nil {
+		authClient, err := app.Auth(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		m.auth = authClient
 	}
-	return &AuthMiddleware{auth: authClient}, nil
+	return m, nil
 }
 
 func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
@@ -40,10 +55,30 @@ func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Personal access tokens must be valid; unlike ID tokens they are never ignored
+		if accesstoken.IsPersonalAccessToken(tokenString) && m.tokens != nil {
+			token, err := m.tokens.Authenticate(r.Context(), tokenString)
+			if err != nil {
+				log.Warn().Err(err).Msg("Failed to authenticate personal access token")
+				http.Error(w, "invalid personal access token", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserIDKey, token.UserID)
+			ctx = accesstoken.WithScopes(ctx, token.Scopes)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		if m.auth == nil {
+			http.Error(w, "ID tokens are not accepted without Firebase", http.StatusUnauthorized)
+			return
+		}
+
 		token, err := m.auth.VerifyIDToken(r.Context(), tokenString)
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to verify ID token")
-			next.ServeHTTP(w, r)
+			log.Warn().Err(err).Msg("Failed to verify ID token")
+			http.Error(w, "invalid ID token", http.StatusUnauthorized)
 			return
 		}
 
@@ -55,4 +90,13 @@ func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 func GetUserID(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(UserIDKey).(string)
 	return userID, ok
-}
\ No newline at end of file
+}
+
+// RequireUserID returns the ID of the authenticated user, or an unauthorized
+// error when the request carries no valid token
+func RequireUserID(ctx context.Context) (string, error) {
+	if userID, ok := GetUserID(ctx); ok && userID != "" {
+		return userID, nil
+	}
+	return "", errs.Unauthorized("authentication required")
+}
@@ -0,0 +1,144 @@
+package accesstoken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// lastUsedResolution avoids a database write on every request made with a token
+const lastUsedResolution = time.Minute
+
+type UseCase struct {
+	tokenRepo accesstoken.Repository
+}
+
+func NewUseCase(tokenRepo accesstoken.Repository) *UseCase {
+	return &UseCase{
+		tokenRepo: tokenRepo,
+	}
+}
+
+// CreateToken creates a personal access token and returns it together with the
+// plaintext secret, which cannot be retrieved again
+func (uc *UseCase) CreateToken(ctx context.Context, userID, name string, scopes []accesstoken.Scope, expiresAt *time.Time) (*accesstoken.Token, string, error) {
+	if userID == "" {
+		return nil, "", errs.Unauthorized("user ID is required")
+	}
+	if err := requireInteractiveSession(ctx); err != nil {
+		return nil, "", err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", errs.Validation("name", "token name is required")
+	}
+	if len(scopes) == 0 {
+		return nil, "", errs.Validation("scopes", "at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, "", errs.Validation("scopes", fmt.Sprintf("invalid scope: %s", scope))
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", errs.Validation("expiresAt", "expiration must be in the future")
+	}
+
+	token, secret, err := accesstoken.NewToken(userID, name, dedupeScopes(scopes), expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.tokenRepo.Save(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("failed to save access token: %w", err)
+	}
+
+	return token, secret, nil
+}
+
+func (uc *UseCase) GetMyTokens(ctx context.Context, userID string) ([]*accesstoken.Token, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	tokens, err := uc.tokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (uc *UseCase) RevokeToken(ctx context.Context, id, userID string) error {
+	if id == "" {
+		return errs.Validation("id", "token ID is required")
+	}
+	if userID == "" {
+		return errs.Unauthorized("user ID is required")
+	}
+	if err := requireInteractiveSession(ctx); err != nil {
+		return err
+	}
+
+	if err := uc.tokenRepo.Revoke(ctx, id, userID); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate resolves a bearer secret to an active token and records its use
+func (uc *UseCase) Authenticate(ctx context.Context, secret string) (*accesstoken.Token, error) {
+	if !accesstoken.IsPersonalAccessToken(secret) {
+		return nil, errs.Unauthorized("not a personal access token")
+	}
+
+	token, err := uc.tokenRepo.FindByHash(ctx, accesstoken.Hash(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate access token: %w", err)
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) {
+		return nil, errs.Unauthorized("access token has expired or was revoked")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := uc.tokenRepo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			// Log error but don't fail the operation
+			log.Warn().Err(err).Str("token_id", token.ID).Msg("Failed to record access token usage")
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
+
+// requireInteractiveSession prevents personal access tokens from minting or
+// revoking other tokens
+func requireInteractiveSession(ctx context.Context) error {
+	if _, ok := accesstoken.ScopesFromContext(ctx); ok {
+		return errs.Unauthorized("personal access tokens cannot manage tokens")
+	}
+	return nil
+}
+
+func dedupeScopes(scopes []accesstoken.Scope) []accesstoken.Scope {
+	seen := make(map[accesstoken.Scope]bool)
+	var result []accesstoken.Scope
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+)
+
+// MockRepository implements accesstoken.Repository for testing
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, t *accesstoken.Token) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByHash(ctx context.Context, hash string) (*accesstoken.Token, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*accesstoken.Token), args.Error(1)
+}
+
+func (m *MockRepository) FindByUserID(ctx context.Context, userID string) ([]*accesstoken.Token, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*accesstoken.Token), args.Error(1)
+}
+
+func (m *MockRepository) Revoke(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func TestUseCase_CreateToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create token with deduplicated scopes", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo)
+
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*accesstoken.Token")).Return(nil)
+
+		token, secret, err := uc.CreateToken(ctx, "user-123", "cron job", []accesstoken.Scope{accesstoken.ScopeRead, accesstoken.ScopeRead}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "cron job", token.Name)
+		assert.Equal(t, []accesstoken.Scope{accesstoken.ScopeRead}, token.Scopes)
+		assert.Equal(t, accesstoken.Hash(secret), token.Hash)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error when scope is invalid", func(t *testing.T) {
+		uc := NewUseCase(new(MockRepository))
+
+		_, _, err := uc.CreateToken(ctx, "user-123", "cron", []accesstoken.Scope{"admin"}, nil)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid scope")
+	})
+
+	t.Run("tokens cannot create tokens", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo)
+		tokenCtx := accesstoken.WithScopes(ctx, []accesstoken.Scope{accesstoken.ScopeWrite})
+
+		_, _, err := uc.CreateToken(tokenCtx, "user-123", "cron", []accesstoken.Scope{accesstoken.ScopeRead}, nil)
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "Save")
+	})
+}
+
+func TestUseCase_Authenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("active token records usage", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo)
+
+		token, secret, err := accesstoken.NewToken("user-123", "cron", []accesstoken.Scope{accesstoken.ScopeRead}, nil)
+		require.NoError(t, err)
+
+		mockRepo.On("FindByHash", ctx, token.Hash).Return(token, nil)
+		mockRepo.On("TouchLastUsed", ctx, token.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+		result, err := uc.Authenticate(ctx, secret)
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-123", result.UserID)
+		assert.NotNil(t, result.LastUsedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("recently used token is not touched again", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo)
+
+		token, secret, err := accesstoken.NewToken("user-123", "cron", []accesstoken.Scope{accesstoken.ScopeRead}, nil)
+		require.NoError(t, err)
+		lastUsed := time.Now().Add(-time.Second)
+		token.LastUsedAt = &lastUsed
+
+		mockRepo.On("FindByHash", ctx, token.Hash).Return(token, nil)
+
+		_, err = uc.Authenticate(ctx, secret)
+
+		require.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "TouchLastUsed")
+	})
+
+	t.Run("revoked token is rejected", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo)
+
+		token, secret, err := accesstoken.NewToken("user-123", "cron", []accesstoken.Scope{accesstoken.ScopeRead}, nil)
+		require.NoError(t, err)
+		revokedAt := time.Now()
+		token.RevokedAt = &revokedAt
+
+		mockRepo.On("FindByHash", ctx, token.Hash).Return(token, nil)
+
+		_, err = uc.Authenticate(ctx, secret)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown token is rejected", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo)
+
+		mockRepo.On("FindByHash", ctx, mock.Anything).Return(nil, errors.New("access token not found"))
+
+		_, err := uc.Authenticate(ctx, accesstoken.Prefix+"unknown")
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,110 @@
+package annotation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+type UseCase struct {
+	annotationRepo annotation.Repository
+	bookRepo       book.Repository
+}
+
+func NewUseCase(annotationRepo annotation.Repository, bookRepo book.Repository) *UseCase {
+	return &UseCase{
+		annotationRepo: annotationRepo,
+		bookRepo:       bookRepo,
+	}
+}
+
+// CreateAnnotation highlights a passage of a book the user can read
+func (uc *UseCase) CreateAnnotation(ctx context.Context, userID, bookID string, start, end int, color annotation.Color, note string) (*annotation.Annotation, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if bookID == "" {
+		return nil, errs.Validation("bookId", "book ID is required")
+	}
+
+	b, err := uc.bookRepo.FindByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find book: %w", err)
+	}
+
+	a, err := annotation.NewAnnotation(userID, b.ID, b.Content, start, end, color, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.annotationRepo.Save(ctx, a); err != nil {
+		return nil, fmt.Errorf("failed to save annotation: %w", err)
+	}
+
+	return a, nil
+}
+
+// UpdateAnnotation changes the color or note of an annotation. Nil arguments
+// are left as they are.
+func (uc *UseCase) UpdateAnnotation(ctx context.Context, id, userID string, color *annotation.Color, note *string) (*annotation.Annotation, error) {
+	if id == "" {
+		return nil, errs.Validation("id", "annotation ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if color != nil && !color.IsValid() {
+		return nil, errs.Validation("color", "unknown annotation color")
+	}
+
+	a, err := uc.annotationRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find annotation: %w", err)
+	}
+
+	if color != nil {
+		a.Color = *color
+	}
+	if note != nil {
+		a.Note = *note
+	}
+
+	if err := uc.annotationRepo.Update(ctx, a); err != nil {
+		return nil, fmt.Errorf("failed to update annotation: %w", err)
+	}
+
+	return a, nil
+}
+
+func (uc *UseCase) DeleteAnnotation(ctx context.Context, id, userID string) error {
+	if id == "" {
+		return errs.Validation("id", "annotation ID is required")
+	}
+	if userID == "" {
+		return errs.Unauthorized("user ID is required")
+	}
+
+	if err := uc.annotationRepo.Delete(ctx, id, userID); err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+
+	return nil
+}
+
+// GetAnnotationsOfBooks returns the user's annotations on each of the books,
+// keyed by book ID and ordered by position
+func (uc *UseCase) GetAnnotationsOfBooks(ctx context.Context, bookIDs []string, userID string) (map[string][]*annotation.Annotation, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	annotations, err := uc.annotationRepo.FindByBookIDs(ctx, bookIDs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotations: %w", err)
+	}
+
+	return annotations, nil
+}
@@ -0,0 +1,156 @@
+package annotation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/book/booktest"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+)
+
+// MockAnnotationRepository implements annotation.Repository for testing
+type MockAnnotationRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnnotationRepository) Save(ctx context.Context, a *annotation.Annotation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) FindByID(ctx context.Context, id, userID string) (*annotation.Annotation, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string][]*annotation.Annotation, error) {
+	args := m.Called(ctx, bookIDs, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) FindAllByBookID(ctx context.Context, bookID string) ([]*annotation.Annotation, error) {
+	args := m.Called(ctx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) Update(ctx context.Context, a *annotation.Annotation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) UpdateAnchors(ctx context.Context, annotations []*annotation.Annotation) error {
+	args := m.Called(ctx, annotations)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) Delete(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func TestUseCase_CreateAnnotation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("highlight a passage of a readable book", func(t *testing.T) {
+		annotationRepo := new(MockAnnotationRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(annotationRepo, bookRepo)
+
+		bookRepo.On("FindByID", ctx, "book-123", "user-123").Return(&book.Book{ID: "book-123", Content: "Hello, world"}, nil)
+		annotationRepo.On("Save", ctx, mock.AnythingOfType("*annotation.Annotation")).Return(nil)
+
+		a, err := uc.CreateAnnotation(ctx, "user-123", "book-123", 7, 12, annotation.ColorGreen, "planet")
+
+		require.NoError(t, err)
+		assert.Equal(t, "world", a.Quote)
+		assert.Equal(t, annotation.ColorGreen, a.Color)
+		assert.Equal(t, "planet", a.Note)
+		annotationRepo.AssertExpectations(t)
+	})
+
+	t.Run("book the user cannot read", func(t *testing.T) {
+		annotationRepo := new(MockAnnotationRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(annotationRepo, bookRepo)
+
+		bookRepo.On("FindByID", ctx, "book-456", "user-123").Return(nil, book.ErrNotFound)
+
+		_, err := uc.CreateAnnotation(ctx, "user-123", "book-456", 0, 1, "", "")
+
+		assert.True(t, errors.Is(err, errs.ErrNotFound))
+		annotationRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("range outside the content", func(t *testing.T) {
+		annotationRepo := new(MockAnnotationRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(annotationRepo, bookRepo)
+
+		bookRepo.On("FindByID", ctx, "book-123", "user-123").Return(&book.Book{ID: "book-123", Content: "Hello"}, nil)
+
+		_, err := uc.CreateAnnotation(ctx, "user-123", "book-123", 2, 10, "", "")
+
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+		annotationRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUseCase_UpdateAnnotation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("change only the note", func(t *testing.T) {
+		annotationRepo := new(MockAnnotationRepository)
+		uc := NewUseCase(annotationRepo, new(booktest.MockRepository))
+		existing := &annotation.Annotation{ID: "ann-123", UserID: "user-123", Color: annotation.ColorBlue, Note: "old"}
+
+		annotationRepo.On("FindByID", ctx, "ann-123", "user-123").Return(existing, nil)
+		annotationRepo.On("Update", ctx, existing).Return(nil)
+
+		note := "new"
+		a, err := uc.UpdateAnnotation(ctx, "ann-123", "user-123", nil, &note)
+
+		require.NoError(t, err)
+		assert.Equal(t, "new", a.Note)
+		assert.Equal(t, annotation.ColorBlue, a.Color)
+	})
+
+	t.Run("unknown color", func(t *testing.T) {
+		annotationRepo := new(MockAnnotationRepository)
+		uc := NewUseCase(annotationRepo, new(booktest.MockRepository))
+
+		color := annotation.Color("orange")
+		_, err := uc.UpdateAnnotation(ctx, "ann-123", "user-123", &color, nil)
+
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+		annotationRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUseCase_DeleteAnnotation(t *testing.T) {
+	ctx := context.Background()
+	annotationRepo := new(MockAnnotationRepository)
+	uc := NewUseCase(annotationRepo, new(booktest.MockRepository))
+
+	annotationRepo.On("Delete", ctx, "ann-123", "user-123").Return(nil)
+
+	require.NoError(t, uc.DeleteAnnotation(ctx, "ann-123", "user-123"))
+
+	err := uc.DeleteAnnotation(ctx, "", "user-123")
+	assert.True(t, errors.Is(err, errs.ErrValidation))
+}
@@ -2,46 +2,76 @@ package book
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
 	"github.com/motoya-k/tsundoc/internal/domain/ai"
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
 	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/markdown"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+	"github.com/motoya-k/tsundoc/internal/domain/transaction"
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
 )
 
 type UseCase struct {
-	bookRepo  book.Repository
-	aiService ai.Service
+	bookRepo       book.Repository
+	annotationRepo annotation.Repository
+	tx             transaction.UnitOfWork
+	aiService      ai.Service
+	events         webhook.Publisher
+	renderer       markdown.Renderer
+	tagRepo        tag.Repository
 }
 
-func NewUseCase(bookRepo book.Repository, aiService ai.Service) *UseCase {
+// NewUseCase creates the book use case. annotationRepo may be nil, in which
+// case annotations are not re-anchored when content changes, events may be
+// nil, in which case no webhook events are published, renderer may be nil
+// when content is never rendered, and tagRepo may be nil, in which case tags
+// are normalized but tag synonyms are not applied.
+func NewUseCase(bookRepo book.Repository, annotationRepo annotation.Repository, tx transaction.UnitOfWork, aiService ai.Service, events webhook.Publisher, renderer markdown.Renderer, tagRepo tag.Repository) *UseCase {
 	return &UseCase{
-		bookRepo:  bookRepo,
-		aiService: aiService,
+		bookRepo:       bookRepo,
+		annotationRepo: annotationRepo,
+		tx:             tx,
+		aiService:      aiService,
+		events:         events,
+		renderer:       renderer,
+		tagRepo:        tagRepo,
 	}
 }
 
-func (uc *UseCase) SaveBook(ctx context.Context, userID, title, author, description, content, url string, tags []string) (*book.Book, error) {
+// SaveBook saves new content as a book. When workspaceID is set the book is
+// owned by that workspace and the user needs write access to it.
+func (uc *UseCase) SaveBook(ctx context.Context, userID, workspaceID, title, author, description, content, url string, tags []string) (*book.Book, error) {
 	if userID == "" {
-		return nil, fmt.Errorf("user ID is required")
+		return nil, errs.Unauthorized("user ID is required")
 	}
 
 	if content == "" {
-		return nil, fmt.Errorf("content is required")
+		return nil, errs.Validation("content", "content is required")
 	}
 
 	b := book.NewBook(userID, content)
+	b.WorkspaceID = workspaceID
 	
 	// Generate title using AI if not provided
 	if title == "" {
-		if uc.aiService != nil {
+		if uc.aiEnabled(ctx) {
 			generatedTitle, err := uc.aiService.GenerateTitle(ctx, content)
+			b.Enrichment = enrichment(b.Enrichment, err)
 			if err != nil {
 				// Log error but don't fail the operation
-				fmt.Printf("Warning: failed to generate title: %v\n", err)
+				log.Warn().Err(err).Msg("Failed to generate title")
 				title = "Untitled"
 			} else {
 				title = generatedTitle
+				uc.recordPrompt(b, ai.OperationGenerateTitle)
 			}
 		} else {
 			title = "Untitled"
@@ -50,13 +80,21 @@ func (uc *UseCase) SaveBook(ctx context.Context, userID, title, author, descript
 	
 	// Generate tags using AI if not provided
 	if len(tags) == 0 {
-		if uc.aiService != nil {
-			generatedTags, err := uc.aiService.GenerateTags(ctx, content)
+		if uc.aiEnabled(ctx) {
+			// Existing tags help the model stay consistent with the library
+			existingTags, err := uc.bookRepo.FindTags(ctx, userID, workspaceID, ai.MaxExistingTags)
+			if err != nil {
+				log.Warn().Err(err).Msg("Failed to get existing tags")
+			}
+
+			generatedTags, err := uc.aiService.GenerateTags(ctx, content, existingTags)
+			b.Enrichment = enrichment(b.Enrichment, err)
 			if err != nil {
 				// Log error but don't fail the operation
-				fmt.Printf("Warning: failed to generate tags: %v\n", err)
+				log.Warn().Err(err).Msg("Failed to generate tags")
 			} else {
-				tags = generatedTags
+				tags = ai.TagNames(ai.NormalizeTags(generatedTags, existingTags))
+				uc.recordPrompt(b, ai.OperationGenerateTags)
 			}
 		}
 	}
@@ -65,21 +103,23 @@ func (uc *UseCase) SaveBook(ctx context.Context, userID, title, author, descript
 	b.Author = author
 	b.Description = description
 	b.URL = url
-	b.Tags = tags
+	b.Tags = uc.canonicalTags(ctx, userID, tags)
 
 	if err := uc.bookRepo.Save(ctx, b); err != nil {
 		return nil, fmt.Errorf("failed to save book: %w", err)
 	}
 
+	uc.publish(ctx, webhook.NewBookEvent(webhook.EventBookCreated, userID, b))
+
 	return b, nil
 }
 
 func (uc *UseCase) GetBook(ctx context.Context, id, userID string) (*book.Book, error) {
 	if id == "" {
-		return nil, fmt.Errorf("book ID is required")
+		return nil, errs.Validation("id", "book ID is required")
 	}
 	if userID == "" {
-		return nil, fmt.Errorf("user ID is required")
+		return nil, errs.Unauthorized("user ID is required")
 	}
 
 	b, err := uc.bookRepo.FindByID(ctx, id, userID)
@@ -90,9 +130,24 @@ func (uc *UseCase) GetBook(ctx context.Context, id, userID string) (*book.Book,
 	return b, nil
 }
 
+// GetBooks returns one entry per id, in the same order; the entry is nil when
+// the book does not exist or the user cannot read it
+func (uc *UseCase) GetBooks(ctx context.Context, ids []string, userID string) ([]*book.Book, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	books, err := uc.bookRepo.FindByIDs(ctx, ids, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+
+	return books, nil
+}
+
 func (uc *UseCase) GetMyBooks(ctx context.Context, userID string, keyword string) ([]*book.Book, error) {
 	if userID == "" {
-		return nil, fmt.Errorf("user ID is required")
+		return nil, errs.Unauthorized("user ID is required")
 	}
 
 	books, err := uc.bookRepo.FindByUserID(ctx, userID, keyword)
@@ -103,18 +158,55 @@ func (uc *UseCase) GetMyBooks(ctx context.Context, userID string, keyword string
 	return books, nil
 }
 
-func (uc *UseCase) UpdateBook(ctx context.Context, id, userID, title, author, description, content, url string, tags []string) (*book.Book, error) {
+func (uc *UseCase) GetWorkspaceBooks(ctx context.Context, workspaceID, userID string, keyword string) ([]*book.Book, error) {
+	if workspaceID == "" {
+		return nil, errs.Validation("workspaceId", "workspace ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	books, err := uc.bookRepo.FindByWorkspaceID(ctx, workspaceID, userID, keyword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+
+	return books, nil
+}
+
+// UpdateBook saves changes to a book. When expectedVersion is set and the
+// book has moved past it, a *book.ConflictError is returned and nothing is
+// written. When the content changes, annotations on the book move with their
+// quoted passages.
+func (uc *UseCase) UpdateBook(ctx context.Context, id, userID, title, author, description, content, url string, tags []string, expectedVersion *int) (*book.Book, error) {
 	if id == "" {
-		return nil, fmt.Errorf("book ID is required")
+		return nil, errs.Validation("id", "book ID is required")
 	}
 	if userID == "" {
-		return nil, fmt.Errorf("user ID is required")
+		return nil, errs.Unauthorized("user ID is required")
 	}
 
 	b, err := uc.bookRepo.FindByID(ctx, id, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find book: %w", err)
 	}
+	if expectedVersion != nil && *expectedVersion != b.Version {
+		return nil, &book.ConflictError{Current: b}
+	}
+
+	contentChanged := b.Content != content
+	tags = uc.canonicalTags(ctx, userID, tags)
+
+	// Output edited by the user no longer comes from a prompt
+	if b.Title != title {
+		delete(b.PromptVersions, string(ai.OperationGenerateTitle))
+	}
+	if !equalTags(b.Tags, tags) {
+		delete(b.PromptVersions, string(ai.OperationGenerateTags))
+	}
+	if contentChanged {
+		delete(b.PromptVersions, string(ai.OperationMergeContents))
+	}
 
 	b.Title = title
 	b.Author = author
@@ -123,62 +215,94 @@ func (uc *UseCase) UpdateBook(ctx context.Context, id, userID, title, author, de
 	b.URL = url
 	b.Tags = tags
 
-	if err := uc.bookRepo.Update(ctx, b); err != nil {
-		return nil, fmt.Errorf("failed to update book: %w", err)
+	err = uc.tx.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.bookRepo.Update(ctx, b, userID); err != nil {
+			return fmt.Errorf("failed to update book: %w", err)
+		}
+		if contentChanged {
+			return uc.reanchorAnnotations(ctx, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	uc.publish(ctx, webhook.NewBookEvent(webhook.EventBookUpdated, userID, b))
+
 	return b, nil
 }
 
 func (uc *UseCase) DeleteBook(ctx context.Context, id, userID string) error {
 	if id == "" {
-		return fmt.Errorf("book ID is required")
+		return errs.Validation("id", "book ID is required")
 	}
 	if userID == "" {
-		return fmt.Errorf("user ID is required")
+		return errs.Unauthorized("user ID is required")
 	}
 
 	if err := uc.bookRepo.Delete(ctx, id, userID); err != nil {
 		return fmt.Errorf("failed to delete book: %w", err)
 	}
 
+	uc.publish(ctx, webhook.NewEvent(webhook.EventBookDeleted, userID, webhook.BookPayload{ID: id}))
+
 	return nil
 }
 
-func (uc *UseCase) MergeBooks(ctx context.Context, userID string, bookIDs []string) (*book.Book, error) {
+// MergeBooks combines books into a new one. expectedVersions is optional and
+// lines up with bookIDs; a book that has moved past its expected version, or
+// that changes while the merge is in progress, fails the merge with a
+// *book.ConflictError.
+func (uc *UseCase) MergeBooks(ctx context.Context, userID string, bookIDs []string, expectedVersions []int) (*book.Book, error) {
 	if userID == "" {
-		return nil, fmt.Errorf("user ID is required")
+		return nil, errs.Unauthorized("user ID is required")
 	}
 
 	if len(bookIDs) < 2 {
-		return nil, fmt.Errorf("at least 2 books are required for merging")
+		return nil, errs.Validation("bookIds", "at least 2 books are required for merging")
+	}
+	if expectedVersions != nil && len(expectedVersions) != len(bookIDs) {
+		return nil, errs.Validation("expectedVersions", "one expected version is required per book")
 	}
 
 	// Fetch all books to merge
-	var booksToMerge []*book.Book
+	booksToMerge, err := uc.bookRepo.FindByIDs(ctx, bookIDs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find books: %w", err)
+	}
+
 	var contents []string
 	var allTags []string
-	
-	for _, bookID := range bookIDs {
-		b, err := uc.bookRepo.FindByID(ctx, bookID, userID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find book %s: %w", bookID, err)
+
+	for i, b := range booksToMerge {
+		if b == nil {
+			return nil, fmt.Errorf("failed to find book %s: %w", bookIDs[i], book.ErrNotFound)
+		}
+		if i > 0 && b.WorkspaceID != booksToMerge[0].WorkspaceID {
+			return nil, errs.Validation("bookIds", "cannot merge books from different workspaces")
+		}
+		if expectedVersions != nil && expectedVersions[i] != b.Version {
+			return nil, &book.ConflictError{Current: b}
 		}
-		booksToMerge = append(booksToMerge, b)
 		contents = append(contents, b.Content)
 		allTags = append(allTags, b.Tags...)
 	}
 
 	// Merge contents using AI
 	var mergedContent string
-	if uc.aiService != nil {
+	var mergeEnrichment book.Enrichment
+	var generated []ai.Operation
+	if uc.aiEnabled(ctx) {
 		content, err := uc.aiService.MergeContents(ctx, contents)
+		mergeEnrichment = enrichment(mergeEnrichment, err)
 		if err != nil {
 			// Fallback to simple concatenation
-			fmt.Printf("Warning: failed to merge contents with AI: %v\n", err)
+			log.Warn().Err(err).Msg("Failed to merge contents with AI")
 			mergedContent = strings.Join(contents, "\n\n---\n\n")
 		} else {
 			mergedContent = content
+			generated = append(generated, ai.OperationMergeContents)
 		}
 	} else {
 		mergedContent = strings.Join(contents, "\n\n---\n\n")
@@ -186,39 +310,163 @@ func (uc *UseCase) MergeBooks(ctx context.Context, userID string, bookIDs []stri
 
 	// Generate title for merged content
 	var mergedTitle string
-	if uc.aiService != nil {
+	if uc.aiEnabled(ctx) {
 		title, err := uc.aiService.GenerateTitle(ctx, mergedContent)
+		mergeEnrichment = enrichment(mergeEnrichment, err)
 		if err != nil {
-			fmt.Printf("Warning: failed to generate title for merged book: %v\n", err)
+			log.Warn().Err(err).Msg("Failed to generate title for merged book")
 			mergedTitle = "Merged Book"
 		} else {
 			mergedTitle = title
+			generated = append(generated, ai.OperationGenerateTitle)
 		}
 	} else {
 		mergedTitle = "Merged Book"
 	}
 
-	// Deduplicate tags
-	uniqueTags := make(map[string]bool)
-	for _, tag := range allTags {
-		if tag != "" {
-			uniqueTags[tag] = true
-		}
-	}
-	
-	var finalTags []string
-	for tag := range uniqueTags {
-		finalTags = append(finalTags, tag)
-	}
-
 	// Create new merged book
 	mergedBook := book.NewBook(userID, mergedContent)
 	mergedBook.Title = mergedTitle
-	mergedBook.Tags = finalTags
+	mergedBook.Tags = uc.canonicalTags(ctx, userID, allTags)
+	mergedBook.WorkspaceID = booksToMerge[0].WorkspaceID
+	mergedBook.Enrichment = mergeEnrichment
+	mergedBook.MergedFrom = len(booksToMerge)
+	for _, operation := range generated {
+		uc.recordPrompt(mergedBook, operation)
+	}
+
+	// Writes for the merge go in one transaction. The AI calls above stay
+	// outside it so that it is not held open while waiting on the provider.
+	// The sources are read again so that edits made while waiting on the
+	// provider are not lost from the merge.
+	err = uc.tx.RunInTransaction(ctx, func(ctx context.Context) error {
+		current, err := uc.bookRepo.FindByIDs(ctx, bookIDs, userID)
+		if err != nil {
+			return fmt.Errorf("failed to find books: %w", err)
+		}
+		for i, b := range current {
+			if b == nil {
+				return fmt.Errorf("failed to find book %s: %w", bookIDs[i], book.ErrNotFound)
+			}
+			if b.Version != booksToMerge[i].Version {
+				return &book.ConflictError{Current: b}
+			}
+		}
 
-	if err := uc.bookRepo.Save(ctx, mergedBook); err != nil {
-		return nil, fmt.Errorf("failed to save merged book: %w", err)
+		if err := uc.bookRepo.Save(ctx, mergedBook); err != nil {
+			return fmt.Errorf("failed to save merged book: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	payload := webhook.NewBookPayload(mergedBook)
+	payload.SourceBookIDs = bookIDs
+	uc.publish(ctx, webhook.NewEvent(webhook.EventBookMerged, userID, payload))
+
 	return mergedBook, nil
-}
\ No newline at end of file
+}
+
+// enrichment adds the outcome of one AI call to the outcome so far: a single
+// failure fails the enrichment
+func enrichment(outcome book.Enrichment, err error) book.Enrichment {
+	if err != nil || outcome == book.EnrichmentFailed {
+		return book.EnrichmentFailed
+	}
+	return book.EnrichmentSucceeded
+}
+
+// reanchorAnnotations moves every user's annotations on b to where their
+// quotes now appear in its content
+func (uc *UseCase) reanchorAnnotations(ctx context.Context, b *book.Book) error {
+	if uc.annotationRepo == nil {
+		return nil
+	}
+
+	annotations, err := uc.annotationRepo.FindAllByBookID(ctx, b.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find annotations: %w", err)
+	}
+
+	var moved []*annotation.Annotation
+	for _, a := range annotations {
+		if a.Reanchor(b.Content) {
+			moved = append(moved, a)
+		}
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+
+	if err := uc.annotationRepo.UpdateAnchors(ctx, moved); err != nil {
+		return fmt.Errorf("failed to re-anchor annotations: %w", err)
+	}
+	return nil
+}
+
+// RenderContent parses the book's content as Markdown
+func (uc *UseCase) RenderContent(ctx context.Context, b *book.Book) (*markdown.Document, error) {
+	if uc.renderer == nil {
+		return nil, errors.New("markdown renderer is not configured")
+	}
+
+	doc, err := uc.renderer.Render(ctx, b.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render book %s: %w", b.ID, err)
+	}
+	return doc, nil
+}
+
+// recordPrompt notes on b the version of the prompt that generated part of
+// it, when the AI service versions its prompts
+func (uc *UseCase) recordPrompt(b *book.Book, operation ai.Operation) {
+	versioner, ok := uc.aiService.(ai.PromptVersioner)
+	if !ok {
+		return
+	}
+	if b.PromptVersions == nil {
+		b.PromptVersions = map[string]string{}
+	}
+	b.PromptVersions[string(operation)] = versioner.PromptVersion(operation)
+}
+
+// canonicalTags normalizes tags, replaces the user's tag synonyms with the
+// tags they stand for and drops duplicates
+func (uc *UseCase) canonicalTags(ctx context.Context, userID string, tags []string) []string {
+	var synonyms []*tag.Synonym
+	if uc.tagRepo != nil {
+		var err error
+		synonyms, err = uc.tagRepo.FindSynonyms(ctx, userID)
+		if err != nil {
+			// Log error but don't fail the operation
+			log.Warn().Err(err).Msg("Failed to get tag synonyms")
+		}
+	}
+	return tag.NewSynonyms(synonyms).Canonicalize(tags)
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// aiEnabled reports whether AI features can be used for the request.
+// Personal access tokens need the ai scope to trigger AI calls.
+func (uc *UseCase) aiEnabled(ctx context.Context) bool {
+	return uc.aiService != nil && accesstoken.Allows(ctx, accesstoken.ScopeAI)
+}
+
+func (uc *UseCase) publish(ctx context.Context, event webhook.Event) {
+	if uc.events != nil {
+		uc.events.Publish(ctx, event)
+	}
+}
@@ -8,78 +8,93 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/motoya-k/tsundoc/internal/domain/accesstoken"
+	"github.com/motoya-k/tsundoc/internal/domain/ai"
+	"github.com/motoya-k/tsundoc/internal/domain/annotation"
 	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/book/booktest"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/markdown"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+	"github.com/motoya-k/tsundoc/internal/domain/transaction"
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
 )
 
-// MockRepository implements book.Repository for testing
-type MockRepository struct {
+// MockAIService implements ai.Service for testing
+type MockAIService struct {
 	mock.Mock
 }
 
-func (m *MockRepository) Save(ctx context.Context, b *book.Book) error {
-	args := m.Called(ctx, b)
-	return args.Error(0)
+func (m *MockAIService) GenerateTitle(ctx context.Context, content string) (string, error) {
+	args := m.Called(ctx, content)
+	return args.String(0), args.Error(1)
 }
 
-func (m *MockRepository) FindByID(ctx context.Context, id, userID string) (*book.Book, error) {
-	args := m.Called(ctx, id, userID)
+func (m *MockAIService) GenerateTags(ctx context.Context, content string, existingTags []string) ([]ai.Tag, error) {
+	args := m.Called(ctx, content, existingTags)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*book.Book), args.Error(1)
+	return args.Get(0).([]ai.Tag), args.Error(1)
 }
 
-func (m *MockRepository) FindByUserID(ctx context.Context, userID, keyword string) ([]*book.Book, error) {
-	args := m.Called(ctx, userID, keyword)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).([]*book.Book), args.Error(1)
+func (m *MockAIService) SummarizeContent(ctx context.Context, content string) (string, error) {
+	args := m.Called(ctx, content)
+	return args.String(0), args.Error(1)
 }
 
-func (m *MockRepository) Update(ctx context.Context, b *book.Book) error {
-	args := m.Called(ctx, b)
-	return args.Error(0)
+func (m *MockAIService) MergeContents(ctx context.Context, contents []string) (string, error) {
+	args := m.Called(ctx, contents)
+	return args.String(0), args.Error(1)
 }
 
-func (m *MockRepository) Delete(ctx context.Context, id, userID string) error {
-	args := m.Called(ctx, id, userID)
-	return args.Error(0)
+// versionedAIService adds prompt versions to MockAIService
+type versionedAIService struct {
+	*MockAIService
 }
 
-// MockAIService implements ai.Service for testing
-type MockAIService struct {
-	mock.Mock
+func (s versionedAIService) PromptVersion(operation ai.Operation) string {
+	return string(operation) + "-v1"
 }
 
-func (m *MockAIService) GenerateTitle(ctx context.Context, content string) (string, error) {
-	args := m.Called(ctx, content)
-	return args.String(0), args.Error(1)
+// stubTagRepository serves a user's tag synonyms. Other methods are left to
+// the embedded nil interface and must not be called.
+type stubTagRepository struct {
+	tag.Repository
+	synonyms []*tag.Synonym
 }
 
-func (m *MockAIService) GenerateTags(ctx context.Context, content string) ([]string, error) {
-	args := m.Called(ctx, content)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).([]string), args.Error(1)
+func (r *stubTagRepository) FindSynonyms(ctx context.Context, userID string) ([]*tag.Synonym, error) {
+	return r.synonyms, nil
 }
 
-func (m *MockAIService) SummarizeContent(ctx context.Context, content string) (string, error) {
-	args := m.Called(ctx, content)
-	return args.String(0), args.Error(1)
+// MockPublisher implements webhook.Publisher for testing
+type MockPublisher struct {
+	mock.Mock
 }
 
-func (m *MockAIService) MergeContents(ctx context.Context, contents []string) (string, error) {
-	args := m.Called(ctx, contents)
-	return args.String(0), args.Error(1)
+func (m *MockPublisher) Publish(ctx context.Context, event webhook.Event) {
+	m.Called(ctx, event)
+}
+
+// MockRenderer implements markdown.Renderer for testing
+type MockRenderer struct {
+	mock.Mock
+}
+
+func (m *MockRenderer) Render(ctx context.Context, source string) (*markdown.Document, error) {
+	args := m.Called(ctx, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*markdown.Document), args.Error(1)
 }
 
 func TestUseCase_SaveBook(t *testing.T) {
 	ctx := context.Background()
-	mockRepo := new(MockRepository)
+	mockRepo := new(booktest.MockRepository)
 	mockAI := new(MockAIService)
-	uc := NewUseCase(mockRepo, mockAI)
+	uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 
 	t.Run("save book with AI generation", func(t *testing.T) {
 		userID := "user-123"
@@ -87,18 +102,26 @@ func TestUseCase_SaveBook(t *testing.T) {
 		
 		// Mock AI responses
 		mockAI.On("GenerateTitle", ctx, content).Return("GraphQL API Guide", nil)
-		mockAI.On("GenerateTags", ctx, content).Return([]string{"GraphQL", "API", "Tutorial"}, nil)
+		mockAI.On("GenerateTags", ctx, content, []string{"API", "Go"}).Return([]ai.Tag{
+			{Name: "GraphQL", Confidence: 0.9},
+			{Name: "api", Confidence: 0.95},
+			{Name: " Tutorial ", Confidence: 0.6},
+			{Name: "Cooking", Confidence: 0.1},
+		}, nil)
 		
 		// Mock repository save
+		mockRepo.On("FindTags", ctx, userID, "", ai.MaxExistingTags).Return([]string{"API", "Go"}, nil)
 		mockRepo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
 
-		result, err := uc.SaveBook(ctx, userID, "", "", "", content, "", nil)
+		result, err := uc.SaveBook(ctx, userID, "", "", "", "", content, "", nil)
 
 		require.NoError(t, err)
 		assert.Equal(t, "GraphQL API Guide", result.Title)
-		assert.Equal(t, []string{"GraphQL", "API", "Tutorial"}, result.Tags)
+		// Normalized, matched to the existing tags and most confident first
+		assert.Equal(t, []string{"API", "graphql", "tutorial"}, result.Tags)
 		assert.Equal(t, content, result.Content)
 		assert.Equal(t, userID, result.UserID)
+		assert.Equal(t, book.EnrichmentSucceeded, result.Enrichment)
 
 		mockRepo.AssertExpectations(t)
 		mockAI.AssertExpectations(t)
@@ -113,12 +136,13 @@ func TestUseCase_SaveBook(t *testing.T) {
 		// Mock repository save - AI should not be called
 		mockRepo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
 
-		result, err := uc.SaveBook(ctx, userID, title, "", "", content, "", tags)
+		result, err := uc.SaveBook(ctx, userID, "", title, "", "", content, "", tags)
 
 		require.NoError(t, err)
 		assert.Equal(t, title, result.Title)
 		assert.Equal(t, tags, result.Tags)
 		assert.Equal(t, content, result.Content)
+		assert.Equal(t, book.EnrichmentSkipped, result.Enrichment)
 
 		mockRepo.AssertExpectations(t)
 		// AI service should not have been called
@@ -127,13 +151,13 @@ func TestUseCase_SaveBook(t *testing.T) {
 	})
 
 	t.Run("error when userID is empty", func(t *testing.T) {
-		_, err := uc.SaveBook(ctx, "", "title", "", "", "content", "", nil)
+		_, err := uc.SaveBook(ctx, "", "", "title", "", "", "content", "", nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user ID is required")
 	})
 
 	t.Run("error when content is empty", func(t *testing.T) {
-		_, err := uc.SaveBook(ctx, "user-123", "title", "", "", "", "", nil)
+		_, err := uc.SaveBook(ctx, "user-123", "", "title", "", "", "", "", nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "content is required")
 	})
@@ -144,27 +168,83 @@ func TestUseCase_SaveBook(t *testing.T) {
 		
 		// Mock AI failures
 		mockAI.On("GenerateTitle", ctx, content).Return("", errors.New("AI error"))
-		mockAI.On("GenerateTags", ctx, content).Return([]string(nil), errors.New("AI error"))
+		mockAI.On("GenerateTags", ctx, content, []string{"API", "Go"}).Return([]ai.Tag(nil), errors.New("AI error"))
 		
 		// Mock repository save
 		mockRepo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
 
-		result, err := uc.SaveBook(ctx, userID, "", "", "", content, "", nil)
+		result, err := uc.SaveBook(ctx, userID, "", "", "", "", content, "", nil)
 
 		require.NoError(t, err)
 		assert.Equal(t, "Untitled", result.Title)
 		assert.Empty(t, result.Tags)
+		assert.Equal(t, book.EnrichmentFailed, result.Enrichment)
 
 		mockRepo.AssertExpectations(t)
 		mockAI.AssertExpectations(t)
 	})
+
+	t.Run("records prompt versions", func(t *testing.T) {
+		repo := new(booktest.MockRepository)
+		aiService := new(MockAIService)
+		uc := NewUseCase(repo, nil, transaction.None, versionedAIService{aiService}, nil, nil, nil)
+
+		aiService.On("GenerateTitle", ctx, "Some content").Return("A Title", nil)
+		aiService.On("GenerateTags", ctx, "Some content", []string(nil)).Return([]ai.Tag(nil), errors.New("AI error"))
+		repo.On("FindTags", ctx, "user-123", "", ai.MaxExistingTags).Return(nil, errors.New("db error"))
+		repo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
+
+		result, err := uc.SaveBook(ctx, "user-123", "", "", "", "", "Some content", "", nil)
+
+		require.NoError(t, err)
+		// Failed calls record no version
+		assert.Equal(t, map[string]string{"generate_title": "generate_title-v1"}, result.PromptVersions)
+	})
+
+	t.Run("applies tag synonyms to given and generated tags", func(t *testing.T) {
+		repo := new(booktest.MockRepository)
+		aiService := new(MockAIService)
+		tags := &stubTagRepository{synonyms: []*tag.Synonym{{Alias: "golang", Tag: "lang/go"}}}
+		uc := NewUseCase(repo, nil, transaction.None, aiService, nil, nil, tags)
+
+		repo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
+		repo.On("FindTags", ctx, "user-123", "", ai.MaxExistingTags).Return([]string{"lang/go"}, nil)
+		aiService.On("GenerateTags", ctx, "Some content", []string{"lang/go"}).Return([]ai.Tag{
+			{Name: "Golang", Confidence: 0.9},
+			{Name: "lang / go", Confidence: 0.8},
+		}, nil)
+
+		given, err := uc.SaveBook(ctx, "user-123", "", "A Title", "", "", "Some content", "", []string{"GoLang", " web ", "Lang/Go"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"lang/go", "web"}, given.Tags)
+
+		generated, err := uc.SaveBook(ctx, "user-123", "", "A Title", "", "", "Some content", "", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"lang/go"}, generated.Tags)
+	})
+
+	t.Run("token without ai scope skips AI generation", func(t *testing.T) {
+		repo := new(booktest.MockRepository)
+		aiService := new(MockAIService)
+		uc := NewUseCase(repo, nil, transaction.None, aiService, nil, nil, nil)
+		tokenCtx := accesstoken.WithScopes(ctx, []accesstoken.Scope{accesstoken.ScopeWrite})
+
+		repo.On("Save", tokenCtx, mock.AnythingOfType("*book.Book")).Return(nil)
+
+		result, err := uc.SaveBook(tokenCtx, "user-123", "", "", "", "", "Some content", "", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Untitled", result.Title)
+		aiService.AssertNotCalled(t, "GenerateTitle")
+		aiService.AssertNotCalled(t, "GenerateTags")
+	})
 }
 
 func TestUseCase_GetBook(t *testing.T) {
 	ctx := context.Background()
-	mockRepo := new(MockRepository)
+	mockRepo := new(booktest.MockRepository)
 	mockAI := new(MockAIService)
-	uc := NewUseCase(mockRepo, mockAI)
+	uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 
 	t.Run("get book successfully", func(t *testing.T) {
 		bookID := "book-123"
@@ -200,9 +280,9 @@ func TestUseCase_GetBook(t *testing.T) {
 
 func TestUseCase_GetMyBooks(t *testing.T) {
 	ctx := context.Background()
-	mockRepo := new(MockRepository)
+	mockRepo := new(booktest.MockRepository)
 	mockAI := new(MockAIService)
-	uc := NewUseCase(mockRepo, mockAI)
+	uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 
 	t.Run("get books successfully", func(t *testing.T) {
 		userID := "user-123"
@@ -228,11 +308,86 @@ func TestUseCase_GetMyBooks(t *testing.T) {
 	})
 }
 
+func TestUseCase_GetWorkspaceBooks(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(booktest.MockRepository)
+	mockAI := new(MockAIService)
+	uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
+
+	t.Run("get workspace books successfully", func(t *testing.T) {
+		expectedBooks := []*book.Book{
+			{ID: "book-1", UserID: "user-456", WorkspaceID: "ws-1", Title: "Shared Book"},
+		}
+
+		mockRepo.On("FindByWorkspaceID", ctx, "ws-1", "user-123", "").Return(expectedBooks, nil)
+
+		result, err := uc.GetWorkspaceBooks(ctx, "ws-1", "user-123", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, expectedBooks, result)
+	})
+
+	t.Run("error when workspace ID is empty", func(t *testing.T) {
+		_, err := uc.GetWorkspaceBooks(ctx, "", "user-123", "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "workspace ID is required")
+	})
+}
+
+// MockAnnotationRepository implements annotation.Repository for testing
+type MockAnnotationRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnnotationRepository) Save(ctx context.Context, a *annotation.Annotation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) FindByID(ctx context.Context, id, userID string) (*annotation.Annotation, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string][]*annotation.Annotation, error) {
+	args := m.Called(ctx, bookIDs, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) FindAllByBookID(ctx context.Context, bookID string) ([]*annotation.Annotation, error) {
+	args := m.Called(ctx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*annotation.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationRepository) Update(ctx context.Context, a *annotation.Annotation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) UpdateAnchors(ctx context.Context, annotations []*annotation.Annotation) error {
+	args := m.Called(ctx, annotations)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationRepository) Delete(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
 func TestUseCase_UpdateBook(t *testing.T) {
 	ctx := context.Background()
-	mockRepo := new(MockRepository)
+	mockRepo := new(booktest.MockRepository)
 	mockAI := new(MockAIService)
-	uc := NewUseCase(mockRepo, mockAI)
+	uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 
 	t.Run("update book successfully", func(t *testing.T) {
 		bookID := "book-123"
@@ -245,9 +400,9 @@ func TestUseCase_UpdateBook(t *testing.T) {
 		}
 
 		mockRepo.On("FindByID", ctx, bookID, userID).Return(originalBook, nil)
-		mockRepo.On("Update", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*book.Book"), userID).Return(nil)
 
-		result, err := uc.UpdateBook(ctx, bookID, userID, "New Title", "Author", "Description", "New content", "URL", []string{"tag1", "tag2"})
+		result, err := uc.UpdateBook(ctx, bookID, userID, "New Title", "Author", "Description", "New content", "URL", []string{"tag1", "tag2"}, nil)
 
 		require.NoError(t, err)
 		assert.Equal(t, "New Title", result.Title)
@@ -256,18 +411,115 @@ func TestUseCase_UpdateBook(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("edited output drops its prompt version", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, nil, nil, nil)
+		current := &book.Book{
+			ID:      "book-321",
+			UserID:  "user-123",
+			Title:   "Generated",
+			Tags:    []string{"go"},
+			Content: "content",
+			PromptVersions: map[string]string{
+				"generate_title": "abc",
+				"generate_tags":  "def",
+			},
+		}
+
+		mockRepo.On("FindByID", ctx, "book-321", "user-123").Return(current, nil)
+		mockRepo.On("Update", ctx, current, "user-123").Return(nil)
+
+		result, err := uc.UpdateBook(ctx, "book-321", "user-123", "Mine", "", "", "content", "", []string{"go"}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"generate_tags": "def"}, result.PromptVersions)
+	})
+
+	t.Run("applies tag synonyms", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		tags := &stubTagRepository{synonyms: []*tag.Synonym{{Alias: "golang", Tag: "lang/go"}}}
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, nil, nil, tags)
+		current := &book.Book{ID: "book-456", UserID: "user-123", Title: "Go", Content: "content"}
+
+		mockRepo.On("FindByID", ctx, "book-456", "user-123").Return(current, nil)
+		mockRepo.On("Update", ctx, current, "user-123").Return(nil)
+
+		result, err := uc.UpdateBook(ctx, "book-456", "user-123", "Go", "", "", "content", "", []string{"golang", "lang/go/generics"}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"lang/go", "lang/go/generics"}, result.Tags)
+	})
+
 	t.Run("error when book ID is empty", func(t *testing.T) {
-		_, err := uc.UpdateBook(ctx, "", "user-123", "title", "", "", "content", "", nil)
+		_, err := uc.UpdateBook(ctx, "", "user-123", "title", "", "", "content", "", nil, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "book ID is required")
 	})
+
+	t.Run("conflict when expected version is stale", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, nil, nil, nil)
+		current := &book.Book{ID: "book-456", UserID: "user-123", Title: "Theirs", Version: 3}
+
+		mockRepo.On("FindByID", ctx, "book-456", "user-123").Return(current, nil)
+
+		expected := 2
+		_, err := uc.UpdateBook(ctx, "book-456", "user-123", "Mine", "", "", "content", "", nil, &expected)
+
+		var conflict *book.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, "Theirs", conflict.Current.Title)
+		assert.True(t, errors.Is(err, errs.ErrConflict))
+		mockRepo.AssertNotCalled(t, "Update")
+	})
+
+	t.Run("annotations follow changed content", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		mockAnnotations := new(MockAnnotationRepository)
+		uc := NewUseCase(mockRepo, mockAnnotations, transaction.None, nil, nil, nil, nil)
+		current := &book.Book{ID: "book-789", UserID: "user-123", Content: "one two three"}
+
+		moved, err := annotation.NewAnnotation("user-123", "book-789", current.Content, 4, 7, "", "")
+		require.NoError(t, err)
+		removed, err := annotation.NewAnnotation("user-456", "book-789", current.Content, 8, 13, "", "")
+		require.NoError(t, err)
+		unchanged, err := annotation.NewAnnotation("user-123", "book-789", current.Content, 0, 3, "", "")
+		require.NoError(t, err)
+
+		mockRepo.On("FindByID", ctx, "book-789", "user-123").Return(current, nil)
+		mockRepo.On("Update", ctx, current, "user-123").Return(nil)
+		mockAnnotations.On("FindAllByBookID", ctx, "book-789").Return([]*annotation.Annotation{moved, removed, unchanged}, nil)
+		mockAnnotations.On("UpdateAnchors", ctx, []*annotation.Annotation{moved, removed}).Return(nil)
+
+		_, err = uc.UpdateBook(ctx, "book-789", "user-123", "", "", "", "one and two", "", nil, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 8, moved.Start)
+		assert.True(t, removed.Detached)
+		mockAnnotations.AssertExpectations(t)
+	})
+
+	t.Run("annotations untouched when content is unchanged", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		mockAnnotations := new(MockAnnotationRepository)
+		uc := NewUseCase(mockRepo, mockAnnotations, transaction.None, nil, nil, nil, nil)
+		current := &book.Book{ID: "book-789", UserID: "user-123", Content: "one two three"}
+
+		mockRepo.On("FindByID", ctx, "book-789", "user-123").Return(current, nil)
+		mockRepo.On("Update", ctx, current, "user-123").Return(nil)
+
+		_, err := uc.UpdateBook(ctx, "book-789", "user-123", "Renamed", "", "", "one two three", "", nil, nil)
+
+		require.NoError(t, err)
+		mockAnnotations.AssertNotCalled(t, "FindAllByBookID", mock.Anything, mock.Anything)
+	})
 }
 
 func TestUseCase_DeleteBook(t *testing.T) {
 	ctx := context.Background()
-	mockRepo := new(MockRepository)
+	mockRepo := new(booktest.MockRepository)
 	mockAI := new(MockAIService)
-	uc := NewUseCase(mockRepo, mockAI)
+	uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 
 	t.Run("delete book successfully", func(t *testing.T) {
 		bookID := "book-123"
@@ -292,9 +544,9 @@ func TestUseCase_MergeBooks(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("merge books successfully", func(t *testing.T) {
-		mockRepo := new(MockRepository)
+		mockRepo := new(booktest.MockRepository)
 		mockAI := new(MockAIService)
-		uc := NewUseCase(mockRepo, mockAI)
+		uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 		userID := "user-123"
 		bookIDs := []string{"book-1", "book-2"}
 		
@@ -314,13 +566,12 @@ func TestUseCase_MergeBooks(t *testing.T) {
 			Tags:    []string{"tag2", "tag3"},
 		}
 
-		mockRepo.On("FindByID", ctx, "book-1", userID).Return(book1, nil)
-		mockRepo.On("FindByID", ctx, "book-2", userID).Return(book2, nil)
+		mockRepo.On("FindByIDs", ctx, []string{"book-1", "book-2"}, userID).Return([]*book.Book{book1, book2}, nil)
 		mockAI.On("MergeContents", ctx, []string{"Content 1", "Content 2"}).Return("Merged Content", nil)
 		mockAI.On("GenerateTitle", ctx, "Merged Content").Return("Merged Title", nil)
 		mockRepo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
 
-		result, err := uc.MergeBooks(ctx, userID, bookIDs)
+		result, err := uc.MergeBooks(ctx, userID, bookIDs, nil)
 
 		require.NoError(t, err)
 		assert.Equal(t, "Merged Title", result.Title)
@@ -329,35 +580,102 @@ func TestUseCase_MergeBooks(t *testing.T) {
 		assert.Contains(t, result.Tags, "tag2")
 		assert.Contains(t, result.Tags, "tag3")
 		assert.Len(t, result.Tags, 3) // Deduplicated tags
+		assert.Equal(t, 2, result.MergedFrom)
+		assert.Equal(t, book.EnrichmentSucceeded, result.Enrichment)
 
 		mockRepo.AssertExpectations(t)
 		mockAI.AssertExpectations(t)
 	})
 
+	t.Run("error when books belong to different workspaces", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		mockAI := new(MockAIService)
+		uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
+		userID := "user-123"
+
+		book1 := &book.Book{ID: "book-1", UserID: userID, Content: "Content 1"}
+		book2 := &book.Book{ID: "book-2", UserID: userID, Content: "Content 2", WorkspaceID: "ws-1"}
+
+		mockRepo.On("FindByIDs", ctx, []string{"book-1", "book-2"}, userID).Return([]*book.Book{book1, book2}, nil)
+
+		_, err := uc.MergeBooks(ctx, userID, []string{"book-1", "book-2"}, nil)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "different workspaces")
+		mockRepo.AssertNotCalled(t, "Save")
+		mockAI.AssertNotCalled(t, "MergeContents")
+	})
+
 	t.Run("error when user ID is empty", func(t *testing.T) {
-		mockRepo := new(MockRepository)
+		mockRepo := new(booktest.MockRepository)
 		mockAI := new(MockAIService)
-		uc := NewUseCase(mockRepo, mockAI)
+		uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 		
-		_, err := uc.MergeBooks(ctx, "", []string{"book-1", "book-2"})
+		_, err := uc.MergeBooks(ctx, "", []string{"book-1", "book-2"}, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user ID is required")
 	})
 
 	t.Run("error when less than 2 books", func(t *testing.T) {
-		mockRepo := new(MockRepository)
+		mockRepo := new(booktest.MockRepository)
 		mockAI := new(MockAIService)
-		uc := NewUseCase(mockRepo, mockAI)
+		uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 		
-		_, err := uc.MergeBooks(ctx, "user-123", []string{"book-1"})
+		_, err := uc.MergeBooks(ctx, "user-123", []string{"book-1"}, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "at least 2 books are required for merging")
 	})
 
+	t.Run("conflict when an expected version is stale", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, nil, nil, nil)
+
+		mockRepo.On("FindByIDs", ctx, []string{"book-1", "book-2"}, "user-123").Return([]*book.Book{
+			{ID: "book-1", Content: "one", Version: 1},
+			{ID: "book-2", Content: "two", Version: 4},
+		}, nil)
+
+		_, err := uc.MergeBooks(ctx, "user-123", []string{"book-1", "book-2"}, []int{1, 3})
+
+		var conflict *book.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, "book-2", conflict.Current.ID)
+		mockRepo.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("conflict when a book changes during the merge", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, nil, nil, nil)
+
+		mockRepo.On("FindByIDs", ctx, []string{"book-1", "book-2"}, "user-123").Return([]*book.Book{
+			{ID: "book-1", Content: "one", Version: 1},
+			{ID: "book-2", Content: "two", Version: 1},
+		}, nil).Once()
+		mockRepo.On("FindByIDs", ctx, []string{"book-1", "book-2"}, "user-123").Return([]*book.Book{
+			{ID: "book-1", Content: "one, edited", Version: 2},
+			{ID: "book-2", Content: "two", Version: 1},
+		}, nil).Once()
+
+		_, err := uc.MergeBooks(ctx, "user-123", []string{"book-1", "book-2"}, nil)
+
+		var conflict *book.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, "one, edited", conflict.Current.Content)
+		mockRepo.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("error when expected versions do not line up", func(t *testing.T) {
+		uc := NewUseCase(new(booktest.MockRepository), nil, transaction.None, nil, nil, nil, nil)
+
+		_, err := uc.MergeBooks(ctx, "user-123", []string{"book-1", "book-2"}, []int{1})
+
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
 	t.Run("merge with AI failure fallback", func(t *testing.T) {
-		mockRepo := new(MockRepository)
+		mockRepo := new(booktest.MockRepository)
 		mockAI := new(MockAIService)
-		uc := NewUseCase(mockRepo, mockAI)
+		uc := NewUseCase(mockRepo, nil, transaction.None, mockAI, nil, nil, nil)
 		userID := "user-123"
 		bookIDs := []string{"book-1", "book-2"}
 		
@@ -373,21 +691,97 @@ func TestUseCase_MergeBooks(t *testing.T) {
 			Content: "Content 2",
 		}
 
-		mockRepo.On("FindByID", ctx, "book-1", userID).Return(book1, nil)
-		mockRepo.On("FindByID", ctx, "book-2", userID).Return(book2, nil)
+		mockRepo.On("FindByIDs", ctx, []string{"book-1", "book-2"}, userID).Return([]*book.Book{book1, book2}, nil)
 		mockAI.On("MergeContents", ctx, []string{"Content 1", "Content 2"}).Return("", errors.New("AI error"))
 		mockAI.On("GenerateTitle", ctx, mock.AnythingOfType("string")).Return("", errors.New("AI error"))
 		mockRepo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
 
-		result, err := uc.MergeBooks(ctx, userID, bookIDs)
+		result, err := uc.MergeBooks(ctx, userID, bookIDs, nil)
 
 		require.NoError(t, err)
 		assert.Equal(t, "Merged Book", result.Title)
 		// When AI fails, it should fall back to simple concatenation
 		expectedContent := "Content 1\n\n---\n\nContent 2"
 		assert.Equal(t, expectedContent, result.Content)
+		assert.Equal(t, book.EnrichmentFailed, result.Enrichment)
 
 		mockRepo.AssertExpectations(t)
 		mockAI.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+
+func TestUseCase_PublishesWebhookEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("save publishes book.created", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		mockEvents := new(MockPublisher)
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, mockEvents, nil, nil)
+
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
+		mockEvents.On("Publish", ctx, mock.MatchedBy(func(e webhook.Event) bool {
+			return e.Type == webhook.EventBookCreated && e.UserID == "user-123"
+		})).Return()
+
+		_, err := uc.SaveBook(ctx, "user-123", "", "Title", "", "", "content", "", []string{"go"})
+
+		require.NoError(t, err)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("merge publishes book.merged with source books", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		mockEvents := new(MockPublisher)
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, mockEvents, nil, nil)
+
+		mockRepo.On("FindByIDs", ctx, []string{"book-1", "book-2"}, "user-123").Return([]*book.Book{{ID: "book-1", Content: "one"}, {ID: "book-2", Content: "two"}}, nil)
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*book.Book")).Return(nil)
+		mockEvents.On("Publish", ctx, mock.MatchedBy(func(e webhook.Event) bool {
+			payload, ok := e.Data.(webhook.BookPayload)
+			return e.Type == webhook.EventBookMerged && ok && len(payload.SourceBookIDs) == 2
+		})).Return()
+
+		_, err := uc.MergeBooks(ctx, "user-123", []string{"book-1", "book-2"}, nil)
+
+		require.NoError(t, err)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("failed delete publishes nothing", func(t *testing.T) {
+		mockRepo := new(booktest.MockRepository)
+		mockEvents := new(MockPublisher)
+		uc := NewUseCase(mockRepo, nil, transaction.None, nil, mockEvents, nil, nil)
+
+		mockRepo.On("Delete", ctx, "book-123", "user-123").Return(errors.New("book not found"))
+
+		err := uc.DeleteBook(ctx, "book-123", "user-123")
+
+		assert.Error(t, err)
+		mockEvents.AssertNotCalled(t, "Publish")
+	})
+}
+
+func TestUseCase_RenderContent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("renders the book content", func(t *testing.T) {
+		mockRenderer := new(MockRenderer)
+		uc := NewUseCase(new(booktest.MockRepository), nil, transaction.None, nil, nil, mockRenderer, nil)
+		doc := &markdown.Document{PlainText: "Title"}
+
+		mockRenderer.On("Render", ctx, "# Title").Return(doc, nil)
+
+		result, err := uc.RenderContent(ctx, &book.Book{ID: "book-123", Content: "# Title"})
+
+		require.NoError(t, err)
+		assert.Same(t, doc, result)
+	})
+
+	t.Run("error without a renderer", func(t *testing.T) {
+		uc := NewUseCase(new(booktest.MockRepository), nil, transaction.None, nil, nil, nil, nil)
+
+		_, err := uc.RenderContent(ctx, &book.Book{ID: "book-123", Content: "# Title"})
+
+		assert.Error(t, err)
+	})
+}
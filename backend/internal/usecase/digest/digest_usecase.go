@@ -0,0 +1,302 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+)
+
+// ErrNoSender is returned when digests are sent without an email sender
+var ErrNoSender = errors.New("no email sender configured")
+
+type UseCase struct {
+	digestRepo      digest.Repository
+	bookRepo        book.Repository
+	readingRepo     reading.Repository
+	savedSearchRepo search.Repository
+	tagRepo         tag.Repository
+	renderer        digest.Renderer
+	sender          digest.Sender
+}
+
+// NewUseCase creates the digest use case. sender may be nil, in which case
+// digests can be previewed but not sent. savedSearchRepo may be nil, in which
+// case digests have no shelves, and so may tagRepo, in which case the tags of
+// saved searches are matched as written.
+func NewUseCase(digestRepo digest.Repository, bookRepo book.Repository, readingRepo reading.Repository, savedSearchRepo search.Repository, tagRepo tag.Repository, renderer digest.Renderer, sender digest.Sender) *UseCase {
+	return &UseCase{
+		digestRepo:      digestRepo,
+		bookRepo:        bookRepo,
+		readingRepo:     readingRepo,
+		savedSearchRepo: savedSearchRepo,
+		tagRepo:         tagRepo,
+		renderer:        renderer,
+		sender:          sender,
+	}
+}
+
+// GetSettings returns the user's digest settings, or the defaults when the
+// user never changed them
+func (uc *UseCase) GetSettings(ctx context.Context, userID string) (*digest.Settings, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	s, err := uc.digestRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, digest.ErrNotFound) {
+		return digest.NewSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest settings: %w", err)
+	}
+
+	return s, nil
+}
+
+// UpdateSettings changes the user's digest settings. Nil arguments are left
+// as they are.
+func (uc *UseCase) UpdateSettings(ctx context.Context, userID string, enabled *bool, email *string, frequency *digest.Frequency, timezone *string) (*digest.Settings, error) {
+	s, err := uc.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled != nil {
+		s.Enabled = *enabled
+	}
+	if email != nil {
+		s.Email = *email
+	}
+	if frequency != nil {
+		s.Frequency = *frequency
+	}
+	if timezone != nil {
+		s.Timezone = *timezone
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.digestRepo.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to save digest settings: %w", err)
+	}
+
+	return s, nil
+}
+
+// PreviewDigest builds and renders the digest the user would receive now,
+// whether or not they opted in
+func (uc *UseCase) PreviewDigest(ctx context.Context, userID string) (*digest.Preview, error) {
+	s, err := uc.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := uc.buildDigest(ctx, s, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	email, err := uc.renderer.Render(d, s.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	return &digest.Preview{Digest: d, Email: email}, nil
+}
+
+// SendDueDigests sends a digest to every user whose digest is due at now and
+// returns how many were sent. A failure for one user is logged and does not
+// stop the others. Empty digests are not sent but count as delivered, so
+// that the user is not checked again until the next period.
+func (uc *UseCase) SendDueDigests(ctx context.Context, now time.Time) (int, error) {
+	if uc.sender == nil {
+		return 0, ErrNoSender
+	}
+
+	settings, err := uc.digestRepo.FindEnabled(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get digest settings: %w", err)
+	}
+
+	sent := 0
+	for _, s := range settings {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		if !s.Due(now) {
+			continue
+		}
+
+		delivered, err := uc.send(ctx, s, now)
+		if err != nil {
+			log.Warn().Err(err).Str("user_id", s.UserID).Msg("Failed to send digest")
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// send builds and sends one user's digest, and reports whether an email went
+// out
+func (uc *UseCase) send(ctx context.Context, s *digest.Settings, now time.Time) (bool, error) {
+	d, err := uc.buildDigest(ctx, s, now)
+	if err != nil {
+		return false, err
+	}
+
+	if !d.IsEmpty() {
+		email, err := uc.renderer.Render(d, s.Email)
+		if err != nil {
+			return false, fmt.Errorf("failed to render digest: %w", err)
+		}
+		if err := uc.sender.Send(ctx, email); err != nil {
+			return false, fmt.Errorf("failed to send digest: %w", err)
+		}
+	}
+
+	if err := uc.digestRepo.MarkSent(ctx, s.UserID, now); err != nil {
+		return false, fmt.Errorf("failed to record digest delivery: %w", err)
+	}
+	return !d.IsEmpty(), nil
+}
+
+// buildDigest gathers the user's personal books for a digest sent at now
+func (uc *UseCase) buildDigest(ctx context.Context, s *digest.Settings, now time.Time) (*digest.Digest, error) {
+	books, err := uc.bookRepo.FindByUserID(ctx, s.UserID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+
+	ids := make([]string, len(books))
+	for i, b := range books {
+		ids[i] = b.ID
+	}
+	states, err := uc.readingRepo.FindByBookIDs(ctx, ids, s.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading states: %w", err)
+	}
+
+	d := &digest.Digest{
+		UserID:     s.UserID,
+		Since:      s.Since(now).In(s.Location()),
+		Until:      now.In(s.Location()),
+		NewBooks:   []*book.Book{},
+		Reminders:  []*book.Book{},
+		Resurfaced: []*book.Book{},
+	}
+	var forgotten []*book.Book
+	for _, b := range books {
+		state, started := states[b.ID]
+		if started && state.Status == reading.StatusUnread {
+			started = false
+		}
+
+		switch {
+		case !b.CreatedAt.Before(d.Since) && b.CreatedAt.Before(now):
+			if len(d.NewBooks) < digest.MaxNewBooks {
+				d.NewBooks = append(d.NewBooks, b)
+			}
+		case started && state.Status == reading.StatusReading && state.UpdatedAt.Before(now.Add(-digest.StalledAfter)):
+			d.Reminders = append(d.Reminders, b)
+		case !started && b.CreatedAt.Before(now.Add(-digest.ResurfaceAfter)):
+			forgotten = append(forgotten, b)
+		}
+	}
+	d.Resurfaced = resurface(forgotten, d.Since)
+
+	if d.Shelves, err = uc.buildShelves(ctx, s.UserID, d.Since, now); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// buildShelves lists, for each of the user's subscribed saved searches, the
+// matching books saved between since and now. Searches matching none are left
+// out.
+func (uc *UseCase) buildShelves(ctx context.Context, userID string, since, now time.Time) ([]*digest.Shelf, error) {
+	shelves := []*digest.Shelf{}
+	if uc.savedSearchRepo == nil {
+		return shelves, nil
+	}
+
+	searches, err := uc.savedSearchRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+
+	var synonyms tag.Synonyms
+	for _, saved := range searches {
+		if !saved.Subscribed {
+			continue
+		}
+
+		// Saved queries were valid when saved, but the query language may
+		// have changed since
+		q, err := search.Parse(saved.Query)
+		if err != nil {
+			log.Warn().Err(err).Str("saved_search_id", saved.ID).Msg("Skipping saved search in digest")
+			continue
+		}
+		if synonyms == nil && uc.tagRepo != nil {
+			list, err := uc.tagRepo.FindSynonyms(ctx, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tag synonyms: %w", err)
+			}
+			synonyms = tag.NewSynonyms(list)
+		}
+
+		// Searches return the newest books first, so the books saved in the
+		// period come first
+		books, err := uc.bookRepo.Search(ctx, userID, saved.WorkspaceID, q.ResolveTags(synonyms.Canonical), digest.MaxShelfBooks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search books: %w", err)
+		}
+
+		shelf := &digest.Shelf{Search: saved, Books: []*book.Book{}}
+		for _, b := range books {
+			if !b.CreatedAt.Before(since) && b.CreatedAt.Before(now) {
+				shelf.Books = append(shelf.Books, b)
+			}
+		}
+		if len(shelf.Books) > 0 {
+			shelves = append(shelves, shelf)
+		}
+	}
+
+	return shelves, nil
+}
+
+// resurface picks up to MaxResurfaced of books. The pick changes with every
+// digest period so that each digest brings up different books.
+func resurface(books []*book.Book, since time.Time) []*book.Book {
+	rank := func(b *book.Book) uint32 {
+		h := fnv.New32a()
+		h.Write([]byte(b.ID + since.UTC().Format(time.RFC3339)))
+		return h.Sum32()
+	}
+	sort.SliceStable(books, func(i, j int) bool {
+		return rank(books[i]) < rank(books[j])
+	})
+
+	if len(books) > digest.MaxResurfaced {
+		books = books[:digest.MaxResurfaced]
+	}
+	return append([]*book.Book{}, books...)
+}
@@ -0,0 +1,301 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/digest"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+)
+
+// stubDigestRepository keeps digest settings in memory
+type stubDigestRepository struct {
+	settings map[string]*digest.Settings
+}
+
+func (r *stubDigestRepository) FindByUserID(ctx context.Context, userID string) (*digest.Settings, error) {
+	s, ok := r.settings[userID]
+	if !ok {
+		return nil, digest.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *stubDigestRepository) FindEnabled(ctx context.Context) ([]*digest.Settings, error) {
+	var result []*digest.Settings
+	for _, s := range r.settings {
+		if s.Enabled {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (r *stubDigestRepository) Save(ctx context.Context, s *digest.Settings) error {
+	if r.settings == nil {
+		r.settings = map[string]*digest.Settings{}
+	}
+	r.settings[s.UserID] = s
+	return nil
+}
+
+func (r *stubDigestRepository) MarkSent(ctx context.Context, userID string, sentAt time.Time) error {
+	r.settings[userID].LastSentAt = &sentAt
+	return nil
+}
+
+// stubBookRepository serves each user's library from a map. Other methods
+// are left to the embedded nil interface and must not be called.
+type stubBookRepository struct {
+	book.Repository
+	books map[string][]*book.Book
+	// found are the books searches return, keyed by the first term of the
+	// query
+	found map[string][]*book.Book
+}
+
+func (r *stubBookRepository) FindByUserID(ctx context.Context, userID, keyword string) ([]*book.Book, error) {
+	return r.books[userID], nil
+}
+
+func (r *stubBookRepository) Search(ctx context.Context, userID, workspaceID string, q *search.Query, limit int) ([]*book.Book, error) {
+	switch t := q.Terms[0].(type) {
+	case *search.Tag:
+		return r.found[t.Tag], nil
+	case *search.Text:
+		return r.found[t.Text], nil
+	}
+	return nil, nil
+}
+
+// stubSavedSearchRepository serves a fixed list of saved searches
+type stubSavedSearchRepository struct {
+	search.Repository
+	searches []*search.SavedSearch
+}
+
+func (r *stubSavedSearchRepository) FindByUserID(ctx context.Context, userID string) ([]*search.SavedSearch, error) {
+	return r.searches, nil
+}
+
+type stubTagRepository struct {
+	tag.Repository
+	synonyms []*tag.Synonym
+}
+
+func (r *stubTagRepository) FindSynonyms(ctx context.Context, userID string) ([]*tag.Synonym, error) {
+	return r.synonyms, nil
+}
+
+// stubReadingRepository serves reading states keyed by book ID
+type stubReadingRepository struct {
+	reading.Repository
+	states map[string]*reading.State
+}
+
+func (r *stubReadingRepository) FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string]*reading.State, error) {
+	result := map[string]*reading.State{}
+	for _, id := range bookIDs {
+		if s, ok := r.states[id]; ok {
+			result[id] = s
+		}
+	}
+	return result, nil
+}
+
+// stubRenderer renders the titles of the new books into the subject
+type stubRenderer struct{}
+
+func (stubRenderer) Render(d *digest.Digest, to string) (*digest.Email, error) {
+	subject := ""
+	for _, b := range d.NewBooks {
+		subject += b.Title + ";"
+	}
+	return &digest.Email{To: to, Subject: subject}, nil
+}
+
+// stubSender records the emails it is asked to send, failing for the
+// addresses in fail
+type stubSender struct {
+	sent []*digest.Email
+	fail map[string]bool
+}
+
+func (s *stubSender) Send(ctx context.Context, email *digest.Email) error {
+	if s.fail[email.To] {
+		return errors.New("mailbox unavailable")
+	}
+	s.sent = append(s.sent, email)
+	return nil
+}
+
+func TestUseCase_UpdateSettings(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubDigestRepository{}
+	uc := NewUseCase(repo, &stubBookRepository{}, &stubReadingRepository{}, nil, nil, stubRenderer{}, nil)
+
+	t.Run("defaults", func(t *testing.T) {
+		s, err := uc.GetSettings(ctx, "user-123")
+
+		require.NoError(t, err)
+		assert.False(t, s.Enabled)
+		assert.Equal(t, digest.FrequencyWeekly, s.Frequency)
+		assert.Equal(t, "UTC", s.Timezone)
+	})
+
+	t.Run("opts in", func(t *testing.T) {
+		enabled := true
+		email := "reader@example.com"
+		frequency := digest.FrequencyDaily
+		timezone := "Asia/Tokyo"
+		s, err := uc.UpdateSettings(ctx, "user-123", &enabled, &email, &frequency, &timezone)
+
+		require.NoError(t, err)
+		assert.True(t, s.Enabled)
+		assert.Same(t, s, repo.settings["user-123"])
+	})
+
+	t.Run("keeps unset fields", func(t *testing.T) {
+		enabled := false
+		s, err := uc.UpdateSettings(ctx, "user-123", &enabled, nil, nil, nil)
+
+		require.NoError(t, err)
+		assert.False(t, s.Enabled)
+		assert.Equal(t, "reader@example.com", s.Email)
+		assert.Equal(t, "Asia/Tokyo", s.Timezone)
+	})
+
+	t.Run("invalid time zone", func(t *testing.T) {
+		timezone := "Mars/Olympus"
+		_, err := uc.UpdateSettings(ctx, "user-123", nil, nil, nil, &timezone)
+
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+	})
+
+	t.Run("requires a user", func(t *testing.T) {
+		_, err := uc.GetSettings(ctx, "")
+
+		assert.True(t, errors.Is(err, errs.ErrUnauthorized))
+	})
+}
+
+func TestUseCase_PreviewDigest(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	books := &stubBookRepository{books: map[string][]*book.Book{
+		"user-123": {
+			{ID: "new", Title: "New", CreatedAt: now.Add(-time.Hour)},
+			{ID: "stalled", Title: "Stalled", CreatedAt: now.AddDate(0, 0, -20)},
+			{ID: "active", Title: "Active", CreatedAt: now.AddDate(0, 0, -20)},
+			{ID: "forgotten", Title: "Forgotten", CreatedAt: now.AddDate(0, 0, -60)},
+			{ID: "finished", Title: "Finished", CreatedAt: now.AddDate(0, 0, -60)},
+		},
+	}}
+	states := &stubReadingRepository{states: map[string]*reading.State{
+		"stalled":  {BookID: "stalled", Status: reading.StatusReading, UpdatedAt: now.AddDate(0, 0, -10)},
+		"active":   {BookID: "active", Status: reading.StatusReading, UpdatedAt: now.AddDate(0, 0, -1)},
+		"finished": {BookID: "finished", Status: reading.StatusRead, UpdatedAt: now.AddDate(0, 0, -40)},
+	}}
+	uc := NewUseCase(&stubDigestRepository{}, books, states, nil, nil, stubRenderer{}, nil)
+
+	preview, err := uc.PreviewDigest(ctx, "user-123")
+	require.NoError(t, err)
+
+	titles := func(books []*book.Book) []string {
+		var result []string
+		for _, b := range books {
+			result = append(result, b.Title)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"New"}, titles(preview.Digest.NewBooks))
+	assert.Equal(t, []string{"Stalled"}, titles(preview.Digest.Reminders))
+	assert.Equal(t, []string{"Forgotten"}, titles(preview.Digest.Resurfaced))
+	assert.Equal(t, "New;", preview.Email.Subject)
+}
+
+func TestUseCase_PreviewDigest_Shelves(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fresh := &book.Book{ID: "fresh", Title: "Fresh", CreatedAt: now.Add(-time.Hour)}
+	old := &book.Book{ID: "old", Title: "Old", CreatedAt: now.AddDate(0, 0, -30)}
+	books := &stubBookRepository{found: map[string][]*book.Book{
+		"lang/go": {fresh, old},
+		"rust":    {old},
+		"draft":   {fresh},
+	}}
+	savedSearches := &stubSavedSearchRepository{searches: []*search.SavedSearch{
+		{ID: "go", Name: "Go", Query: "tag:golang", Subscribed: true},
+		{ID: "rust", Name: "Rust", Query: "rust", Subscribed: true},
+		{ID: "drafts", Name: "Drafts", Query: "draft"},
+	}}
+	tags := &stubTagRepository{synonyms: []*tag.Synonym{{Alias: "golang", Tag: "lang/go"}}}
+	uc := NewUseCase(&stubDigestRepository{}, books, &stubReadingRepository{}, savedSearches, tags, stubRenderer{}, nil)
+
+	preview, err := uc.PreviewDigest(ctx, "user-123")
+	require.NoError(t, err)
+
+	// Only subscribed searches with books saved in the period make a shelf,
+	// and tags go through the user's synonyms
+	require.Len(t, preview.Digest.Shelves, 1)
+	assert.Equal(t, "Go", preview.Digest.Shelves[0].Search.Name)
+	assert.Equal(t, []*book.Book{fresh}, preview.Digest.Shelves[0].Books)
+	assert.False(t, preview.Digest.IsEmpty())
+}
+
+func TestUseCase_SendDueDigests(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 6, 9, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	justNow := now.Add(-time.Minute)
+
+	repo := &stubDigestRepository{settings: map[string]*digest.Settings{
+		"due":      {UserID: "due", Enabled: true, Email: "due@example.com", Frequency: digest.FrequencyDaily, Timezone: "UTC", LastSentAt: &yesterday},
+		"sent":     {UserID: "sent", Enabled: true, Email: "sent@example.com", Frequency: digest.FrequencyDaily, Timezone: "UTC", LastSentAt: &justNow},
+		"empty":    {UserID: "empty", Enabled: true, Email: "empty@example.com", Frequency: digest.FrequencyDaily, Timezone: "UTC"},
+		"failing":  {UserID: "failing", Enabled: true, Email: "failing@example.com", Frequency: digest.FrequencyDaily, Timezone: "UTC"},
+		"disabled": {UserID: "disabled", Email: "disabled@example.com", Frequency: digest.FrequencyDaily, Timezone: "UTC"},
+	}}
+	newBook := func(id string) *book.Book {
+		return &book.Book{ID: id, Title: id, CreatedAt: now.Add(-time.Hour)}
+	}
+	books := &stubBookRepository{books: map[string][]*book.Book{
+		"due":      {newBook("due-book")},
+		"sent":     {newBook("sent-book")},
+		"failing":  {newBook("failing-book")},
+		"disabled": {newBook("disabled-book")},
+	}}
+	sender := &stubSender{fail: map[string]bool{"failing@example.com": true}}
+	uc := NewUseCase(repo, books, &stubReadingRepository{}, nil, nil, stubRenderer{}, sender)
+
+	sent, err := uc.SendDueDigests(ctx, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, sent)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "due@example.com", sender.sent[0].To)
+	assert.Equal(t, "due-book;", sender.sent[0].Subject)
+
+	// An empty digest is skipped until the next period, a failed one is
+	// retried on the next run
+	assert.Equal(t, now, *repo.settings["due"].LastSentAt)
+	assert.Equal(t, now, *repo.settings["empty"].LastSentAt)
+	assert.Nil(t, repo.settings["failing"].LastSentAt)
+	assert.Equal(t, justNow, *repo.settings["sent"].LastSentAt)
+
+	t.Run("without a sender", func(t *testing.T) {
+		uc := NewUseCase(repo, books, &stubReadingRepository{}, nil, nil, stubRenderer{}, nil)
+
+		_, err := uc.SendDueDigests(ctx, now)
+		assert.ErrorIs(t, err, ErrNoSender)
+	})
+}
@@ -0,0 +1,194 @@
+package link
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/link"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+)
+
+const (
+	// DefaultGraphDepth is how many links away from the tagged books the
+	// knowledge graph reaches when no depth is given
+	DefaultGraphDepth = 1
+	// MaxGraphDepth bounds the depth a client can ask for
+	MaxGraphDepth = 3
+	// MaxGraphNodes bounds the size of a knowledge graph. Expansion stops once
+	// it is reached.
+	MaxGraphNodes = 500
+)
+
+type UseCase struct {
+	linkRepo link.Repository
+	bookRepo book.Repository
+}
+
+func NewUseCase(linkRepo link.Repository, bookRepo book.Repository) *UseCase {
+	return &UseCase{
+		linkRepo: linkRepo,
+		bookRepo: bookRepo,
+	}
+}
+
+// GetOutgoingLinksOfBooks returns the links written in each of the books,
+// keyed by book ID and ordered by position
+func (uc *UseCase) GetOutgoingLinksOfBooks(ctx context.Context, bookIDs []string, userID string) (map[string][]*link.Link, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	links, err := uc.linkRepo.FindBySourceIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetBacklinksOfBooks returns the links pointing at each of the books, keyed
+// by book ID
+func (uc *UseCase) GetBacklinksOfBooks(ctx context.Context, bookIDs []string, userID string) (map[string][]*link.Link, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	links, err := uc.linkRepo.FindByTargetIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backlinks: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetUnresolvedLinks returns the links that match no book, in the user's
+// personal books or in a workspace when workspaceID is set
+func (uc *UseCase) GetUnresolvedLinks(ctx context.Context, userID, workspaceID string) ([]*link.Link, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	links, err := uc.linkRepo.FindUnresolved(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unresolved links: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetKnowledgeGraph starts from the books carrying tagName or one of its
+// descendants, or every book when tagName is empty, and follows links in
+// both directions up to depth steps. Books come from the user's personal
+// library, or from a workspace when workspaceID is set.
+func (uc *UseCase) GetKnowledgeGraph(ctx context.Context, userID, workspaceID, tagName string, depth int) (*link.Graph, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if depth < 0 || depth > MaxGraphDepth {
+		return nil, errs.Validation("depth", fmt.Sprintf("depth must be between 0 and %d", MaxGraphDepth))
+	}
+
+	var library []*book.Book
+	var err error
+	if workspaceID != "" {
+		library, err = uc.bookRepo.FindByWorkspaceID(ctx, workspaceID, userID, "")
+	} else {
+		library, err = uc.bookRepo.FindByUserID(ctx, userID, "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+
+	graph := &link.Graph{Nodes: []*book.Book{}, Edges: []*link.Edge{}}
+	included := map[string]bool{}
+	var frontier []string
+	for _, b := range library {
+		if len(graph.Nodes) == MaxGraphNodes {
+			break
+		}
+		if tagName == "" || tag.Tagged(b, tagName) {
+			graph.Nodes = append(graph.Nodes, b)
+			included[b.ID] = true
+			frontier = append(frontier, b.ID)
+		}
+	}
+
+	for step := 0; step < depth && len(frontier) > 0 && len(graph.Nodes) < MaxGraphNodes; step++ {
+		neighbors, err := uc.neighbors(ctx, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, id := range neighbors {
+			if !included[id] {
+				included[id] = true
+				next = append(next, id)
+			}
+		}
+		if room := MaxGraphNodes - len(graph.Nodes); len(next) > room {
+			next = next[:room]
+		}
+
+		books, err := uc.bookRepo.FindByIDs(ctx, next, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get books: %w", err)
+		}
+		frontier = frontier[:0]
+		for _, b := range books {
+			if b != nil {
+				graph.Nodes = append(graph.Nodes, b)
+				frontier = append(frontier, b.ID)
+			}
+		}
+	}
+
+	nodeIDs := make([]string, len(graph.Nodes))
+	inGraph := make(map[string]bool, len(graph.Nodes))
+	for i, b := range graph.Nodes {
+		nodeIDs[i] = b.ID
+		inGraph[b.ID] = true
+	}
+	outgoing, err := uc.linkRepo.FindBySourceIDs(ctx, nodeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+	for _, id := range nodeIDs {
+		seen := map[string]bool{}
+		for _, l := range outgoing[id] {
+			if inGraph[l.TargetID] && !seen[l.TargetID] {
+				seen[l.TargetID] = true
+				graph.Edges = append(graph.Edges, &link.Edge{SourceID: id, TargetID: l.TargetID})
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// neighbors returns the IDs of books linked from or to any of ids
+func (uc *UseCase) neighbors(ctx context.Context, ids []string) ([]string, error) {
+	outgoing, err := uc.linkRepo.FindBySourceIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+	incoming, err := uc.linkRepo.FindByTargetIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backlinks: %w", err)
+	}
+
+	var neighbors []string
+	for _, id := range ids {
+		for _, l := range outgoing[id] {
+			if l.Resolved() {
+				neighbors = append(neighbors, l.TargetID)
+			}
+		}
+		for _, l := range incoming[id] {
+			neighbors = append(neighbors, l.SourceID)
+		}
+	}
+	return neighbors, nil
+}
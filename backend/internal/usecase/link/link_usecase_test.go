@@ -0,0 +1,142 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/link"
+)
+
+// stubLinkRepository answers link lookups from a fixed list of links
+type stubLinkRepository struct {
+	links []*link.Link
+}
+
+func (r *stubLinkRepository) FindBySourceIDs(ctx context.Context, sourceIDs []string) (map[string][]*link.Link, error) {
+	result := map[string][]*link.Link{}
+	for _, id := range sourceIDs {
+		for _, l := range r.links {
+			if l.SourceID == id {
+				result[id] = append(result[id], l)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *stubLinkRepository) FindByTargetIDs(ctx context.Context, targetIDs []string) (map[string][]*link.Link, error) {
+	result := map[string][]*link.Link{}
+	for _, id := range targetIDs {
+		for _, l := range r.links {
+			if l.TargetID == id {
+				result[id] = append(result[id], l)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *stubLinkRepository) FindUnresolved(ctx context.Context, userID, workspaceID string) ([]*link.Link, error) {
+	var result []*link.Link
+	for _, l := range r.links {
+		if !l.Resolved() {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+// stubBookRepository serves a user's library from a list. Other methods are
+// left to the embedded nil interface and must not be called.
+type stubBookRepository struct {
+	book.Repository
+	books []*book.Book
+}
+
+func (r *stubBookRepository) FindByUserID(ctx context.Context, userID, keyword string) ([]*book.Book, error) {
+	return r.books, nil
+}
+
+func (r *stubBookRepository) FindByIDs(ctx context.Context, ids []string, userID string) ([]*book.Book, error) {
+	result := make([]*book.Book, len(ids))
+	for i, id := range ids {
+		for _, b := range r.books {
+			if b.ID == id {
+				result[i] = b
+			}
+		}
+	}
+	return result, nil
+}
+
+func TestUseCase_GetKnowledgeGraph(t *testing.T) {
+	ctx := context.Background()
+
+	// go-1 -> go-2 -> rust-1 -> rust-2, and rust-3 -> go-1
+	library := []*book.Book{
+		{ID: "go-1", Tags: []string{"Go"}},
+		{ID: "go-2", Tags: []string{"go/concurrency"}},
+		{ID: "rust-1", Tags: []string{"rust"}},
+		{ID: "rust-2", Tags: []string{"rust"}},
+		{ID: "rust-3", Tags: []string{"rust"}},
+	}
+	links := []*link.Link{
+		{SourceID: "go-1", Target: "Go 2", TargetID: "go-2"},
+		{SourceID: "go-1", Target: "go-2", TargetID: "go-2"},
+		{SourceID: "go-1", Target: "Missing"},
+		{SourceID: "go-2", Target: "Rust 1", TargetID: "rust-1"},
+		{SourceID: "rust-1", Target: "Rust 2", TargetID: "rust-2"},
+		{SourceID: "rust-3", Target: "Go 1", TargetID: "go-1"},
+	}
+	uc := NewUseCase(&stubLinkRepository{links: links}, &stubBookRepository{books: library})
+
+	nodeIDs := func(g *link.Graph) []string {
+		ids := make([]string, len(g.Nodes))
+		for i, b := range g.Nodes {
+			ids[i] = b.ID
+		}
+		return ids
+	}
+
+	t.Run("tagged books only", func(t *testing.T) {
+		// go/concurrency is under go
+		g, err := uc.GetKnowledgeGraph(ctx, "user-123", "", "GO", 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go-1", "go-2"}, nodeIDs(g))
+		// Two links to the same book make one edge, unresolved links none
+		assert.Equal(t, []*link.Edge{{SourceID: "go-1", TargetID: "go-2"}}, g.Edges)
+	})
+
+	t.Run("one step in both directions", func(t *testing.T) {
+		g, err := uc.GetKnowledgeGraph(ctx, "user-123", "", "go", 1)
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"go-1", "go-2", "rust-1", "rust-3"}, nodeIDs(g))
+		assert.ElementsMatch(t, []*link.Edge{
+			{SourceID: "go-1", TargetID: "go-2"},
+			{SourceID: "go-2", TargetID: "rust-1"},
+			{SourceID: "rust-3", TargetID: "go-1"},
+		}, g.Edges)
+	})
+
+	t.Run("depth out of range", func(t *testing.T) {
+		_, err := uc.GetKnowledgeGraph(ctx, "user-123", "", "go", MaxGraphDepth+1)
+
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+	})
+}
+
+func TestUseCase_GetUnresolvedLinks(t *testing.T) {
+	uc := NewUseCase(&stubLinkRepository{}, &stubBookRepository{})
+
+	_, err := uc.GetUnresolvedLinks(context.Background(), "", "")
+
+	assert.True(t, errors.Is(err, errs.ErrUnauthorized))
+}
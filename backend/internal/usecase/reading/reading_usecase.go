@@ -0,0 +1,176 @@
+package reading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+)
+
+type UseCase struct {
+	readingRepo reading.Repository
+	bookRepo    book.Repository
+}
+
+func NewUseCase(readingRepo reading.Repository, bookRepo book.Repository) *UseCase {
+	return &UseCase{
+		readingRepo: readingRepo,
+		bookRepo:    bookRepo,
+	}
+}
+
+// SetReadingStatus records where the user stands with reading a book they
+// can read. A nil progress keeps the current one.
+func (uc *UseCase) SetReadingStatus(ctx context.Context, bookID, userID string, status reading.Status, progress *int) (*reading.State, error) {
+	if bookID == "" {
+		return nil, errs.Validation("bookId", "book ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	b, err := uc.bookRepo.FindByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find book: %w", err)
+	}
+
+	states, err := uc.readingRepo.FindByBookIDs(ctx, []string{b.ID}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading state: %w", err)
+	}
+	state, ok := states[b.ID]
+	if !ok {
+		state = reading.NewState(userID, b.ID)
+	}
+
+	if err := state.SetStatus(status, progress, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.readingRepo.Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save reading state: %w", err)
+	}
+
+	return state, nil
+}
+
+// GetStatesOfBooks returns the user's reading state of each of the books,
+// keyed by book ID. Books the user never set a status on are unread.
+func (uc *UseCase) GetStatesOfBooks(ctx context.Context, bookIDs []string, userID string) (map[string]*reading.State, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	states, err := uc.readingRepo.FindByBookIDs(ctx, bookIDs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading states: %w", err)
+	}
+
+	for _, id := range bookIDs {
+		if _, ok := states[id]; !ok {
+			states[id] = reading.NewState(userID, id)
+		}
+	}
+	return states, nil
+}
+
+// FilterByStatus keeps the books the user has one of statuses on, in order.
+// No statuses keeps every book.
+func (uc *UseCase) FilterByStatus(ctx context.Context, books []*book.Book, userID string, statuses []reading.Status) ([]*book.Book, error) {
+	if len(statuses) == 0 {
+		return books, nil
+	}
+
+	wanted := make(map[reading.Status]bool, len(statuses))
+	for _, s := range statuses {
+		if !s.IsValid() {
+			return nil, errs.Validation("status", "unknown reading status")
+		}
+		wanted[s] = true
+	}
+
+	states, err := uc.GetStatesOfBooks(ctx, bookIDs(books), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []*book.Book{}
+	for _, b := range books {
+		if wanted[states[b.ID].Status] {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered, nil
+}
+
+// GetBacklog returns the unread books of the user's personal library, or of
+// a workspace when workspaceID is set, ordered by sortBy. A limit of 0
+// returns every unread book.
+func (uc *UseCase) GetBacklog(ctx context.Context, userID, workspaceID string, sortBy reading.BacklogSort, limit int) ([]*book.Book, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if sortBy == "" {
+		sortBy = reading.BacklogOldest
+	}
+	if sortBy != reading.BacklogOldest && sortBy != reading.BacklogShortest {
+		return nil, errs.Validation("sort", "unknown backlog sort")
+	}
+	if limit < 0 {
+		return nil, errs.Validation("limit", "limit must not be negative")
+	}
+
+	var library []*book.Book
+	var err error
+	if workspaceID != "" {
+		library, err = uc.bookRepo.FindByWorkspaceID(ctx, workspaceID, userID, "")
+	} else {
+		library, err = uc.bookRepo.FindByUserID(ctx, userID, "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+
+	backlog, err := uc.FilterByStatus(ctx, library, userID, []reading.Status{reading.StatusUnread})
+	if err != nil {
+		return nil, err
+	}
+
+	minutes := make(map[string]int, len(backlog))
+	for _, b := range backlog {
+		minutes[b.ID] = reading.EstimateMinutes(b.Content)
+	}
+	day := func(b *book.Book) time.Time {
+		return b.CreatedAt.UTC().Truncate(24 * time.Hour)
+	}
+	sort.SliceStable(backlog, func(i, j int) bool {
+		a, b := backlog[i], backlog[j]
+		if sortBy == reading.BacklogShortest && minutes[a.ID] != minutes[b.ID] {
+			return minutes[a.ID] < minutes[b.ID]
+		}
+		if !day(a).Equal(day(b)) {
+			return day(a).Before(day(b))
+		}
+		if minutes[a.ID] != minutes[b.ID] {
+			return minutes[a.ID] < minutes[b.ID]
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	if limit > 0 && len(backlog) > limit {
+		backlog = backlog[:limit]
+	}
+	return backlog, nil
+}
+
+func bookIDs(books []*book.Book) []string {
+	ids := make([]string, len(books))
+	for i, b := range books {
+		ids[i] = b.ID
+	}
+	return ids
+}
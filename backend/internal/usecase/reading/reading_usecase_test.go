@@ -0,0 +1,173 @@
+package reading
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/reading"
+)
+
+// stubReadingRepository keeps reading states in memory
+type stubReadingRepository struct {
+	states map[string]*reading.State
+}
+
+func (r *stubReadingRepository) Save(ctx context.Context, s *reading.State) error {
+	if r.states == nil {
+		r.states = map[string]*reading.State{}
+	}
+	r.states[s.BookID+"/"+s.UserID] = s
+	return nil
+}
+
+func (r *stubReadingRepository) FindByBookIDs(ctx context.Context, bookIDs []string, userID string) (map[string]*reading.State, error) {
+	result := map[string]*reading.State{}
+	for _, id := range bookIDs {
+		if s, ok := r.states[id+"/"+userID]; ok {
+			result[id] = s
+		}
+	}
+	return result, nil
+}
+
+// stubBookRepository serves a user's library from a list. Other methods are
+// left to the embedded nil interface and must not be called.
+type stubBookRepository struct {
+	book.Repository
+	books []*book.Book
+}
+
+func (r *stubBookRepository) FindByUserID(ctx context.Context, userID, keyword string) ([]*book.Book, error) {
+	return r.books, nil
+}
+
+func (r *stubBookRepository) FindByID(ctx context.Context, id, userID string) (*book.Book, error) {
+	for _, b := range r.books {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, book.ErrNotFound
+}
+
+func TestUseCase_SetReadingStatus(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubReadingRepository{}
+	uc := NewUseCase(repo, &stubBookRepository{books: []*book.Book{{ID: "book-1"}}})
+
+	t.Run("starts reading", func(t *testing.T) {
+		progress := 10
+		state, err := uc.SetReadingStatus(ctx, "book-1", "user-123", reading.StatusReading, &progress)
+
+		require.NoError(t, err)
+		assert.Equal(t, reading.StatusReading, state.Status)
+		assert.Equal(t, 10, state.Progress)
+		assert.NotNil(t, state.StartedAt)
+		assert.Same(t, state, repo.states["book-1/user-123"])
+	})
+
+	t.Run("continues from the saved state", func(t *testing.T) {
+		state, err := uc.SetReadingStatus(ctx, "book-1", "user-123", reading.StatusRead, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 100, state.Progress)
+		assert.NotNil(t, state.FinishedAt)
+	})
+
+	t.Run("book not found", func(t *testing.T) {
+		_, err := uc.SetReadingStatus(ctx, "book-2", "user-123", reading.StatusRead, nil)
+
+		assert.True(t, errors.Is(err, book.ErrNotFound))
+	})
+
+	t.Run("invalid progress", func(t *testing.T) {
+		progress := 150
+		_, err := uc.SetReadingStatus(ctx, "book-1", "user-123", reading.StatusReading, &progress)
+
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.SetReadingStatus(ctx, "book-1", "", reading.StatusRead, nil)
+
+		assert.True(t, errors.Is(err, errs.ErrUnauthorized))
+	})
+}
+
+func TestUseCase_FilterByStatus(t *testing.T) {
+	ctx := context.Background()
+	books := []*book.Book{{ID: "book-1"}, {ID: "book-2"}, {ID: "book-3"}}
+	repo := &stubReadingRepository{}
+	uc := NewUseCase(repo, &stubBookRepository{books: books})
+
+	_, err := uc.SetReadingStatus(ctx, "book-2", "user-123", reading.StatusReading, nil)
+	require.NoError(t, err)
+	_, err = uc.SetReadingStatus(ctx, "book-3", "user-123", reading.StatusRead, nil)
+	require.NoError(t, err)
+
+	filtered, err := uc.FilterByStatus(ctx, books, "user-123", []reading.Status{reading.StatusUnread, reading.StatusRead})
+	require.NoError(t, err)
+	assert.Equal(t, []*book.Book{books[0], books[2]}, filtered)
+
+	// Another user has not started any of them
+	filtered, err = uc.FilterByStatus(ctx, books, "user-456", []reading.Status{reading.StatusUnread})
+	require.NoError(t, err)
+	assert.Equal(t, books, filtered)
+
+	_, err = uc.FilterByStatus(ctx, books, "user-123", []reading.Status{"skimmed"})
+	assert.True(t, errors.Is(err, errs.ErrValidation))
+}
+
+func TestUseCase_GetBacklog(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	long := strings.Repeat("word ", 1000)
+
+	library := []*book.Book{
+		{ID: "new-short", Content: "short", CreatedAt: day.AddDate(0, 0, 2)},
+		{ID: "old-long", Content: long, CreatedAt: day},
+		{ID: "old-short", Content: "short", CreatedAt: day.Add(time.Hour)},
+		{ID: "read", Content: "short", CreatedAt: day.AddDate(0, 0, -1)},
+	}
+	uc := NewUseCase(&stubReadingRepository{}, &stubBookRepository{books: library})
+	_, err := uc.SetReadingStatus(ctx, "read", "user-123", reading.StatusRead, nil)
+	require.NoError(t, err)
+
+	ids := func(books []*book.Book) []string {
+		result := make([]string, len(books))
+		for i, b := range books {
+			result[i] = b.ID
+		}
+		return result
+	}
+
+	t.Run("oldest first, quickest first on the same day", func(t *testing.T) {
+		backlog, err := uc.GetBacklog(ctx, "user-123", "", "", 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"old-short", "old-long", "new-short"}, ids(backlog))
+	})
+
+	t.Run("shortest first", func(t *testing.T) {
+		backlog, err := uc.GetBacklog(ctx, "user-123", "", reading.BacklogShortest, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"old-short", "new-short"}, ids(backlog))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := uc.GetBacklog(ctx, "user-123", "", "newest", 0)
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+
+		_, err = uc.GetBacklog(ctx, "user-123", "", "", -1)
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+	})
+}
@@ -0,0 +1,179 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+type UseCase struct {
+	savedSearchRepo search.Repository
+	bookRepo        book.Repository
+	tagRepo         tag.Repository
+}
+
+// NewUseCase creates the search use case. tagRepo may be nil, in which case
+// tags in queries are matched as written rather than through the user's tag
+// synonyms.
+func NewUseCase(savedSearchRepo search.Repository, bookRepo book.Repository, tagRepo tag.Repository) *UseCase {
+	return &UseCase{
+		savedSearchRepo: savedSearchRepo,
+		bookRepo:        bookRepo,
+		tagRepo:         tagRepo,
+	}
+}
+
+// SearchBooks returns the user's personal books, or a workspace's books when
+// workspaceID is set, matching the query, newest first. A limit of 0 uses the
+// default.
+func (uc *UseCase) SearchBooks(ctx context.Context, userID, workspaceID, input string, limit int) ([]*book.Book, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if limit < 0 {
+		return nil, errs.Validation("limit", "limit must not be negative")
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	q, err := search.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	q, err = uc.resolveTags(ctx, userID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := uc.bookRepo.Search(ctx, userID, workspaceID, q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search books: %w", err)
+	}
+
+	return books, nil
+}
+
+// SavedSearchBooks returns the books matching a saved search, as its owner
+// sees them
+func (uc *UseCase) SavedSearchBooks(ctx context.Context, s *search.SavedSearch, limit int) ([]*book.Book, error) {
+	return uc.SearchBooks(ctx, s.UserID, s.WorkspaceID, s.Query, limit)
+}
+
+// resolveTags replaces the tags of the query that are aliases of the user's
+// with the tags they stand for
+func (uc *UseCase) resolveTags(ctx context.Context, userID string, q *search.Query) (*search.Query, error) {
+	if uc.tagRepo == nil {
+		return q, nil
+	}
+
+	synonyms, err := uc.tagRepo.FindSynonyms(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag synonyms: %w", err)
+	}
+
+	return q.ResolveTags(tag.NewSynonyms(synonyms).Canonical), nil
+}
+
+func (uc *UseCase) GetSavedSearches(ctx context.Context, userID string) ([]*search.SavedSearch, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	searches, err := uc.savedSearchRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+func (uc *UseCase) GetSavedSearch(ctx context.Context, id, userID string) (*search.SavedSearch, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	s, err := uc.savedSearchRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	return s, nil
+}
+
+// CreateSavedSearch saves a query of the user's personal books, or of a
+// workspace's books when workspaceID is set, under a name
+func (uc *UseCase) CreateSavedSearch(ctx context.Context, userID, workspaceID, name, query string, pinned, subscribed bool) (*search.SavedSearch, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	s, err := search.NewSavedSearch(userID, workspaceID, name, query)
+	if err != nil {
+		return nil, err
+	}
+	s.Pinned = pinned
+	s.Subscribed = subscribed
+
+	if err := uc.savedSearchRepo.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to save saved search: %w", err)
+	}
+
+	return s, nil
+}
+
+// UpdateSavedSearch changes a saved search of the user's. Nil arguments are
+// left as they are.
+func (uc *UseCase) UpdateSavedSearch(ctx context.Context, id, userID string, name, query *string, pinned, subscribed *bool) (*search.SavedSearch, error) {
+	s, err := uc.GetSavedSearch(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		s.Name = strings.TrimSpace(*name)
+	}
+	if query != nil {
+		s.Query = strings.TrimSpace(*query)
+	}
+	if pinned != nil {
+		s.Pinned = *pinned
+	}
+	if subscribed != nil {
+		s.Subscribed = *subscribed
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.savedSearchRepo.Update(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+
+	return s, nil
+}
+
+func (uc *UseCase) DeleteSavedSearch(ctx context.Context, id, userID string) error {
+	if userID == "" {
+		return errs.Unauthorized("user ID is required")
+	}
+
+	if err := uc.savedSearchRepo.Delete(ctx, id, userID); err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+
+	return nil
+}
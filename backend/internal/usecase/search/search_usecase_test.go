@@ -0,0 +1,178 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/search"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+)
+
+// stubBookRepository records the last search it ran
+type stubBookRepository struct {
+	book.Repository
+	userID, workspaceID string
+	query               *search.Query
+	limit               int
+}
+
+func (r *stubBookRepository) Search(ctx context.Context, userID, workspaceID string, q *search.Query, limit int) ([]*book.Book, error) {
+	r.userID, r.workspaceID, r.query, r.limit = userID, workspaceID, q, limit
+	return []*book.Book{{ID: "book-1"}}, nil
+}
+
+type stubTagRepository struct {
+	tag.Repository
+	synonyms []*tag.Synonym
+}
+
+func (r *stubTagRepository) FindSynonyms(ctx context.Context, userID string) ([]*tag.Synonym, error) {
+	return r.synonyms, nil
+}
+
+// stubSavedSearchRepository keeps saved searches in memory
+type stubSavedSearchRepository struct {
+	searches map[string]*search.SavedSearch
+}
+
+func newStubSavedSearchRepository() *stubSavedSearchRepository {
+	return &stubSavedSearchRepository{searches: map[string]*search.SavedSearch{}}
+}
+
+func (r *stubSavedSearchRepository) Save(ctx context.Context, s *search.SavedSearch) error {
+	r.searches[s.ID] = s
+	return nil
+}
+
+func (r *stubSavedSearchRepository) FindByID(ctx context.Context, id, userID string) (*search.SavedSearch, error) {
+	s, ok := r.searches[id]
+	if !ok || s.UserID != userID {
+		return nil, search.ErrNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *stubSavedSearchRepository) FindByUserID(ctx context.Context, userID string) ([]*search.SavedSearch, error) {
+	var result []*search.SavedSearch
+	for _, s := range r.searches {
+		if s.UserID == userID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (r *stubSavedSearchRepository) Update(ctx context.Context, s *search.SavedSearch) error {
+	if _, err := r.FindByID(ctx, s.ID, s.UserID); err != nil {
+		return err
+	}
+	r.searches[s.ID] = s
+	return nil
+}
+
+func (r *stubSavedSearchRepository) Delete(ctx context.Context, id, userID string) error {
+	if _, err := r.FindByID(ctx, id, userID); err != nil {
+		return err
+	}
+	delete(r.searches, id)
+	return nil
+}
+
+func TestUseCase_SearchBooks(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &stubBookRepository{}
+	tagRepo := &stubTagRepository{synonyms: []*tag.Synonym{{Alias: "golang", Tag: "lang/go"}}}
+	uc := NewUseCase(newStubSavedSearchRepository(), bookRepo, tagRepo)
+
+	books, err := uc.SearchBooks(ctx, "user-123", "workspace-1", "tag:GoLang -tag:draft", 0)
+	require.NoError(t, err)
+	assert.Len(t, books, 1)
+	assert.Equal(t, "user-123", bookRepo.userID)
+	assert.Equal(t, "workspace-1", bookRepo.workspaceID)
+	assert.Equal(t, defaultLimit, bookRepo.limit)
+	// Tags are resolved through the user's synonyms
+	assert.Equal(t, []search.Term{
+		&search.Tag{Tag: "lang/go"},
+		&search.Not{Term: &search.Tag{Tag: "draft"}},
+	}, bookRepo.query.Terms)
+
+	_, err = uc.SearchBooks(ctx, "user-123", "", "go", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, maxLimit, bookRepo.limit)
+
+	t.Run("without tag synonyms", func(t *testing.T) {
+		uc := NewUseCase(newStubSavedSearchRepository(), bookRepo, nil)
+		_, err := uc.SearchBooks(ctx, "user-123", "", "tag:golang", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []search.Term{&search.Tag{Tag: "golang"}}, bookRepo.query.Terms)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := uc.SearchBooks(ctx, "user-123", "", `"unclosed`, 10)
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		_, err := uc.SearchBooks(ctx, "user-123", "", "go", -1)
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.SearchBooks(ctx, "", "", "go", 10)
+		assert.ErrorIs(t, err, errs.ErrUnauthorized)
+	})
+}
+
+func TestUseCase_SavedSearches(t *testing.T) {
+	ctx := context.Background()
+	repo := newStubSavedSearchRepository()
+	bookRepo := &stubBookRepository{}
+	uc := NewUseCase(repo, bookRepo, nil)
+
+	s, err := uc.CreateSavedSearch(ctx, "user-123", "", " Go backlog ", "tag:go status:unread", true, false)
+	require.NoError(t, err)
+	assert.Equal(t, "Go backlog", s.Name)
+	assert.True(t, s.Pinned)
+
+	_, err = uc.CreateSavedSearch(ctx, "user-123", "", "Broken", "status:done", false, false)
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	// Nil arguments are left as they are
+	subscribed := true
+	updated, err := uc.UpdateSavedSearch(ctx, s.ID, "user-123", nil, nil, nil, &subscribed)
+	require.NoError(t, err)
+	assert.Equal(t, "Go backlog", updated.Name)
+	assert.Equal(t, "tag:go status:unread", updated.Query)
+	assert.True(t, updated.Pinned)
+	assert.True(t, updated.Subscribed)
+
+	invalid := "tag:"
+	_, err = uc.UpdateSavedSearch(ctx, s.ID, "user-123", nil, &invalid, nil, nil)
+	assert.ErrorIs(t, err, errs.ErrValidation)
+	assert.Equal(t, "tag:go status:unread", repo.searches[s.ID].Query)
+
+	books, err := uc.SavedSearchBooks(ctx, updated, 5)
+	require.NoError(t, err)
+	assert.Len(t, books, 1)
+	assert.Equal(t, "user-123", bookRepo.userID)
+	assert.Equal(t, 5, bookRepo.limit)
+
+	t.Run("other users cannot see or change it", func(t *testing.T) {
+		_, err := uc.GetSavedSearch(ctx, s.ID, "user-456")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		_, err = uc.UpdateSavedSearch(ctx, s.ID, "user-456", nil, nil, &subscribed, nil)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		assert.ErrorIs(t, uc.DeleteSavedSearch(ctx, s.ID, "user-456"), errs.ErrNotFound)
+	})
+
+	require.NoError(t, uc.DeleteSavedSearch(ctx, s.ID, "user-123"))
+	searches, err := uc.GetSavedSearches(ctx, "user-123")
+	require.NoError(t, err)
+	assert.Empty(t, searches)
+}
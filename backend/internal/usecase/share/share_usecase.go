@@ -0,0 +1,129 @@
+package share
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/share"
+)
+
+type UseCase struct {
+	shareRepo share.Repository
+	bookRepo  book.Repository
+}
+
+func NewUseCase(shareRepo share.Repository, bookRepo book.Repository) *UseCase {
+	return &UseCase{
+		shareRepo: shareRepo,
+		bookRepo:  bookRepo,
+	}
+}
+
+func (uc *UseCase) CreateShareLink(ctx context.Context, userID, bookID string, expiresAt *time.Time, password string) (*share.Link, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if bookID == "" {
+		return nil, errs.Validation("bookId", "book ID is required")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errs.Validation("expiresAt", "expiration must be in the future")
+	}
+
+	// Only the owner of a book can share it. Other members of a workspace
+	// can read its books but not publish them.
+	b, err := uc.bookRepo.FindByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find book: %w", err)
+	}
+	if b.UserID != userID {
+		return nil, errs.Unauthorized("only the owner of a book can share it")
+	}
+
+	link, err := share.NewLink(userID, bookID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if password != "" {
+		if err := link.SetPassword(password); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.shareRepo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save share link: %w", err)
+	}
+
+	return link, nil
+}
+
+func (uc *UseCase) RevokeShareLink(ctx context.Context, id, userID string) error {
+	if id == "" {
+		return errs.Validation("id", "share link ID is required")
+	}
+	if userID == "" {
+		return errs.Unauthorized("user ID is required")
+	}
+
+	if err := uc.shareRepo.Revoke(ctx, id, userID); err != nil {
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) GetShareLinks(ctx context.Context, bookID, userID string) ([]*share.Link, error) {
+	if bookID == "" {
+		return nil, errs.Validation("bookId", "book ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	links, err := uc.shareRepo.FindByBookID(ctx, bookID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share links: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetSharedBook resolves a public share token to its book and records the view.
+// It returns share.ErrPasswordRequired when the link is protected and the
+// password is missing or wrong.
+func (uc *UseCase) GetSharedBook(ctx context.Context, token, password string) (*book.Book, *share.Link, error) {
+	if token == "" {
+		return nil, nil, errs.Validation("token", "share token is required")
+	}
+
+	link, err := uc.shareRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	if !link.IsActive(time.Now()) {
+		return nil, nil, share.ErrLinkInactive
+	}
+	if !link.CheckPassword(password) {
+		return nil, link, share.ErrPasswordRequired
+	}
+
+	b, err := uc.bookRepo.FindByID(ctx, link.BookID, link.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get shared book: %w", err)
+	}
+
+	if err := uc.shareRepo.IncrementViewCount(ctx, link.ID); err != nil {
+		// Log error but don't fail the operation
+		log.Warn().Err(err).Str("share_link_id", link.ID).Msg("Failed to record share link view")
+	} else {
+		link.ViewCount++
+	}
+
+	return b, link, nil
+}
@@ -0,0 +1,174 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/book/booktest"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/share"
+)
+
+// MockShareRepository implements share.Repository for testing
+type MockShareRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareRepository) Save(ctx context.Context, l *share.Link) error {
+	args := m.Called(ctx, l)
+	return args.Error(0)
+}
+
+func (m *MockShareRepository) FindByToken(ctx context.Context, token string) (*share.Link, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*share.Link), args.Error(1)
+}
+
+func (m *MockShareRepository) FindByBookID(ctx context.Context, bookID, userID string) ([]*share.Link, error) {
+	args := m.Called(ctx, bookID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*share.Link), args.Error(1)
+}
+
+func (m *MockShareRepository) Revoke(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockShareRepository) IncrementViewCount(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestUseCase_CreateShareLink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create link for own book", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(shareRepo, bookRepo)
+
+		b := book.NewBook("user-123", "content")
+		bookRepo.On("FindByID", ctx, b.ID, "user-123").Return(b, nil)
+		shareRepo.On("Save", ctx, mock.AnythingOfType("*share.Link")).Return(nil)
+
+		link, err := uc.CreateShareLink(ctx, "user-123", b.ID, nil, "secret")
+
+		require.NoError(t, err)
+		assert.Equal(t, b.ID, link.BookID)
+		assert.NotEmpty(t, link.Token)
+		assert.True(t, link.HasPassword())
+		shareRepo.AssertExpectations(t)
+		bookRepo.AssertExpectations(t)
+	})
+
+	t.Run("error when book belongs to another user", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(shareRepo, bookRepo)
+
+		bookRepo.On("FindByID", ctx, "book-123", "user-456").Return(nil, errors.New("book not found"))
+
+		_, err := uc.CreateShareLink(ctx, "user-456", "book-123", nil, "")
+
+		assert.Error(t, err)
+		shareRepo.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("error when a workspace member does not own the book", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(shareRepo, bookRepo)
+
+		b := book.NewBook("user-123", "content")
+		b.WorkspaceID = "workspace-1"
+		bookRepo.On("FindByID", ctx, b.ID, "viewer-456").Return(b, nil)
+
+		_, err := uc.CreateShareLink(ctx, "viewer-456", b.ID, nil, "")
+
+		assert.ErrorIs(t, err, errs.ErrUnauthorized)
+		shareRepo.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("error when expiration is in the past", func(t *testing.T) {
+		uc := NewUseCase(new(MockShareRepository), new(booktest.MockRepository))
+		past := time.Now().Add(-time.Minute)
+
+		_, err := uc.CreateShareLink(ctx, "user-123", "book-123", &past, "")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expiration must be in the future")
+	})
+}
+
+func TestUseCase_GetSharedBook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns book and records view", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(shareRepo, bookRepo)
+
+		b := book.NewBook("user-123", "content")
+		link, err := share.NewLink("user-123", b.ID, nil)
+		require.NoError(t, err)
+
+		shareRepo.On("FindByToken", ctx, link.Token).Return(link, nil)
+		bookRepo.On("FindByID", ctx, b.ID, "user-123").Return(b, nil)
+		shareRepo.On("IncrementViewCount", ctx, link.ID).Return(nil)
+
+		result, resultLink, err := uc.GetSharedBook(ctx, link.Token, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, b, result)
+		assert.Equal(t, 1, resultLink.ViewCount)
+		shareRepo.AssertExpectations(t)
+	})
+
+	t.Run("revoked link is inactive", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(shareRepo, bookRepo)
+
+		link, err := share.NewLink("user-123", "book-123", nil)
+		require.NoError(t, err)
+		revokedAt := time.Now()
+		link.RevokedAt = &revokedAt
+
+		shareRepo.On("FindByToken", ctx, link.Token).Return(link, nil)
+
+		_, _, err = uc.GetSharedBook(ctx, link.Token, "")
+
+		assert.ErrorIs(t, err, share.ErrLinkInactive)
+		bookRepo.AssertNotCalled(t, "FindByID")
+	})
+
+	t.Run("protected link requires password", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		bookRepo := new(booktest.MockRepository)
+		uc := NewUseCase(shareRepo, bookRepo)
+
+		link, err := share.NewLink("user-123", "book-123", nil)
+		require.NoError(t, err)
+		require.NoError(t, link.SetPassword("secret"))
+
+		shareRepo.On("FindByToken", ctx, link.Token).Return(link, nil)
+
+		_, _, err = uc.GetSharedBook(ctx, link.Token, "wrong")
+
+		assert.ErrorIs(t, err, share.ErrPasswordRequired)
+		shareRepo.AssertNotCalled(t, "IncrementViewCount")
+	})
+}
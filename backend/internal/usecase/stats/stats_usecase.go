@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/stats"
+)
+
+type UseCase struct {
+	statsRepo stats.Repository
+}
+
+func NewUseCase(statsRepo stats.Repository) *UseCase {
+	return &UseCase{
+		statsRepo: statsRepo,
+	}
+}
+
+// GetLibraryStats describes the user's personal books, or a workspace when
+// workspaceID is set, over the range ending today. An empty range is a month.
+func (uc *UseCase) GetLibraryStats(ctx context.Context, userID, workspaceID string, rng stats.Range) (*stats.LibraryStats, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if rng == "" {
+		rng = stats.RangeMonth
+	}
+	if !rng.IsValid() {
+		return nil, errs.Validation("range", "unknown range")
+	}
+
+	since, interval := rng.Window(time.Now())
+	result, err := uc.statsRepo.GetLibraryStats(ctx, userID, workspaceID, since, interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library stats: %w", err)
+	}
+
+	result.Range = rng
+	return result, nil
+}
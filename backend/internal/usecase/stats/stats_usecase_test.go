@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/stats"
+)
+
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) GetLibraryStats(ctx context.Context, userID, workspaceID string, since time.Time, interval stats.Interval) (*stats.LibraryStats, error) {
+	args := m.Called(ctx, userID, workspaceID, since, interval)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*stats.LibraryStats), args.Error(1)
+}
+
+func TestUseCase_GetLibraryStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("defaults to a month", func(t *testing.T) {
+		repo := new(MockStatsRepository)
+		uc := NewUseCase(repo)
+		repo.On("GetLibraryStats", ctx, "user-123", "ws-1", mock.AnythingOfType("time.Time"), stats.IntervalDay).
+			Return(&stats.LibraryStats{TotalBooks: 3}, nil)
+
+		result, err := uc.GetLibraryStats(ctx, "user-123", "ws-1", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, stats.RangeMonth, result.Range)
+		assert.Equal(t, 3, result.TotalBooks)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("weekly buckets for a year", func(t *testing.T) {
+		repo := new(MockStatsRepository)
+		uc := NewUseCase(repo)
+		repo.On("GetLibraryStats", ctx, "user-123", "", mock.AnythingOfType("time.Time"), stats.IntervalWeek).
+			Return(&stats.LibraryStats{}, nil)
+
+		result, err := uc.GetLibraryStats(ctx, "user-123", "", stats.RangeYear)
+
+		require.NoError(t, err)
+		assert.Equal(t, stats.RangeYear, result.Range)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown range", func(t *testing.T) {
+		uc := NewUseCase(new(MockStatsRepository))
+
+		_, err := uc.GetLibraryStats(ctx, "user-123", "", "decade")
+
+		assert.True(t, errors.Is(err, errs.ErrValidation))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		uc := NewUseCase(new(MockStatsRepository))
+
+		_, err := uc.GetLibraryStats(ctx, "", "", stats.RangeWeek)
+
+		assert.True(t, errors.Is(err, errs.ErrUnauthorized))
+	})
+}
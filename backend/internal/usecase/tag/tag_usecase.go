@@ -0,0 +1,119 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+)
+
+type UseCase struct {
+	tagRepo tag.Repository
+}
+
+func NewUseCase(tagRepo tag.Repository) *UseCase {
+	return &UseCase{
+		tagRepo: tagRepo,
+	}
+}
+
+// GetTagTree returns the tag hierarchy of the user's personal books, or of a
+// workspace when workspaceID is set, with the number of books under each tag
+func (uc *UseCase) GetTagTree(ctx context.Context, userID, workspaceID string) ([]*tag.Node, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	bookTags, err := uc.tagRepo.FindBookTags(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	return tag.BuildTree(bookTags), nil
+}
+
+func (uc *UseCase) GetSynonyms(ctx context.Context, userID string) ([]*tag.Synonym, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	synonyms, err := uc.tagRepo.FindSynonyms(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag synonyms: %w", err)
+	}
+
+	return synonyms, nil
+}
+
+// SetSynonym makes alias stand for tagName in the user's tags from now on.
+// Synonyms do not chain: tagName cannot be an alias itself, and alias cannot
+// be the tag of another synonym.
+func (uc *UseCase) SetSynonym(ctx context.Context, userID, alias, tagName string) (*tag.Synonym, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	synonym, err := tag.NewSynonym(userID, alias, tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.tagRepo.FindSynonyms(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag synonyms: %w", err)
+	}
+	for _, s := range existing {
+		if s.Alias == strings.ToLower(synonym.Tag) {
+			return nil, errs.Validation("tag", fmt.Sprintf("%s is itself a synonym of %s", synonym.Tag, s.Tag))
+		}
+		if s.Alias != synonym.Alias && strings.ToLower(s.Tag) == synonym.Alias {
+			return nil, errs.Validation("alias", fmt.Sprintf("%s is the tag of the synonym %s", synonym.Alias, s.Alias))
+		}
+	}
+
+	if err := uc.tagRepo.SaveSynonym(ctx, synonym); err != nil {
+		return nil, fmt.Errorf("failed to save tag synonym: %w", err)
+	}
+
+	return synonym, nil
+}
+
+func (uc *UseCase) DeleteSynonym(ctx context.Context, userID, alias string) error {
+	if userID == "" {
+		return errs.Unauthorized("user ID is required")
+	}
+
+	if err := uc.tagRepo.DeleteSynonym(ctx, userID, strings.ToLower(tag.Normalize(alias))); err != nil {
+		return fmt.Errorf("failed to delete tag synonym: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveTag returns the tag that name stands for in the user's tags
+func (uc *UseCase) ResolveTag(ctx context.Context, userID, name string) (string, error) {
+	synonyms, err := uc.GetSynonyms(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return tag.NewSynonyms(synonyms).Canonical(name), nil
+}
+
+// FilterByTag keeps the books carrying the tag that name stands for, or one
+// of its descendants. An empty name keeps every book.
+func (uc *UseCase) FilterByTag(ctx context.Context, books []*book.Book, userID, name string) ([]*book.Book, error) {
+	if name == "" {
+		return books, nil
+	}
+
+	resolved, err := uc.ResolveTag(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag.Filter(books, resolved), nil
+}
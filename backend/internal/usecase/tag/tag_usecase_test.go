@@ -0,0 +1,116 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/book"
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/tag"
+)
+
+// stubTagRepository keeps one user's synonyms in memory and serves the tags
+// of a fixed library
+type stubTagRepository struct {
+	synonyms []*tag.Synonym
+	bookTags [][]string
+}
+
+func (r *stubTagRepository) FindSynonyms(ctx context.Context, userID string) ([]*tag.Synonym, error) {
+	return r.synonyms, nil
+}
+
+func (r *stubTagRepository) SaveSynonym(ctx context.Context, s *tag.Synonym) error {
+	for i, existing := range r.synonyms {
+		if existing.Alias == s.Alias {
+			r.synonyms[i] = s
+			return nil
+		}
+	}
+	r.synonyms = append(r.synonyms, s)
+	return nil
+}
+
+func (r *stubTagRepository) DeleteSynonym(ctx context.Context, userID, alias string) error {
+	for i, s := range r.synonyms {
+		if s.Alias == alias {
+			r.synonyms = append(r.synonyms[:i], r.synonyms[i+1:]...)
+			return nil
+		}
+	}
+	return tag.ErrSynonymNotFound
+}
+
+func (r *stubTagRepository) FindBookTags(ctx context.Context, userID, workspaceID string) ([][]string, error) {
+	return r.bookTags, nil
+}
+
+func TestUseCase_SetSynonym(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubTagRepository{}
+	uc := NewUseCase(repo)
+
+	s, err := uc.SetSynonym(ctx, "user-123", "GoLang", "lang/go")
+	require.NoError(t, err)
+	assert.Equal(t, "golang", s.Alias)
+
+	// Pointing an alias at a new tag replaces it
+	_, err = uc.SetSynonym(ctx, "user-123", "golang", "go")
+	require.NoError(t, err)
+	require.Len(t, repo.synonyms, 1)
+	assert.Equal(t, "go", repo.synonyms[0].Tag)
+
+	t.Run("tag cannot be an alias", func(t *testing.T) {
+		_, err := uc.SetSynonym(ctx, "user-123", "gopher", "Golang")
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("alias cannot be the tag of another synonym", func(t *testing.T) {
+		_, err := uc.SetSynonym(ctx, "user-123", "go", "lang/go")
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("unknown alias", func(t *testing.T) {
+		assert.ErrorIs(t, uc.DeleteSynonym(ctx, "user-123", "rustlang"), errs.ErrNotFound)
+		require.NoError(t, uc.DeleteSynonym(ctx, "user-123", " GOLANG "))
+		assert.Empty(t, repo.synonyms)
+	})
+}
+
+func TestUseCase_FilterByTag(t *testing.T) {
+	ctx := context.Background()
+	uc := NewUseCase(&stubTagRepository{synonyms: []*tag.Synonym{{Alias: "golang", Tag: "lang/go"}}})
+	books := []*book.Book{
+		{ID: "go", Tags: []string{"lang/go/generics"}},
+		{ID: "rust", Tags: []string{"lang/rust"}},
+	}
+
+	filtered, err := uc.FilterByTag(ctx, books, "user-123", "golang")
+	require.NoError(t, err)
+	assert.Equal(t, []*book.Book{books[0]}, filtered)
+
+	filtered, err = uc.FilterByTag(ctx, books, "user-123", "lang")
+	require.NoError(t, err)
+	assert.Equal(t, books, filtered)
+
+	filtered, err = uc.FilterByTag(ctx, books, "user-123", "")
+	require.NoError(t, err)
+	assert.Equal(t, books, filtered)
+}
+
+func TestUseCase_GetTagTree(t *testing.T) {
+	uc := NewUseCase(&stubTagRepository{bookTags: [][]string{{"lang/go"}, {"lang/rust", "web"}}})
+
+	tree, err := uc.GetTagTree(context.Background(), "user-123", "")
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "lang", tree[0].Path)
+	assert.Equal(t, 2, tree[0].Count)
+	assert.Len(t, tree[0].Children, 2)
+
+	_, err = uc.GetTagTree(context.Background(), "", "")
+	assert.ErrorIs(t, err, errs.ErrUnauthorized)
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+)
+
+const (
+	defaultDeliveryLimit = 20
+	maxDeliveryLimit     = 100
+)
+
+type UseCase struct {
+	webhookRepo webhook.Repository
+	dispatcher  webhook.Dispatcher
+}
+
+func NewUseCase(webhookRepo webhook.Repository, dispatcher webhook.Dispatcher) *UseCase {
+	return &UseCase{
+		webhookRepo: webhookRepo,
+		dispatcher:  dispatcher,
+	}
+}
+
+// CreateWebhook subscribes a URL to book events. A secret is generated when
+// none is given; it is used to sign every delivery.
+func (uc *UseCase) CreateWebhook(ctx context.Context, userID, rawURL, secret string, events []webhook.EventType) (*webhook.Webhook, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if rawURL == "" {
+		return nil, errs.Validation("url", "webhook URL is required")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, errs.Validation("url", "webhook URL must be an absolute http or https URL")
+	}
+	if !webhook.PublicHost(u.Hostname()) {
+		return nil, errs.Validation("url", "webhook URL must point to a public host")
+	}
+	if len(events) == 0 {
+		return nil, errs.Validation("events", "at least one event is required")
+	}
+	for _, e := range events {
+		if !e.IsValid() {
+			return nil, errs.Validation("events", fmt.Sprintf("invalid event: %s", e))
+		}
+	}
+
+	w, err := webhook.NewWebhook(userID, rawURL, secret, dedupeEvents(events))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.webhookRepo.Save(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	return w, nil
+}
+
+func (uc *UseCase) GetMyWebhooks(ctx context.Context, userID string) ([]*webhook.Webhook, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	webhooks, err := uc.webhookRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (uc *UseCase) DeleteWebhook(ctx context.Context, id, userID string) error {
+	if id == "" {
+		return errs.Validation("id", "webhook ID is required")
+	}
+	if userID == "" {
+		return errs.Unauthorized("user ID is required")
+	}
+
+	if err := uc.webhookRepo.Delete(ctx, id, userID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the delivery log of a webhook, newest first
+func (uc *UseCase) GetDeliveries(ctx context.Context, webhookID, userID string, limit int) ([]*webhook.Delivery, error) {
+	if webhookID == "" {
+		return nil, errs.Validation("id", "webhook ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+
+	deliveries, err := uc.webhookRepo.FindDeliveries(ctx, webhookID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// TestWebhook sends a test event right away, without retries, and returns the
+// recorded delivery so the caller can see how the receiver responded
+func (uc *UseCase) TestWebhook(ctx context.Context, id, userID string) (*webhook.Delivery, error) {
+	if id == "" {
+		return nil, errs.Validation("id", "webhook ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	w, err := uc.webhookRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	event := webhook.NewEvent(webhook.EventTest, userID, map[string]string{"webhookId": w.ID})
+	delivery, err := uc.dispatcher.Deliver(ctx, w, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deliver test event: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func dedupeEvents(events []webhook.EventType) []webhook.EventType {
+	seen := make(map[webhook.EventType]bool)
+	var result []webhook.EventType
+	for _, e := range events {
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/webhook"
+)
+
+// MockRepository implements webhook.Repository for testing
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, w *webhook.Webhook) error {
+	args := m.Called(ctx, w)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id, userID string) (*webhook.Webhook, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) FindByUserID(ctx context.Context, userID string) ([]*webhook.Webhook, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveDelivery(ctx context.Context, d *webhook.Delivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindDeliveries(ctx context.Context, webhookID, userID string, limit int) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, webhookID, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+// MockDispatcher implements webhook.Dispatcher for testing
+type MockDispatcher struct {
+	mock.Mock
+}
+
+func (m *MockDispatcher) Publish(ctx context.Context, event webhook.Event) {
+	m.Called(ctx, event)
+}
+
+func (m *MockDispatcher) Deliver(ctx context.Context, w *webhook.Webhook, event webhook.Event) (*webhook.Delivery, error) {
+	args := m.Called(ctx, w, event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Delivery), args.Error(1)
+}
+
+func TestUseCase_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create webhook successfully", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, new(MockDispatcher))
+
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*webhook.Webhook")).Return(nil)
+
+		events := []webhook.EventType{webhook.EventBookCreated, webhook.EventBookCreated, webhook.EventBookMerged}
+		w, err := uc.CreateWebhook(ctx, "user-123", "https://example.com/hook", "", events)
+
+		require.NoError(t, err)
+		assert.Equal(t, []webhook.EventType{webhook.EventBookCreated, webhook.EventBookMerged}, w.Events)
+		assert.NotEmpty(t, w.Secret)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		uc := NewUseCase(new(MockRepository), new(MockDispatcher))
+
+		tests := []struct {
+			name   string
+			url    string
+			events []webhook.EventType
+			errMsg string
+		}{
+			{"missing URL", "", []webhook.EventType{webhook.EventBookCreated}, "webhook URL is required"},
+			{"relative URL", "/hook", []webhook.EventType{webhook.EventBookCreated}, "absolute http or https URL"},
+			{"unsupported scheme", "ftp://example.com", []webhook.EventType{webhook.EventBookCreated}, "absolute http or https URL"},
+			{"loopback host", "http://localhost:8080/hook", []webhook.EventType{webhook.EventBookCreated}, "public host"},
+			{"metadata address", "http://169.254.169.254/latest", []webhook.EventType{webhook.EventBookCreated}, "public host"},
+			{"private address", "https://[fd00::1]/hook", []webhook.EventType{webhook.EventBookCreated}, "public host"},
+			{"no events", "https://example.com/hook", nil, "at least one event is required"},
+			{"test event", "https://example.com/hook", []webhook.EventType{webhook.EventTest}, "invalid event"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := uc.CreateWebhook(ctx, "user-123", tt.url, "", tt.events)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			})
+		}
+	})
+}
+
+func TestUseCase_GetDeliveries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("limit is clamped", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, new(MockDispatcher))
+
+		mockRepo.On("FindDeliveries", ctx, "hook-123", "user-123", 20).Return([]*webhook.Delivery{}, nil).Once()
+		mockRepo.On("FindDeliveries", ctx, "hook-123", "user-123", 100).Return([]*webhook.Delivery{}, nil).Once()
+
+		_, err := uc.GetDeliveries(ctx, "hook-123", "user-123", 0)
+		require.NoError(t, err)
+		_, err = uc.GetDeliveries(ctx, "hook-123", "user-123", 1000)
+		require.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUseCase_TestWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deliver test event", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockDispatcher := new(MockDispatcher)
+		uc := NewUseCase(mockRepo, mockDispatcher)
+
+		w := &webhook.Webhook{ID: "hook-123", UserID: "user-123"}
+		delivery := &webhook.Delivery{ID: "delivery-123", Succeeded: true}
+
+		mockRepo.On("FindByID", ctx, "hook-123", "user-123").Return(w, nil)
+		mockDispatcher.On("Deliver", ctx, w, mock.MatchedBy(func(e webhook.Event) bool {
+			return e.Type == webhook.EventTest
+		})).Return(delivery, nil)
+
+		result, err := uc.TestWebhook(ctx, "hook-123", "user-123")
+
+		require.NoError(t, err)
+		assert.Equal(t, delivery, result)
+		mockDispatcher.AssertExpectations(t)
+	})
+
+	t.Run("error when webhook not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockDispatcher := new(MockDispatcher)
+		uc := NewUseCase(mockRepo, mockDispatcher)
+
+		mockRepo.On("FindByID", ctx, "hook-123", "user-123").Return(nil, errors.New("webhook not found"))
+
+		_, err := uc.TestWebhook(ctx, "hook-123", "user-123")
+
+		assert.Error(t, err)
+		mockDispatcher.AssertNotCalled(t, "Deliver")
+	})
+}
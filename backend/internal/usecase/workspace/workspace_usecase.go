@@ -0,0 +1,277 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/motoya-k/tsundoc/internal/domain/errs"
+	"github.com/motoya-k/tsundoc/internal/domain/transaction"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
+)
+
+type UseCase struct {
+	workspaceRepo workspace.Repository
+	tx            transaction.UnitOfWork
+}
+
+func NewUseCase(workspaceRepo workspace.Repository, tx transaction.UnitOfWork) *UseCase {
+	return &UseCase{
+		workspaceRepo: workspaceRepo,
+		tx:            tx,
+	}
+}
+
+func (uc *UseCase) CreateWorkspace(ctx context.Context, userID, name string) (*workspace.Workspace, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errs.Validation("name", "workspace name is required")
+	}
+
+	ws := workspace.NewWorkspace(userID, name)
+	err := uc.tx.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.workspaceRepo.Save(ctx, ws); err != nil {
+			return fmt.Errorf("failed to save workspace: %w", err)
+		}
+
+		owner := &workspace.Member{
+			WorkspaceID: ws.ID,
+			UserID:      userID,
+			Role:        workspace.RoleOwner,
+		}
+		if err := uc.workspaceRepo.AddMember(ctx, owner); err != nil {
+			return fmt.Errorf("failed to add workspace owner: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ws, nil
+}
+
+func (uc *UseCase) GetWorkspace(ctx context.Context, id, userID string) (*workspace.Workspace, error) {
+	if id == "" {
+		return nil, errs.Validation("id", "workspace ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	ws, err := uc.workspaceRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return ws, nil
+}
+
+func (uc *UseCase) GetMyWorkspaces(ctx context.Context, userID string) ([]*workspace.Workspace, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	workspaces, err := uc.workspaceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// GetMembership returns the user's membership, failing if the user is not a member
+func (uc *UseCase) GetMembership(ctx context.Context, workspaceID, userID string) (*workspace.Member, error) {
+	if workspaceID == "" {
+		return nil, errs.Validation("workspaceId", "workspace ID is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	member, err := uc.workspaceRepo.FindMember(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace membership: %w", err)
+	}
+
+	return member, nil
+}
+
+func (uc *UseCase) GetMembers(ctx context.Context, workspaceID, userID string) ([]*workspace.Member, error) {
+	if _, err := uc.GetMembership(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.workspaceRepo.FindMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+
+	return members, nil
+}
+
+// GetMembersOfWorkspaces returns the members of each workspace, in the order of
+// workspaceIDs. The entry is nil for workspaces the user is not a member of.
+func (uc *UseCase) GetMembersOfWorkspaces(ctx context.Context, workspaceIDs []string, userID string) ([][]*workspace.Member, error) {
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	byWorkspace, err := uc.workspaceRepo.FindMembersByWorkspaceIDs(ctx, workspaceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+
+	result := make([][]*workspace.Member, len(workspaceIDs))
+	for i, workspaceID := range workspaceIDs {
+		members := byWorkspace[workspaceID]
+		for _, m := range members {
+			if m.UserID == userID {
+				result[i] = members
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (uc *UseCase) CreateInvitation(ctx context.Context, workspaceID, userID string, role workspace.Role) (*workspace.Invitation, error) {
+	if !role.IsValid() {
+		return nil, errs.Validation("role", fmt.Sprintf("invalid role: %s", role))
+	}
+	if err := uc.requireManager(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	inv, err := workspace.NewInvitation(workspaceID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.workspaceRepo.SaveInvitation(ctx, inv); err != nil {
+		return nil, fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	return inv, nil
+}
+
+func (uc *UseCase) AcceptInvitation(ctx context.Context, token, userID string) (*workspace.Workspace, error) {
+	if token == "" {
+		return nil, errs.Validation("token", "invitation token is required")
+	}
+	if userID == "" {
+		return nil, errs.Unauthorized("user ID is required")
+	}
+
+	inv, err := uc.workspaceRepo.FindInvitationByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if !inv.IsPending(time.Now()) {
+		return nil, errs.Conflict("invitation has expired or was already used")
+	}
+
+	if _, err := uc.workspaceRepo.FindMember(ctx, inv.WorkspaceID, userID); err == nil {
+		return nil, errs.Conflict("already a member of this workspace")
+	}
+
+	err = uc.tx.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.workspaceRepo.MarkInvitationAccepted(ctx, inv.ID, userID); err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+
+		member := &workspace.Member{
+			WorkspaceID: inv.WorkspaceID,
+			UserID:      userID,
+			Role:        inv.Role,
+		}
+		if err := uc.workspaceRepo.AddMember(ctx, member); err != nil {
+			return fmt.Errorf("failed to join workspace: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetWorkspace(ctx, inv.WorkspaceID, userID)
+}
+
+func (uc *UseCase) UpdateMemberRole(ctx context.Context, workspaceID, userID, memberID string, role workspace.Role) (*workspace.Member, error) {
+	if !role.IsValid() {
+		return nil, errs.Validation("role", fmt.Sprintf("invalid role: %s", role))
+	}
+	if err := uc.requireManager(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	if role != workspace.RoleOwner {
+		if err := uc.ensureAnotherOwner(ctx, workspaceID, memberID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.workspaceRepo.UpdateMemberRole(ctx, workspaceID, memberID, role); err != nil {
+		return nil, fmt.Errorf("failed to update member role: %w", err)
+	}
+
+	return uc.workspaceRepo.FindMember(ctx, workspaceID, memberID)
+}
+
+// RemoveMember removes a member from a workspace. Owners can remove anyone,
+// and any member can remove themselves to leave the workspace.
+func (uc *UseCase) RemoveMember(ctx context.Context, workspaceID, userID, memberID string) error {
+	if memberID != userID {
+		if err := uc.requireManager(ctx, workspaceID, userID); err != nil {
+			return err
+		}
+	} else if _, err := uc.GetMembership(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+
+	if err := uc.ensureAnotherOwner(ctx, workspaceID, memberID); err != nil {
+		return err
+	}
+
+	if err := uc.workspaceRepo.RemoveMember(ctx, workspaceID, memberID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) requireManager(ctx context.Context, workspaceID, userID string) error {
+	member, err := uc.GetMembership(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !member.Role.CanManage() {
+		return errs.Unauthorized("only workspace owners can manage members")
+	}
+	return nil
+}
+
+// ensureAnotherOwner fails if memberID is the last owner of the workspace
+func (uc *UseCase) ensureAnotherOwner(ctx context.Context, workspaceID, memberID string) error {
+	members, err := uc.workspaceRepo.FindMembers(ctx, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to get workspace members: %w", err)
+	}
+
+	for _, m := range members {
+		if m.Role == workspace.RoleOwner && m.UserID != memberID {
+			return nil
+		}
+	}
+	for _, m := range members {
+		if m.UserID == memberID && m.Role == workspace.RoleOwner {
+			return errs.Conflict("a workspace must keep at least one owner")
+		}
+	}
+	return nil
+}
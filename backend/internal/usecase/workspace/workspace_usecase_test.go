@@ -0,0 +1,298 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/motoya-k/tsundoc/internal/domain/transaction"
+	"github.com/motoya-k/tsundoc/internal/domain/workspace"
+)
+
+// MockRepository implements workspace.Repository for testing
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, ws *workspace.Workspace) error {
+	args := m.Called(ctx, ws)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id, userID string) (*workspace.Workspace, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*workspace.Workspace), args.Error(1)
+}
+
+func (m *MockRepository) FindByUserID(ctx context.Context, userID string) ([]*workspace.Workspace, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*workspace.Workspace), args.Error(1)
+}
+
+func (m *MockRepository) AddMember(ctx context.Context, member *workspace.Member) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindMember(ctx context.Context, workspaceID, userID string) (*workspace.Member, error) {
+	args := m.Called(ctx, workspaceID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*workspace.Member), args.Error(1)
+}
+
+func (m *MockRepository) FindMembers(ctx context.Context, workspaceID string) ([]*workspace.Member, error) {
+	args := m.Called(ctx, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*workspace.Member), args.Error(1)
+}
+
+func (m *MockRepository) FindMembersByWorkspaceIDs(ctx context.Context, workspaceIDs []string) (map[string][]*workspace.Member, error) {
+	args := m.Called(ctx, workspaceIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]*workspace.Member), args.Error(1)
+}
+
+func (m *MockRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID string, role workspace.Role) error {
+	args := m.Called(ctx, workspaceID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	args := m.Called(ctx, workspaceID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveInvitation(ctx context.Context, inv *workspace.Invitation) error {
+	args := m.Called(ctx, inv)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindInvitationByToken(ctx context.Context, token string) (*workspace.Invitation, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*workspace.Invitation), args.Error(1)
+}
+
+func (m *MockRepository) MarkInvitationAccepted(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// recordingUnitOfWork runs fn directly and keeps the error that would decide
+// between commit and rollback
+type recordingUnitOfWork struct {
+	calls int
+	err   error
+}
+
+func (u *recordingUnitOfWork) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	u.calls++
+	u.err = fn(ctx)
+	return u.err
+}
+
+func TestUseCase_CreateWorkspace(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creator becomes owner", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, transaction.None)
+
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*workspace.Workspace")).Return(nil)
+		mockRepo.On("AddMember", ctx, mock.MatchedBy(func(m *workspace.Member) bool {
+			return m.UserID == "user-123" && m.Role == workspace.RoleOwner
+		})).Return(nil)
+
+		ws, err := uc.CreateWorkspace(ctx, "user-123", "  Research  ")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Research", ws.Name)
+		assert.Equal(t, "user-123", ws.OwnerID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed owner insert rolls back the workspace", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uow := &recordingUnitOfWork{}
+		uc := NewUseCase(mockRepo, uow)
+
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*workspace.Workspace")).Return(nil)
+		mockRepo.On("AddMember", ctx, mock.AnythingOfType("*workspace.Member")).Return(errors.New("insert failed"))
+
+		_, err := uc.CreateWorkspace(ctx, "user-123", "Research")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to add workspace owner")
+		assert.Equal(t, 1, uow.calls)
+		assert.Error(t, uow.err)
+	})
+
+	t.Run("error when name is empty", func(t *testing.T) {
+		uc := NewUseCase(new(MockRepository), transaction.None)
+
+		_, err := uc.CreateWorkspace(ctx, "user-123", " ")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "workspace name is required")
+	})
+}
+
+func TestUseCase_CreateInvitation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("owner can invite", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, transaction.None)
+
+		mockRepo.On("FindMember", ctx, "ws-1", "user-123").Return(&workspace.Member{WorkspaceID: "ws-1", UserID: "user-123", Role: workspace.RoleOwner}, nil)
+		mockRepo.On("SaveInvitation", ctx, mock.AnythingOfType("*workspace.Invitation")).Return(nil)
+
+		inv, err := uc.CreateInvitation(ctx, "ws-1", "user-123", workspace.RoleEditor)
+
+		require.NoError(t, err)
+		assert.Equal(t, workspace.RoleEditor, inv.Role)
+		assert.NotEmpty(t, inv.Token)
+	})
+
+	t.Run("editor cannot invite", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, transaction.None)
+
+		mockRepo.On("FindMember", ctx, "ws-1", "user-456").Return(&workspace.Member{WorkspaceID: "ws-1", UserID: "user-456", Role: workspace.RoleEditor}, nil)
+
+		_, err := uc.CreateInvitation(ctx, "ws-1", "user-456", workspace.RoleViewer)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "only workspace owners")
+		mockRepo.AssertNotCalled(t, "SaveInvitation")
+	})
+
+	t.Run("error when role is invalid", func(t *testing.T) {
+		uc := NewUseCase(new(MockRepository), transaction.None)
+
+		_, err := uc.CreateInvitation(ctx, "ws-1", "user-123", workspace.Role("admin"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid role")
+	})
+}
+
+func TestUseCase_AcceptInvitation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("join workspace with invited role", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, transaction.None)
+
+		inv, err := workspace.NewInvitation("ws-1", "user-123", workspace.RoleViewer)
+		require.NoError(t, err)
+		ws := &workspace.Workspace{ID: "ws-1", Name: "Research"}
+
+		mockRepo.On("FindInvitationByToken", ctx, inv.Token).Return(inv, nil)
+		mockRepo.On("FindMember", ctx, "ws-1", "user-456").Return(nil, errors.New("workspace member not found"))
+		mockRepo.On("MarkInvitationAccepted", ctx, inv.ID, "user-456").Return(nil)
+		mockRepo.On("AddMember", ctx, mock.MatchedBy(func(m *workspace.Member) bool {
+			return m.UserID == "user-456" && m.Role == workspace.RoleViewer
+		})).Return(nil)
+		mockRepo.On("FindByID", ctx, "ws-1", "user-456").Return(ws, nil)
+
+		result, err := uc.AcceptInvitation(ctx, inv.Token, "user-456")
+
+		require.NoError(t, err)
+		assert.Equal(t, ws, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error when invitation expired", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, transaction.None)
+
+		inv, err := workspace.NewInvitation("ws-1", "user-123", workspace.RoleViewer)
+		require.NoError(t, err)
+		inv.ExpiresAt = time.Now().Add(-time.Minute)
+
+		mockRepo.On("FindInvitationByToken", ctx, inv.Token).Return(inv, nil)
+
+		_, err = uc.AcceptInvitation(ctx, inv.Token, "user-456")
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "AddMember")
+	})
+}
+
+func TestUseCase_RemoveMember(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("last owner cannot leave", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, transaction.None)
+
+		owner := &workspace.Member{WorkspaceID: "ws-1", UserID: "user-123", Role: workspace.RoleOwner}
+		mockRepo.On("FindMember", ctx, "ws-1", "user-123").Return(owner, nil)
+		mockRepo.On("FindMembers", ctx, "ws-1").Return([]*workspace.Member{
+			owner,
+			{WorkspaceID: "ws-1", UserID: "user-456", Role: workspace.RoleEditor},
+		}, nil)
+
+		err := uc.RemoveMember(ctx, "ws-1", "user-123", "user-123")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "at least one owner")
+		mockRepo.AssertNotCalled(t, "RemoveMember")
+	})
+
+	t.Run("member can leave", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		uc := NewUseCase(mockRepo, transaction.None)
+
+		owner := &workspace.Member{WorkspaceID: "ws-1", UserID: "user-123", Role: workspace.RoleOwner}
+		editor := &workspace.Member{WorkspaceID: "ws-1", UserID: "user-456", Role: workspace.RoleEditor}
+		mockRepo.On("FindMember", ctx, "ws-1", "user-456").Return(editor, nil)
+		mockRepo.On("FindMembers", ctx, "ws-1").Return([]*workspace.Member{owner, editor}, nil)
+		mockRepo.On("RemoveMember", ctx, "ws-1", "user-456").Return(nil)
+
+		err := uc.RemoveMember(ctx, "ws-1", "user-456", "user-456")
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUseCase_GetMembersOfWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	uc := NewUseCase(mockRepo, transaction.None)
+
+	owner := &workspace.Member{WorkspaceID: "ws-1", UserID: "user-123", Role: workspace.RoleOwner}
+	stranger := &workspace.Member{WorkspaceID: "ws-2", UserID: "user-456", Role: workspace.RoleOwner}
+	mockRepo.On("FindMembersByWorkspaceIDs", ctx, []string{"ws-1", "ws-2"}).Return(map[string][]*workspace.Member{
+		"ws-1": {owner},
+		"ws-2": {stranger},
+	}, nil)
+
+	members, err := uc.GetMembersOfWorkspaces(ctx, []string{"ws-1", "ws-2"}, "user-123")
+
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, []*workspace.Member{owner}, members[0])
+	assert.Nil(t, members[1], "members are hidden from non-members")
+}
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token VARCHAR(64) NOT NULL,
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_share_links_token ON share_links(token);
+CREATE INDEX IF NOT EXISTS idx_share_links_book_id ON share_links(book_id);
+CREATE INDEX IF NOT EXISTS idx_share_links_user_id ON share_links(user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_created_at;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook_id;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP INDEX IF EXISTS idx_webhooks_user_id;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,6 @@
+ALTER TABLE books ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE books ALTER COLUMN tags DROP DEFAULT;
+ALTER TABLE books ADD COLUMN tags_array TEXT[];
+UPDATE books SET tags_array = ARRAY(SELECT jsonb_array_elements_text(tags)) WHERE jsonb_typeof(tags) = 'array';
+ALTER TABLE books DROP COLUMN tags;
+ALTER TABLE books RENAME COLUMN tags_array TO tags;
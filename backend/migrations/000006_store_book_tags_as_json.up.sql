@@ -0,0 +1,5 @@
+-- Tags move from a Postgres-only array to a JSON array so that the same
+-- queries work on the SQLite backend
+ALTER TABLE books ALTER COLUMN tags TYPE JSONB USING COALESCE(to_jsonb(tags), '[]'::jsonb);
+ALTER TABLE books ALTER COLUMN tags SET DEFAULT '[]'::jsonb;
+ALTER TABLE books ALTER COLUMN id DROP DEFAULT;
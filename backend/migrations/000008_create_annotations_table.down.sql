@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_annotations_user_id;
+DROP INDEX IF EXISTS idx_annotations_book_id_user_id;
+DROP TABLE IF EXISTS annotations;
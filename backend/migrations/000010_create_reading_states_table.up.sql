@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS reading_states (
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (book_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_states_user_id_status ON reading_states(user_id, status);
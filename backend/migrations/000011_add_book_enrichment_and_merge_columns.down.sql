@@ -0,0 +1,2 @@
+ALTER TABLE books DROP COLUMN merged_from;
+ALTER TABLE books DROP COLUMN enrichment;
@@ -0,0 +1,3 @@
+-- Library statistics report on AI enrichment and on books made by merging
+ALTER TABLE books ADD COLUMN enrichment VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN merged_from INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_digest_settings_enabled;
+DROP TABLE IF EXISTS digest_settings;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS digest_settings (
+    user_id VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    frequency VARCHAR(16) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_settings_enabled ON digest_settings(enabled);
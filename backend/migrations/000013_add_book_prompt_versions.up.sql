@@ -0,0 +1,3 @@
+-- Versions of the AI prompts that generated a book's title, tags or content,
+-- keyed by operation
+ALTER TABLE books ADD COLUMN prompt_versions JSONB DEFAULT '{}'::jsonb;
@@ -0,0 +1,34 @@
+// Package migrations embeds the SQL migrations so that the server binary can
+// apply them without access to the source tree.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+// FS holds the NNNNNN_name.up.sql and NNNNNN_name.down.sql files for Postgres
+//
+//go:embed *.sql
+var FS embed.FS
+
+// sqliteFS holds the SQLite variant of the same migrations. Versions are kept
+// aligned with the Postgres set so that schema_migrations means the same
+// thing on both backends.
+//
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// For returns the migrations for a database dialect as reported by
+// gorm.Dialector.Name
+func For(dialect string) (fs.FS, error) {
+	switch dialect {
+	case "postgres":
+		return FS, nil
+	case "sqlite":
+		return fs.Sub(sqliteFS, "sqlite")
+	default:
+		return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+	}
+}
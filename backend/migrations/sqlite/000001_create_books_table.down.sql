@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_books_created_at;
+DROP INDEX IF EXISTS idx_books_deleted_at;
+DROP INDEX IF EXISTS idx_books_user_id;
+DROP TABLE IF EXISTS books;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS books (
+    id TEXT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    content TEXT,
+    tags TEXT NOT NULL DEFAULT '[]',
+    user_id VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_books_user_id ON books(user_id);
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_books_created_at ON books(created_at);
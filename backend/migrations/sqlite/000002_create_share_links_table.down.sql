@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_share_links_user_id;
+DROP INDEX IF EXISTS idx_share_links_book_id;
+DROP INDEX IF EXISTS idx_share_links_token;
+DROP TABLE IF EXISTS share_links;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_books_workspace_id;
+ALTER TABLE books DROP COLUMN workspace_id;
+
+DROP INDEX IF EXISTS idx_workspace_invitations_workspace_id;
+DROP INDEX IF EXISTS idx_workspace_invitations_token;
+DROP TABLE IF EXISTS workspace_invitations;
+
+DROP INDEX IF EXISTS idx_workspace_members_user_id;
+DROP TABLE IF EXISTS workspace_members;
+
+DROP INDEX IF EXISTS idx_workspaces_owner_id;
+DROP TABLE IF EXISTS workspaces;
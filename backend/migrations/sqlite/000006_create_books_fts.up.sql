@@ -0,0 +1,25 @@
+-- Full-text index over books. The trigram tokenizer gives case-insensitive
+-- substring matches, the same semantics as the LIKE search on Postgres.
+CREATE VIRTUAL TABLE IF NOT EXISTS books_fts USING fts5(
+    title,
+    content,
+    tags,
+    content='books',
+    content_rowid='rowid',
+    tokenize='trigram'
+);
+
+CREATE TRIGGER IF NOT EXISTS books_fts_insert AFTER INSERT ON books BEGIN
+    INSERT INTO books_fts(rowid, title, content, tags) VALUES (new.rowid, new.title, new.content, new.tags);
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_fts_delete AFTER DELETE ON books BEGIN
+    INSERT INTO books_fts(books_fts, rowid, title, content, tags) VALUES ('delete', old.rowid, old.title, old.content, old.tags);
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_fts_update AFTER UPDATE ON books BEGIN
+    INSERT INTO books_fts(books_fts, rowid, title, content, tags) VALUES ('delete', old.rowid, old.title, old.content, old.tags);
+    INSERT INTO books_fts(rowid, title, content, tags) VALUES (new.rowid, new.title, new.content, new.tags);
+END;
+
+INSERT INTO books_fts(books_fts) VALUES ('rebuild');
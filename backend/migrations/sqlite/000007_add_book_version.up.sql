@@ -0,0 +1,3 @@
+-- Optimistic concurrency: every update increments the version and is
+-- rejected when the client's expected version is stale
+ALTER TABLE books ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
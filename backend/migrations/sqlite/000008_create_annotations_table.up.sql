@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS annotations (
+    id TEXT PRIMARY KEY,
+    book_id TEXT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    start_offset INTEGER NOT NULL,
+    end_offset INTEGER NOT NULL,
+    quote TEXT NOT NULL,
+    color VARCHAR(16) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    detached BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_annotations_book_id_user_id ON annotations(book_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_annotations_user_id ON annotations(user_id);
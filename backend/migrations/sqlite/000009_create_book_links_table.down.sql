@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_book_links_lower_target;
+DROP INDEX IF EXISTS idx_book_links_target_id;
+DROP TABLE IF EXISTS book_links;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS book_links (
+    source_id TEXT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    target TEXT NOT NULL,
+    target_id TEXT REFERENCES books(id) ON DELETE SET NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (source_id, target)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_links_target_id ON book_links(target_id);
+CREATE INDEX IF NOT EXISTS idx_book_links_lower_target ON book_links(LOWER(target));
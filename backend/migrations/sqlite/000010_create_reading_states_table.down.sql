@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reading_states_user_id_status;
+DROP TABLE IF EXISTS reading_states;
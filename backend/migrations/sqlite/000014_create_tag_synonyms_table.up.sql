@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS tag_synonyms (
+    user_id VARCHAR(255) NOT NULL,
+    alias VARCHAR(255) NOT NULL,
+    tag VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, alias)
+);
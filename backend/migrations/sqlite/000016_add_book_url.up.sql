@@ -0,0 +1,2 @@
+-- The address a book was saved from, if any
+ALTER TABLE books ADD COLUMN url TEXT NOT NULL DEFAULT '';
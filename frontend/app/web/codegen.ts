@@ -7,6 +7,10 @@ const config: CodegenConfig = {
   generates: {
     'src/lib/graphql/generated/': {
       preset: 'client',
+      presetConfig: {
+        // Writes persisted-documents.json, the backend's GRAPHQL_ALLOWLIST_FILE
+        persistedDocuments: true,
+      },
       plugins: [
         'typescript',
         'typescript-operations',